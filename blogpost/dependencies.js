@@ -2,6 +2,7 @@ document.addEventListener('DOMContentLoaded', () => {
     const form = document.getElementById('dependency-form');
     const generateBtn = document.getElementById('generate-btn');
     const verboseToggle = document.getElementById('verbose-toggle');
+    const enrichToggle = document.getElementById('enrich-toggle');
     const resultsContainer = document.getElementById('results-container');
     const loadingSpinner = document.getElementById('loading-spinner');
     const diagramOutput = document.getElementById('diagram-output');
@@ -24,9 +25,14 @@ document.addEventListener('DOMContentLoaded', () => {
 
         const identifier = document.getElementById('project-identifier').value;
         const sourceType = document.getElementById('source-type').value;
+        const enrich = enrichToggle.checked;
 
         try {
-            const depsResponse = await fetch(`/api/dependencies?source=${sourceType}&id=${identifier}`);
+            const depsParams = new URLSearchParams({ source: sourceType, id: identifier });
+            if (enrich) {
+                depsParams.set('enrich', 'true');
+            }
+            const depsResponse = await fetch(`/api/dependencies?${depsParams}`);
             
             if (!depsResponse.ok) {
                 const errorText = await depsResponse.text();
@@ -41,7 +47,8 @@ document.addEventListener('DOMContentLoaded', () => {
                 jsonOutput.style.display = 'block';
             }
 
-            const renderResponse = await fetch('/api/render', {
+            const renderURL = enrich ? '/api/render?nebraska=true&popups=true' : '/api/render';
+            const renderResponse = await fetch(renderURL, {
                 method: 'POST',
                 headers: {
                     'Content-Type': 'application/json'
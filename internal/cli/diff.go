@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+	dagtransform "github.com/matzehuels/stacktower/pkg/dag/transform"
+	pkgio "github.com/matzehuels/stacktower/pkg/io"
+)
+
+type diffOpts struct {
+	format string
+	output string
+	render renderOpts
+}
+
+func newDiffCmd() *cobra.Command {
+	opts := diffOpts{
+		format: "text",
+		render: renderOpts{
+			normalize: true,
+			width:     defaultWidth,
+			height:    defaultHeight,
+			style:     styleSimple,
+			diff:      true,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "diff <old.json> <new.json>",
+		Short: "Show what changed between two snapshots of a graph",
+		Long: `Compare two parsed graphs and list the packages and dependencies that were added or
+removed, version changes, and changed metadata. The tower and nodelink formats render
+both snapshots as one graph: added packages highlighted, changed ones marked, and
+removed ones ghosted.`,
+		Example: `  # Summary of an upgrade
+  stacktower diff before.json after.json
+
+  # Rendered tower of the upgrade
+  stacktower diff before.json after.json --format tower -o upgrade.svg`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDiff(cmd.Context(), args[0], args[1], &opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.format, "format", opts.format, "output format: text, json, tower or nodelink")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "output file (stdout if empty)")
+	cmd.Flags().StringVar(&opts.render.style, "style", opts.render.style, "visual style: simple or handdrawn (tower)")
+	cmd.Flags().Float64Var(&opts.render.width, "width", opts.render.width, "frame width (tower)")
+	cmd.Flags().Float64Var(&opts.render.height, "height", opts.render.height, "frame height (tower)")
+	cmd.Flags().BoolVar(&opts.render.showEdges, "edges", false, "show edges (tower)")
+	cmd.Flags().IntVar(&opts.render.orderTimeout, "ordering-timeout", 60, "timeout in seconds for optimal search")
+
+	return cmd
+}
+
+func runDiff(ctx context.Context, oldPath, newPath string, opts *diffOpts) error {
+	logger := loggerFromContext(ctx)
+
+	switch opts.format {
+	case "text", "json":
+	case "tower", "nodelink":
+		if err := validateStyle(opts.render.style); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid format: %s (must be 'text', 'json', 'tower' or 'nodelink')", opts.format)
+	}
+
+	before, err := pkgio.ImportJSON(oldPath)
+	if err != nil {
+		return err
+	}
+	after, err := pkgio.ImportJSON(newPath)
+	if err != nil {
+		return err
+	}
+	d := dag.Compare(before, after)
+	logger.Infof("%d added, %d removed, %d changed", len(d.AddedNodes), len(d.RemovedNodes), len(d.Changed))
+
+	if opts.format == "tower" || opts.format == "nodelink" {
+		g := dagtransform.Normalize(dag.Overlay(before, after, d))
+		opts.render.output = opts.output
+		return renderSingle(ctx, g, opts.format, &opts.render)
+	}
+
+	out, err := openOutput(opts.output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if opts.format == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(d)
+	}
+	return writeDiffReport(out, d, before, after)
+}
+
+func writeDiffReport(w io.Writer, d dag.Diff, before, after *dag.DAG) error {
+	if d.Empty() {
+		_, err := fmt.Fprintln(w, "No changes")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "%d added, %d removed, %d changed packages; %d added, %d removed dependencies\n",
+		len(d.AddedNodes), len(d.RemovedNodes), len(d.Changed), len(d.AddedEdges), len(d.RemovedEdges))
+
+	if len(d.AddedNodes)+len(d.RemovedNodes)+len(d.Changed) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "\tPACKAGE\tVERSION")
+	}
+	for _, id := range d.AddedNodes {
+		fmt.Fprintf(tw, "+\t%s\t%s\n", id, versionOf(after, id))
+	}
+	for _, id := range d.RemovedNodes {
+		fmt.Fprintf(tw, "-\t%s\t%s\n", id, versionOf(before, id))
+	}
+	for _, c := range d.Changed {
+		version := c.NewVersion
+		if c.VersionChanged() {
+			version = c.OldVersion + " → " + c.NewVersion
+		}
+		fmt.Fprintf(tw, "~\t%s\t%s\n", c.ID, version)
+		for _, m := range c.Meta {
+			fmt.Fprintf(tw, "\t  %s\t%s → %s\n", m.Key, diffValue(m.Old), diffValue(m.New))
+		}
+	}
+
+	if len(d.AddedEdges)+len(d.RemovedEdges) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "\tDEPENDENCY")
+	}
+	for _, e := range d.AddedEdges {
+		fmt.Fprintf(tw, "+\t%s → %s\n", e.From, e.To)
+	}
+	for _, e := range d.RemovedEdges {
+		fmt.Fprintf(tw, "-\t%s → %s\n", e.From, e.To)
+	}
+	return tw.Flush()
+}
+
+func versionOf(g *dag.DAG, id string) string {
+	if n, ok := g.Node(id); ok {
+		if v, _ := n.Meta["version"].(string); v != "" {
+			return v
+		}
+	}
+	return "-"
+}
+
+func diffValue(v any) string {
+	if v == nil {
+		return "(none)"
+	}
+	return formatValue(v)
+}
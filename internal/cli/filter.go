@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+	pkgio "github.com/matzehuels/stacktower/pkg/io"
+)
+
+type filterOpts struct {
+	output      string
+	ancestors   string
+	descendants string
+	drop        []string
+	dropWhere   []string
+	depth       int
+	collapse    []string
+	group       []string
+}
+
+func newFilterCmd() *cobra.Command {
+	opts := filterOpts{depth: -1}
+
+	cmd := &cobra.Command{
+		Use:   "filter <graph.json>",
+		Short: "Cut a graph down to the part you want to render",
+		Long: `Write a smaller graph: keep only a package's ancestors or descendants, drop packages by
+name or metadata, prune below a depth, collapse a package's subtree into it, or merge families of
+packages into composite blocks. Steps apply in that order. Dropped packages take whatever was reachable only through them along, so no new
+roots appear. The result is a regular graph file, ready for render.`,
+		Example: `  # Only what fastapi pulls in through starlette
+  stacktower filter fastapi.json --descendants starlette -o starlette.json
+
+  # Hide type stubs and archived packages, three levels deep
+  stacktower filter fastapi.json --drop 'types-*' --drop-where repo_archived=true --depth 3
+
+  # Show pydantic as a single block
+  stacktower filter fastapi.json --collapse pydantic | stacktower render /dev/stdin -t tower
+
+  # One block per npm scope and per sphinxcontrib-* family
+  stacktower filter docs.json --group scope --group prefix:sphinxcontrib-`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runFilter(cmd.Context(), args[0], &opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "output file (stdout if empty)")
+	cmd.Flags().StringVar(&opts.ancestors, "ancestors", "", "keep only this package and the packages that depend on it")
+	cmd.Flags().StringVar(&opts.descendants, "descendants", "", "keep only this package and its dependencies")
+	cmd.Flags().StringArrayVar(&opts.drop, "drop", nil, "drop packages whose name matches a glob pattern (repeatable)")
+	cmd.Flags().StringArrayVar(&opts.dropWhere, "drop-where", nil, "drop packages whose metadata matches KEY=GLOB (repeatable)")
+	cmd.Flags().IntVar(&opts.depth, "depth", opts.depth, "drop packages more than this many levels below a root (-1 for no limit)")
+	cmd.Flags().StringArrayVar(&opts.collapse, "collapse", nil, "fold a package's exclusive subtree into one block (repeatable)")
+	cmd.Flags().StringArrayVar(&opts.group, "group", nil, "merge packages into composite blocks by scope, repo, prefix:PREFIX or meta:KEY (repeatable)")
+
+	return cmd
+}
+
+func runFilter(ctx context.Context, input string, opts *filterOpts) error {
+	logger := loggerFromContext(ctx)
+
+	drop, err := dropPredicate(opts.drop, opts.dropWhere)
+	if err != nil {
+		return err
+	}
+
+	groups := make([]func(*dag.Node) string, 0, len(opts.group))
+	for _, by := range opts.group {
+		key, err := groupKey(by)
+		if err != nil {
+			return err
+		}
+		groups = append(groups, key)
+	}
+
+	g, err := pkgio.ImportJSON(input)
+	if err != nil {
+		return err
+	}
+	before := g.NodeCount()
+
+	for _, id := range append([]string{opts.ancestors, opts.descendants}, opts.collapse...) {
+		if _, ok := g.Node(id); id != "" && !ok {
+			return fmt.Errorf("package %s not found in %s", id, input)
+		}
+	}
+
+	if opts.ancestors != "" {
+		g = g.Subgraph(keepWith(opts.ancestors, g.Ancestors(opts.ancestors)))
+	}
+	if opts.descendants != "" {
+		g = g.Subgraph(keepWith(opts.descendants, g.Descendants(opts.descendants)))
+	}
+	if drop != nil {
+		g = g.Without(drop)
+	}
+	if opts.depth >= 0 {
+		g = g.Prune(opts.depth)
+	}
+	for _, id := range opts.collapse {
+		if _, ok := g.Node(id); !ok {
+			logger.Warnf("%s was filtered out before it could be collapsed", id)
+			continue
+		}
+		g = g.Collapse(id)
+	}
+	for _, key := range groups {
+		g = g.Group(key)
+	}
+	logger.Infof("Kept %d of %d packages, %d dependencies", g.NodeCount(), before, g.EdgeCount())
+
+	out, err := openOutput(opts.output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if err := pkgio.WriteJSON(g, out); err != nil {
+		return err
+	}
+	if opts.output != "" {
+		logger.Infof("Wrote graph to %s", opts.output)
+	}
+	return nil
+}
+
+func keepWith(id string, ids []string) func(*dag.Node) bool {
+	keep := make(map[string]bool, len(ids)+1)
+	keep[id] = true
+	for _, i := range ids {
+		keep[i] = true
+	}
+	return func(n *dag.Node) bool { return keep[n.ID] }
+}
+
+func groupKey(by string) (func(*dag.Node) string, error) {
+	kind, arg, _ := strings.Cut(by, ":")
+	switch {
+	case by == "scope":
+		return dag.ByScope, nil
+	case by == "repo":
+		return dag.ByRepo, nil
+	case kind == "prefix" && arg != "":
+		return dag.ByPrefix(arg), nil
+	case kind == "meta" && arg != "":
+		return dag.ByMeta(arg), nil
+	}
+	return nil, fmt.Errorf("invalid --group %q (want scope, repo, prefix:PREFIX or meta:KEY)", by)
+}
+
+// dropPredicate matches nodes by ID pattern or by KEY=GLOB against the
+// printed form of a metadata value. It is nil when nothing is dropped.
+func dropPredicate(patterns, where []string) (func(*dag.Node) bool, error) {
+	if len(patterns) == 0 && len(where) == 0 {
+		return nil, nil
+	}
+
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+	}
+	type cond struct{ key, glob string }
+	conds := make([]cond, 0, len(where))
+	for _, w := range where {
+		key, glob, ok := strings.Cut(w, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --drop-where %q (want KEY=GLOB)", w)
+		}
+		if _, err := path.Match(glob, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", glob, err)
+		}
+		conds = append(conds, cond{key, glob})
+	}
+
+	return func(n *dag.Node) bool {
+		for _, p := range patterns {
+			if ok, _ := path.Match(p, n.ID); ok {
+				return true
+			}
+		}
+		for _, c := range conds {
+			v, present := n.Meta[c.key]
+			if !present {
+				continue
+			}
+			if ok, _ := path.Match(c.glob, fmt.Sprint(v)); ok {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/matzehuels/stacktower/pkg/health"
+	pkgio "github.com/matzehuels/stacktower/pkg/io"
+)
+
+type healthOpts struct {
+	policy string
+	format string
+	output string
+}
+
+func newHealthCmd() *cobra.Command {
+	opts := healthOpts{format: "text"}
+
+	cmd := &cobra.Command{
+		Use:   "health <graph.json>",
+		Short: "Score the maintenance health of every package",
+		Long: `Grade each package from A to F by commit recency, release cadence, contributor
+concentration, issue responsiveness and archived state. Needs a graph parsed with --enrich.
+Signals marked ~ are approximated and those marked n/a had no data; see the usage docs.`,
+		Example: `  # Least healthy packages first
+  stacktower health fastapi.json
+
+  # Custom weights and thresholds, machine-readable
+  stacktower health fastapi.json --policy health.json --format json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runHealth(cmd.Context(), args[0], &opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.policy, "policy", "", "scoring policy file (JSON); defaults apply to omitted fields")
+	cmd.Flags().StringVar(&opts.format, "format", opts.format, "output format: text or json")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "output file (stdout if empty)")
+
+	return cmd
+}
+
+func loadHealthPolicy(path string) (health.Policy, error) {
+	if path == "" {
+		return health.DefaultPolicy(), nil
+	}
+	return health.LoadPolicy(path)
+}
+
+func runHealth(ctx context.Context, input string, opts *healthOpts) error {
+	logger := loggerFromContext(ctx)
+
+	if opts.format != "text" && opts.format != "json" {
+		return fmt.Errorf("invalid format: %s (must be 'text' or 'json')", opts.format)
+	}
+
+	policy, err := loadHealthPolicy(opts.policy)
+	if err != nil {
+		return err
+	}
+
+	g, err := pkgio.ImportJSON(input)
+	if err != nil {
+		return err
+	}
+	report := health.Assess(g, policy)
+
+	out, err := openOutput(opts.output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if opts.format == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+	} else {
+		err = writeHealthReport(out, report)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(report.Packages) == 0 {
+		logger.Warn("No package has repository metadata; parse with --enrich first")
+	}
+	return nil
+}
+
+var healthSignals = []health.Signal{
+	health.SignalCommits, health.SignalReleases, health.SignalContributors, health.SignalIssues,
+}
+
+func writeHealthReport(w io.Writer, r health.Report) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	counts := make(map[health.Grade]int)
+	for _, p := range r.Packages {
+		counts[p.Grade]++
+	}
+	fmt.Fprintf(tw, "%d packages scored, %d without metadata\n", len(r.Packages), len(r.Unscored))
+	for _, g := range health.Grades {
+		if counts[g] > 0 {
+			fmt.Fprintf(tw, "  %s\t%d\n", g, counts[g])
+		}
+	}
+
+	if len(r.Packages) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "PACKAGE\tSCORE\tGRADE\tSIGNALS")
+		for _, p := range r.Packages {
+			var signals []string
+			for _, s := range healthSignals {
+				switch v, ok := p.Signals[s]; {
+				case !ok:
+					signals = append(signals, fmt.Sprintf("%s=n/a", s))
+				case slices.Contains(p.Approximate, s):
+					signals = append(signals, fmt.Sprintf("%s=~%.2f", s, v))
+				default:
+					signals = append(signals, fmt.Sprintf("%s=%.2f", s, v))
+				}
+			}
+			if p.Archived {
+				signals = append(signals, "archived")
+			}
+			fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", p.Package, p.Score, p.Grade, strings.Join(signals, " "))
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	pkgio "github.com/matzehuels/stacktower/pkg/io"
+	"github.com/matzehuels/stacktower/pkg/libyear"
+)
+
+type libyearsOpts struct {
+	format string
+	output string
+}
+
+func newLibyearsCmd() *cobra.Command {
+	opts := libyearsOpts{format: "text"}
+
+	cmd := &cobra.Command{
+		Use:   "libyears <graph.json>",
+		Short: "Report how far each package trails its newest release",
+		Long: `Measure, per package, the libyears between its version and the newest release, and
+how long ago its version was released, from the release history recorded at parse time.
+Packages are listed stalest first, with the total across the graph.`,
+		Example: `  # Stalest packages first
+  stacktower libyears fastapi.json
+
+  # Machine-readable
+  stacktower libyears fastapi.json --format json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLibyears(cmd.Context(), args[0], &opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.format, "format", opts.format, "output format: text or json")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "output file (stdout if empty)")
+
+	return cmd
+}
+
+func runLibyears(ctx context.Context, input string, opts *libyearsOpts) error {
+	logger := loggerFromContext(ctx)
+
+	if opts.format != "text" && opts.format != "json" {
+		return fmt.Errorf("invalid format: %s (must be 'text' or 'json')", opts.format)
+	}
+
+	g, err := pkgio.ImportJSON(input)
+	if err != nil {
+		return err
+	}
+	report := libyear.Assess(g)
+
+	out, err := openOutput(opts.output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if opts.format == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+	} else {
+		err = writeLibyearsReport(out, report)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(report.Packages) == 0 {
+		logger.Warn("No package has a release history; re-parse the graph with --releases")
+	}
+	return nil
+}
+
+func writeLibyearsReport(w io.Writer, r libyear.Report) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	behind := 0
+	for _, p := range r.Packages {
+		if p.Libyears > 0 {
+			behind++
+		}
+	}
+	fmt.Fprintf(tw, "%.1f libyears across %d packages (%d behind), %d without release history\n",
+		r.Libyears, len(r.Packages), behind, len(r.Unknown))
+
+	if len(r.Packages) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "PACKAGE\tVERSION\tRELEASED\tLATEST\tLIBYEARS\tAGE")
+		for _, p := range r.Packages {
+			latest := p.Latest
+			if latest == p.Version {
+				latest = "-"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%.1f\t%.1fy\n", p.Package, p.Version, p.Released, latest, p.Libyears, p.AgeYears)
+		}
+	}
+	return tw.Flush()
+}
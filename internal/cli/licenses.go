@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	pkgio "github.com/matzehuels/stacktower/pkg/io"
+	"github.com/matzehuels/stacktower/pkg/license"
+)
+
+type licensesOpts struct {
+	policy string
+	format string
+	output string
+}
+
+func newLicensesCmd() *cobra.Command {
+	opts := licensesOpts{format: "text"}
+
+	cmd := &cobra.Command{
+		Use:   "licenses <graph.json>",
+		Short: "Report package licenses and policy violations",
+		Long: `Normalize every package's license to an SPDX expression, group them by family,
+and check them against a policy file. Exits non-zero when any package violates the policy.`,
+		Example: `  # Inventory only
+  stacktower licenses fastapi.json
+
+  # Enforce a policy in CI
+  stacktower licenses fastapi.json --policy licenses.json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLicenses(cmd.Context(), args[0], &opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.policy, "policy", "", "policy file (JSON with allow, deny, allow_unknown)")
+	cmd.Flags().StringVar(&opts.format, "format", opts.format, "output format: text or json")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "output file (stdout if empty)")
+
+	return cmd
+}
+
+func runLicenses(ctx context.Context, input string, opts *licensesOpts) error {
+	logger := loggerFromContext(ctx)
+
+	if opts.format != "text" && opts.format != "json" {
+		return fmt.Errorf("invalid format: %s (must be 'text' or 'json')", opts.format)
+	}
+
+	policy := license.Policy{AllowUnknown: true}
+	if opts.policy != "" {
+		p, err := license.LoadPolicy(opts.policy)
+		if err != nil {
+			return err
+		}
+		policy = p
+	}
+
+	g, err := pkgio.ImportJSON(input)
+	if err != nil {
+		return err
+	}
+	report := license.Check(g, policy)
+
+	out, err := openOutput(opts.output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if opts.format == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+	} else {
+		err = writeLicenseReport(out, report)
+	}
+	if err != nil {
+		return err
+	}
+
+	if n := len(report.Violations); n > 0 {
+		return fmt.Errorf("%d license policy violation(s)", n)
+	}
+	logger.Infof("Checked %d packages, no violations", len(report.Packages))
+	return nil
+}
+
+func writeLicenseReport(w io.Writer, r license.Report) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	counts := make(map[license.Family]int)
+	for _, p := range r.Packages {
+		counts[p.Family]++
+	}
+	fmt.Fprintf(tw, "%d packages\n", len(r.Packages))
+	for _, f := range license.Families {
+		if counts[f] > 0 {
+			fmt.Fprintf(tw, "  %s\t%d\n", f, counts[f])
+		}
+	}
+
+	if len(r.Violations) > 0 {
+		fmt.Fprintf(tw, "\n%d violations\n", len(r.Violations))
+		for _, v := range r.Violations {
+			lic := v.License
+			if lic == "" {
+				lic = "(none)"
+			}
+			fmt.Fprintf(tw, "  %s\t%s\t%s\n", v.Package, lic, v.Reason)
+			fmt.Fprintf(tw, "    via %s\n", strings.Join(v.Path, " → "))
+		}
+	}
+	return tw.Flush()
+}
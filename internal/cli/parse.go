@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/matzehuels/stacktower/pkg/health"
 	pkgio "github.com/matzehuels/stacktower/pkg/io"
 	"github.com/matzehuels/stacktower/pkg/source"
 	"github.com/matzehuels/stacktower/pkg/source/javascript"
@@ -19,11 +21,22 @@ import (
 )
 
 type parseOpts struct {
-	maxDepth int
-	maxNodes int
-	enrich   bool
-	refresh  bool
-	output   string
+	maxDepth      int
+	maxNodes      int
+	enrich        bool
+	vulns         bool
+	osvDB         string
+	downloads     bool
+	downloadsFile string
+	scorecard     bool
+	scorecardFile string
+	releases      bool
+	health        bool
+	healthPolicy  string
+	plugins       []string
+	prefer        []string
+	refresh       bool
+	output        string
 }
 
 type parserFactory func() (source.Parser, error)
@@ -40,6 +53,17 @@ func newParseCmd() *cobra.Command {
 	cmd.PersistentFlags().IntVar(&opts.maxDepth, "max-depth", opts.maxDepth, "maximum dependency depth")
 	cmd.PersistentFlags().IntVar(&opts.maxNodes, "max-nodes", opts.maxNodes, "maximum nodes to fetch")
 	cmd.PersistentFlags().BoolVar(&opts.enrich, "enrich", false, "enrich with repository metadata")
+	cmd.PersistentFlags().BoolVar(&opts.vulns, "vulns", false, "annotate packages with known vulnerabilities from OSV")
+	cmd.PersistentFlags().StringVar(&opts.osvDB, "osv-db", "", "local OSV database dump (directory or zip) to use instead of the API (implies --vulns)")
+	cmd.PersistentFlags().BoolVar(&opts.downloads, "downloads", false, "add registry download counts (npm, PyPI)")
+	cmd.PersistentFlags().StringVar(&opts.downloadsFile, "pypi-downloads", "", "PyPI download counts exported from BigQuery (CSV or NDJSON), instead of pypistats (implies --downloads)")
+	cmd.PersistentFlags().BoolVar(&opts.scorecard, "scorecard", false, "add OpenSSF Scorecard results (GitHub and GitLab repositories)")
+	cmd.PersistentFlags().StringVar(&opts.scorecardFile, "scorecard-file", "", "Scorecard results stored locally (JSON or NDJSON from 'scorecard --format json'), instead of the API (implies --scorecard)")
+	cmd.PersistentFlags().BoolVar(&opts.releases, "releases", false, "record each package's release history (for libyears and release cadence)")
+	cmd.PersistentFlags().BoolVar(&opts.health, "health", false, "score maintenance health into each package's metadata (implies --enrich and --releases)")
+	cmd.PersistentFlags().StringVar(&opts.healthPolicy, "health-policy", "", "scoring policy for --health (JSON; implies --health)")
+	cmd.PersistentFlags().StringArrayVar(&opts.plugins, "plugin", nil, "external metadata provider: stacktower-provider-NAME on PATH, or a path to an executable (repeatable)")
+	cmd.PersistentFlags().StringArrayVar(&opts.prefer, "prefer", nil, "provider precedence for a metadata key, e.g. repo_stars=gitlab,github or '*=github' (repeatable)")
 	cmd.PersistentFlags().BoolVar(&opts.refresh, "refresh", false, "bypass cache")
 	cmd.PersistentFlags().StringVarP(&opts.output, "output", "o", "", "output file (stdout if empty)")
 
@@ -76,17 +100,63 @@ func runParse(ctx context.Context, p source.Parser, pkg string, opts *parseOpts)
 	logger := loggerFromContext(ctx)
 	logger.Infof("Parsing %s dependencies", pkg)
 
-	providers, err := buildMetadataProviders(opts.enrich)
+	precedence, err := parsePrecedence(opts.prefer)
+	if err != nil {
+		return err
+	}
+
+	scoreHealth := opts.health || opts.healthPolicy != ""
+	policy, err := loadHealthPolicy(opts.healthPolicy)
+	if err != nil {
+		return err
+	}
+
+	providers, err := buildMetadataProviders(opts.enrich || scoreHealth)
 	if err != nil {
 		logger.Warnf("Metadata enrichment disabled: %v", err)
 	} else if len(providers) > 0 {
 		logger.Debugf("Metadata enrichment enabled (%d providers)", len(providers))
 	}
 
+	if opts.vulns || opts.osvDB != "" {
+		osv, err := buildOSVProvider(opts.osvDB)
+		if err != nil {
+			return err
+		}
+		providers = append(providers, osv)
+	}
+
+	if opts.downloads || opts.downloadsFile != "" {
+		dl, err := metadata.NewDownloads(source.DefaultCacheTTL, opts.downloadsFile)
+		if err != nil {
+			return fmt.Errorf("downloads: %w", err)
+		}
+		providers = append(providers, dl)
+	}
+
+	if opts.scorecard || opts.scorecardFile != "" {
+		sc, err := buildScorecardProvider(opts.scorecardFile)
+		if err != nil {
+			return fmt.Errorf("scorecard: %w", err)
+		}
+		providers = append(providers, sc)
+	}
+
+	for _, name := range opts.plugins {
+		pl, err := metadata.NewPlugin(name)
+		if err != nil {
+			return err
+		}
+		defer pl.Close()
+		providers = append(providers, pl)
+	}
+
 	srcOpts := source.Options{
 		MaxDepth:          opts.maxDepth,
 		MaxNodes:          opts.maxNodes,
 		MetadataProviders: providers,
+		Precedence:        precedence,
+		Releases:          opts.releases || scoreHealth,
 		Refresh:           opts.refresh,
 		CacheTTL:          source.DefaultCacheTTL,
 		Logger:            func(msg string, args ...any) { logger.Warnf(msg, args...) },
@@ -100,6 +170,10 @@ func runParse(ctx context.Context, p source.Parser, pkg string, opts *parseOpts)
 	}
 	prog.done(fmt.Sprintf("Resolved %d packages with %d dependencies", g.NodeCount(), g.EdgeCount()))
 
+	if scoreHealth {
+		logger.Infof("Scored the health of %d packages", health.Annotate(g, policy))
+	}
+
 	out, err := openOutput(opts.output)
 	if err != nil {
 		return err
@@ -116,6 +190,32 @@ func runParse(ctx context.Context, p source.Parser, pkg string, opts *parseOpts)
 	return nil
 }
 
+// parsePrecedence reads --prefer rules of the form key=provider,provider.
+func parsePrecedence(rules []string) (source.Precedence, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	p := make(source.Precedence, len(rules))
+	for _, rule := range rules {
+		key, list, ok := strings.Cut(rule, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --prefer %q (want key=provider,...)", rule)
+		}
+		var names []string
+		for name := range strings.SplitSeq(list, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+		if len(names) == 0 {
+			return nil, fmt.Errorf("invalid --prefer %q: no providers", rule)
+		}
+		p[key] = names
+	}
+	return p, nil
+}
+
 func buildMetadataProviders(enrich bool) ([]source.MetadataProvider, error) {
 	if !enrich {
 		return nil, nil
@@ -136,13 +236,45 @@ func buildMetadataProviders(enrich bool) ([]source.MetadataProvider, error) {
 		}
 		providers = append(providers, gl)
 	}
+	if tok := os.Getenv("BITBUCKET_TOKEN"); tok != "" {
+		bb, err := metadata.NewBitbucket(tok, source.DefaultCacheTTL)
+		if err != nil {
+			return nil, fmt.Errorf("bitbucket: %w", err)
+		}
+		providers = append(providers, bb)
+	}
+	if base, tok := os.Getenv("GITEA_URL"), os.Getenv("GITEA_TOKEN"); base != "" || tok != "" {
+		gt, err := metadata.NewGitea(base, tok, source.DefaultCacheTTL)
+		if err != nil {
+			return nil, fmt.Errorf("gitea: %w", err)
+		}
+		providers = append(providers, gt)
+	}
 
 	if len(providers) == 0 {
-		return nil, fmt.Errorf("no tokens found (GITHUB_TOKEN, GITLAB_TOKEN)")
+		return nil, fmt.Errorf("no tokens found (GITHUB_TOKEN, GITLAB_TOKEN, BITBUCKET_TOKEN, GITEA_TOKEN)")
 	}
 	return providers, nil
 }
 
+func buildOSVProvider(dbPath string) (source.MetadataProvider, error) {
+	if dbPath == "" {
+		return metadata.NewOSV(source.DefaultCacheTTL)
+	}
+	osv, err := metadata.NewOSVFromDB(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("osv: %w", err)
+	}
+	return osv, nil
+}
+
+func buildScorecardProvider(path string) (source.MetadataProvider, error) {
+	if path == "" {
+		return metadata.NewScorecard(source.DefaultCacheTTL)
+	}
+	return metadata.NewScorecardFromFile(path)
+}
+
 type nopCloser struct{ io.Writer }
 
 func (nopCloser) Close() error { return nil }
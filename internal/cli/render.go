@@ -44,6 +44,12 @@ type renderOpts struct {
 	nebraska     bool
 	popups       bool
 	topDown      bool
+	colorBy      string
+	healthPolicy string
+	widthBy      string
+	layering     string
+	layerWidth   int
+	diff         bool // graph is a dag.Overlay; color blocks by change
 }
 
 func newRenderCmd() *cobra.Command {
@@ -64,6 +70,15 @@ func newRenderCmd() *cobra.Command {
 			if err := validateStyle(opts.style); err != nil {
 				return err
 			}
+			if _, err := colorScaleFor(&opts); err != nil {
+				return err
+			}
+			if _, err := widthWeightFor(opts.widthBy); err != nil {
+				return err
+			}
+			if _, err := layererFor(&opts); err != nil {
+				return err
+			}
 			return runRender(cmd.Context(), args[0], &opts)
 		},
 	}
@@ -72,6 +87,8 @@ func newRenderCmd() *cobra.Command {
 	cmd.Flags().StringVarP(&vizTypesStr, "type", "t", "", "visualization types: nodelink, tower (comma-separated)")
 	cmd.Flags().BoolVar(&opts.detailed, "detailed", false, "show detailed information (nodelink)")
 	cmd.Flags().BoolVar(&opts.normalize, "normalize", opts.normalize, "apply normalization pipeline")
+	cmd.Flags().StringVar(&opts.layering, "layering", "", "row assignment: longest-path (default), longest-path-sinks, coffman-graham, network-simplex")
+	cmd.Flags().IntVar(&opts.layerWidth, "layer-width", 0, "maximum packages per row for --layering coffman-graham (0 for automatic)")
 	cmd.Flags().Float64Var(&opts.width, "width", opts.width, "frame width (tower)")
 	cmd.Flags().Float64Var(&opts.height, "height", opts.height, "frame height (tower)")
 	cmd.Flags().BoolVar(&opts.showEdges, "edges", false, "show edges (tower)")
@@ -83,6 +100,9 @@ func newRenderCmd() *cobra.Command {
 	cmd.Flags().BoolVar(&opts.nebraska, "nebraska", false, "show Nebraska guy ranking (handdrawn)")
 	cmd.Flags().BoolVar(&opts.popups, "popups", false, "show hover popups (handdrawn)")
 	cmd.Flags().BoolVar(&opts.topDown, "top-down", false, "use top-down width flow (roots get equal width)")
+	cmd.Flags().StringVar(&opts.colorBy, "color-by", "", "color blocks by: license, downloads, health, scorecard, libyears, age (tower)")
+	cmd.Flags().StringVar(&opts.healthPolicy, "health-policy", "", "health scoring policy file for --color-by health (JSON)")
+	cmd.Flags().StringVar(&opts.widthBy, "width-by", "", "scale block widths by: downloads (tower)")
 
 	return cmd
 }
@@ -94,6 +114,60 @@ func parseVizTypes(s string) []string {
 	return strings.Split(s, ",")
 }
 
+func colorScaleFor(opts *renderOpts) (tower.ColorScale, error) {
+	if opts.diff {
+		return tower.DiffColors{}, nil
+	}
+	switch opts.colorBy {
+	case "":
+		return nil, nil
+	case "license":
+		return tower.LicenseColors{}, nil
+	case "downloads":
+		return tower.DownloadColors{}, nil
+	case "scorecard":
+		return tower.ScorecardColors{}, nil
+	case "libyears":
+		return tower.LibyearColors{}, nil
+	case "age":
+		return tower.LibyearColors{Age: true}, nil
+	case "health":
+		policy, err := loadHealthPolicy(opts.healthPolicy)
+		if err != nil {
+			return nil, err
+		}
+		return tower.HealthColors{Policy: policy}, nil
+	default:
+		return nil, fmt.Errorf("invalid color scale: %s (must be 'license', 'downloads', 'health', 'scorecard', 'libyears' or 'age')", opts.colorBy)
+	}
+}
+
+func layererFor(opts *renderOpts) (dagtransform.Layerer, error) {
+	switch opts.layering {
+	case "", "longest-path":
+		return dagtransform.LongestPath{}, nil
+	case "longest-path-sinks":
+		return dagtransform.LongestPathFromSinks{}, nil
+	case "coffman-graham":
+		return dagtransform.CoffmanGraham{Width: opts.layerWidth}, nil
+	case "network-simplex":
+		return dagtransform.NetworkSimplex{}, nil
+	default:
+		return nil, fmt.Errorf("invalid layering: %s (must be 'longest-path', 'longest-path-sinks', 'coffman-graham' or 'network-simplex')", opts.layering)
+	}
+}
+
+func widthWeightFor(name string) (tower.WidthWeight, error) {
+	switch name {
+	case "":
+		return nil, nil
+	case "downloads":
+		return tower.DownloadWeight, nil
+	default:
+		return nil, fmt.Errorf("invalid width scale: %s (must be 'downloads')", name)
+	}
+}
+
 func validateStyle(s string) error {
 	if s != styleSimple && s != styleHanddrawn {
 		return fmt.Errorf("invalid style: %s (must be 'simple' or 'handdrawn')", s)
@@ -111,12 +185,6 @@ func runRender(ctx context.Context, input string, opts *renderOpts) error {
 	}
 	logger.Infof("Loaded graph: %d nodes, %d edges", g.NodeCount(), g.EdgeCount())
 
-	if opts.normalize {
-		before := g.NodeCount()
-		g = dagtransform.Normalize(g)
-		logger.Infof("Normalized: %d nodes (%+d), %d edges", g.NodeCount(), g.NodeCount()-before, g.EdgeCount())
-	}
-
 	if len(opts.vizTypes) == 1 {
 		return renderSingle(ctx, g, opts.vizTypes[0], opts)
 	}
@@ -186,6 +254,11 @@ func renderAndWrite(ctx context.Context, g *dag.DAG, vizType, basePath string, o
 }
 
 func renderGraph(ctx context.Context, g *dag.DAG, vizType string, opts *renderOpts) ([]byte, error) {
+	if opts.normalize {
+		// Normalization changes the graph in place; each visualization
+		// normalizes its own copy.
+		g = g.Subgraph(func(*dag.Node) bool { return true })
+	}
 	switch vizType {
 	case "nodelink":
 		return renderNodeLink(ctx, g, opts)
@@ -199,6 +272,11 @@ func renderGraph(ctx context.Context, g *dag.DAG, vizType string, opts *renderOp
 func renderNodeLink(ctx context.Context, g *dag.DAG, opts *renderOpts) ([]byte, error) {
 	logger := loggerFromContext(ctx)
 	logger.Info("Generating node-link diagram")
+	if opts.normalize {
+		before := g.NodeCount()
+		layerer, _ := layererFor(opts)
+		logNormalized(logger, dagtransform.NormalizeWith(g, layerer), before)
+	}
 	dot := nodelink.ToDOT(g, nodelink.Options{Detailed: opts.detailed})
 	return nodelink.RenderSVG(dot)
 }
@@ -217,7 +295,11 @@ func renderTower(ctx context.Context, g *dag.DAG, opts *renderOpts) ([]byte, err
 		return nil, err
 	}
 
+	before := g.NodeCount()
 	layout := tower.Build(g, opts.width, opts.height, layoutOpts...)
+	if opts.normalize {
+		logNormalized(logger, g, before)
+	}
 	logger.Debugf("Layout computed: %d blocks", len(layout.Blocks))
 
 	if opts.merge {
@@ -234,9 +316,21 @@ func renderTower(ctx context.Context, g *dag.DAG, opts *renderOpts) ([]byte, err
 	return tower.RenderSVG(layout, renderOpts...), nil
 }
 
+func logNormalized(logger *log.Logger, g *dag.DAG, before int) {
+	logger.Infof("Normalized: %d nodes (%+d), %d edges", g.NodeCount(), g.NodeCount()-before, g.EdgeCount())
+	if back := g.BackEdges(); len(back) > 0 {
+		logger.Warnf("Broke dependency cycles by setting aside %d edges; they are drawn as back edges", len(back))
+	}
+}
+
 func buildLayoutOpts(ctx context.Context, opts *renderOpts) ([]tower.Option, error) {
 	var layoutOpts []tower.Option
 
+	if opts.normalize {
+		layerer, _ := layererFor(opts)
+		layoutOpts = append(layoutOpts, tower.WithLayering(layerer))
+	}
+
 	switch opts.ordering {
 	case "barycentric":
 	case "optimal", "":
@@ -250,6 +344,9 @@ func buildLayoutOpts(ctx context.Context, opts *renderOpts) ([]tower.Option, err
 		loggerFromContext(ctx).Debug("Using top-down width flow")
 		layoutOpts = append(layoutOpts, tower.WithTopDownWidths())
 	}
+	if weight, _ := widthWeightFor(opts.widthBy); weight != nil {
+		layoutOpts = append(layoutOpts, tower.WithWidthWeights(weight))
+	}
 
 	return layoutOpts, nil
 }
@@ -337,6 +434,9 @@ func buildRenderOpts(g *dag.DAG, opts *renderOpts) []tower.RenderOption {
 	if opts.merge {
 		result = append(result, tower.WithMerged())
 	}
+	if scale, _ := colorScaleFor(opts); scale != nil {
+		result = append(result, tower.WithColorScale(scale))
+	}
 	if opts.style == styleHanddrawn {
 		result = append(result, tower.WithStyle(handdrawn.New(defaultSeed)))
 		if opts.nebraska {
@@ -45,6 +45,14 @@ func Execute() error {
 
 	root.AddCommand(newParseCmd())
 	root.AddCommand(newRenderCmd())
+	root.AddCommand(newLicensesCmd())
+	root.AddCommand(newHealthCmd())
+	root.AddCommand(newLibyearsCmd())
+	root.AddCommand(newSourcesCmd())
+	root.AddCommand(newDiffCmd())
+	root.AddCommand(newWhyCmd())
+	root.AddCommand(newFilterCmd())
+	root.AddCommand(newStatsCmd())
 	root.AddCommand(newPQTreeCmd())
 	root.AddCommand(newServerCmd())
 
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"strconv"
 
 	"github.com/matzehuels/stacktower/pkg/dag"
 	pkgio "github.com/matzehuels/stacktower/pkg/io"
@@ -35,7 +36,13 @@ func newServerCmd() *cobra.Command {
 }
 
 func runServer(ctx context.Context) error {
-	http.Handle("/api/dependencies", dependenciesHandler(ctx))
+	// Enrichment uses the server's own forge tokens; clients only opt in.
+	providers, err := buildMetadataProviders(true)
+	if err != nil {
+		log.Printf("Metadata enrichment unavailable: %v", err)
+	}
+
+	http.Handle("/api/dependencies", dependenciesHandler(providers))
 	http.HandleFunc("/api/render", renderHandler)
 
 	// Redirect root to dependencies.html
@@ -95,8 +102,7 @@ func renderHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	cmd := exec.Command(
-		executable,
+	args := []string{
 		"render",
 		tmpfile.Name(),
 		"-t", "tower",
@@ -107,7 +113,15 @@ func renderHandler(w http.ResponseWriter, r *http.Request) {
 		"--merge",
 		"--randomize",
 		"-o", outputFile,
-	)
+	}
+	if queryBool(r, "nebraska") {
+		args = append(args, "--nebraska")
+	}
+	if queryBool(r, "popups") {
+		args = append(args, "--popups")
+	}
+
+	cmd := exec.Command(executable, args...)
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 	if err := cmd.Run(); err != nil {
@@ -136,7 +150,7 @@ var parserFactories = map[string]func() (source.Parser, error){
 	// "github" would need a different handling as it's not a simple package parser
 }
 
-func dependenciesHandler(ctx context.Context) http.Handler {
+func dependenciesHandler(providers []source.MetadataProvider) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		sourceType := r.URL.Query().Get("source")
 		pkgName := r.URL.Query().Get("id")
@@ -146,6 +160,12 @@ func dependenciesHandler(ctx context.Context) http.Handler {
 			return
 		}
 
+		enrich := queryBool(r, "enrich")
+		if enrich && len(providers) == 0 {
+			http.Error(w, "Metadata enrichment is not configured on this server", http.StatusServiceUnavailable)
+			return
+		}
+
 		factory, ok := parserFactories[sourceType]
 		if !ok {
 			http.Error(w, fmt.Sprintf("Source '%s' not supported", sourceType), http.StatusBadRequest)
@@ -159,9 +179,9 @@ func dependenciesHandler(ctx context.Context) http.Handler {
 		}
 
 		// Simplified opts for now
-		opts := &parseOpts{maxDepth: 10, maxNodes: 500}
+		opts := &parseOpts{maxDepth: 10, maxNodes: 500, enrich: enrich}
 
-		graph, err := runParseForServer(r.Context(), p, pkgName, opts)
+		graph, err := runParseForServer(r.Context(), p, pkgName, opts, providers)
 		if err != nil {
 			http.Error(w, fmt.Sprintf("Error parsing dependencies: %v", err), http.StatusInternalServerError)
 			return
@@ -176,12 +196,13 @@ func dependenciesHandler(ctx context.Context) http.Handler {
 	})
 }
 
-func runParseForServer(ctx context.Context, p source.Parser, pkg string, opts *parseOpts) (*dag.DAG, error) {
+func runParseForServer(ctx context.Context, p source.Parser, pkg string, opts *parseOpts, providers []source.MetadataProvider) (*dag.DAG, error) {
 	// This function is an adaptation of runParse from parse.go
 	// We can't use the logger from the command context here easily, so we use a default one for now.
 
-	// No metadata providers for now to keep it simple
-	var providers []source.MetadataProvider
+	if !opts.enrich {
+		providers = nil
+	}
 
 	srcOpts := source.Options{
 		MaxDepth:          opts.maxDepth,
@@ -198,3 +219,8 @@ func runParseForServer(ctx context.Context, p source.Parser, pkg string, opts *p
 
 	return g, nil
 }
+
+func queryBool(r *http.Request, key string) bool {
+	v, _ := strconv.ParseBool(r.URL.Query().Get(key))
+	return v
+}
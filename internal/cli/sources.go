@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	pkgio "github.com/matzehuels/stacktower/pkg/io"
+	"github.com/matzehuels/stacktower/pkg/source"
+)
+
+const maxValueWidth = 40
+
+type sourcesOpts struct {
+	format string
+	output string
+}
+
+func newSourcesCmd() *cobra.Command {
+	opts := sourcesOpts{format: "text"}
+
+	cmd := &cobra.Command{
+		Use:   "sources <graph.json>",
+		Short: "Show which provider supplied each metadata value",
+		Long: `List, per package, the provider each metadata value came from, the providers whose
+conflicting value lost under the precedence rules (see parse --prefer), and the providers
+that failed. Needs a graph parsed with at least one metadata provider.`,
+		Example: `  # Per-provider totals, then every package
+  stacktower sources fastapi.json
+
+  # Machine-readable
+  stacktower sources fastapi.json --format json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSources(cmd.Context(), args[0], &opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.format, "format", opts.format, "output format: text or json")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "output file (stdout if empty)")
+
+	return cmd
+}
+
+func runSources(ctx context.Context, input string, opts *sourcesOpts) error {
+	logger := loggerFromContext(ctx)
+
+	if opts.format != "text" && opts.format != "json" {
+		return fmt.Errorf("invalid format: %s (must be 'text' or 'json')", opts.format)
+	}
+
+	g, err := pkgio.ImportJSON(input)
+	if err != nil {
+		return err
+	}
+	report := source.Provenance(g)
+
+	out, err := openOutput(opts.output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if opts.format == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+	} else {
+		err = writeSourcesReport(out, report)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(report.Packages) == 0 {
+		logger.Warn("No provenance recorded; parse with --enrich, --vulns, --downloads or --scorecard first")
+	}
+	return nil
+}
+
+func writeSourcesReport(w io.Writer, r source.ProvenanceReport) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "PROVIDER\tPACKAGES\tVALUES\tFAILURES")
+	for _, p := range r.Providers {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\n", p.Name, p.Packages, p.Values, p.Failures)
+	}
+
+	for _, p := range r.Packages {
+		fmt.Fprintf(tw, "\n%s\n", p.Package)
+		for _, f := range p.Fields {
+			note := ""
+			if len(f.Overridden) > 0 {
+				note = "over " + strings.Join(f.Overridden, ", ")
+			}
+			fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", f.Key, formatValue(f.Value), f.Source, note)
+		}
+		for _, name := range slices.Sorted(maps.Keys(p.Failed)) {
+			fmt.Fprintf(tw, "  failed\t%s\t%s\t\n", p.Failed[name], name)
+		}
+	}
+	return tw.Flush()
+}
+
+func formatValue(v any) string {
+	var s string
+	switch val := v.(type) {
+	case string:
+		s = val
+	default:
+		data, _ := json.Marshal(val)
+		s = string(data)
+	}
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > maxValueWidth {
+		s = string(r[:maxValueWidth-1]) + "…"
+	}
+	return s
+}
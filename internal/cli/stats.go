@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	pkgio "github.com/matzehuels/stacktower/pkg/io"
+	"github.com/matzehuels/stacktower/pkg/stats"
+)
+
+type statsOpts struct {
+	format string
+	output string
+	top    int
+}
+
+func newStatsCmd() *cobra.Command {
+	opts := statsOpts{format: "text", top: 10}
+
+	cmd := &cobra.Command{
+		Use:   "stats <graph.json>",
+		Short: "Report structural metrics of a dependency graph",
+		Long: `Measure the shape of a dependency graph: its size, depth and width per row, how fan-in and
+fan-out are distributed, and which packages matter most to it. Packages are ranked by how many
+others depend on them transitively, by betweenness centrality, and as single points of failure:
+packages every path to some others runs through, so that removing one cuts those off.`,
+		Example: `  # Summary with the top ten of each ranking
+  stacktower stats fastapi.json
+
+  # Every ranked package, machine-readable
+  stacktower stats fastapi.json --top 0 --format json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runStats(args[0], &opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.format, "format", opts.format, "output format: text or json")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "output file (stdout if empty)")
+	cmd.Flags().IntVar(&opts.top, "top", opts.top, "packages per ranking (0 for all)")
+
+	return cmd
+}
+
+func runStats(input string, opts *statsOpts) error {
+	if opts.format != "text" && opts.format != "json" {
+		return fmt.Errorf("invalid format: %s (must be 'text' or 'json')", opts.format)
+	}
+
+	g, err := pkgio.ImportJSON(input)
+	if err != nil {
+		return err
+	}
+	report := stats.Analyze(g, opts.top)
+
+	out, err := openOutput(opts.output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if opts.format == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	return writeStatsReport(out, report)
+}
+
+func writeStatsReport(w io.Writer, r stats.Report) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "%d packages, %d dependencies, %d roots, %d leaves\n", r.Nodes, r.Edges, r.Roots, r.Leaves)
+	fmt.Fprintf(tw, "Depth %d, widths per row: %s\n", r.Depth, joinInts(r.Widths))
+
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "DEGREE\tMEAN\tMEDIAN\tP90\tMAX\tHISTOGRAM")
+	writeDistribution(tw, "fan-in", r.FanIn)
+	writeDistribution(tw, "fan-out", r.FanOut)
+
+	if len(r.MostDependedUpon) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "MOST DEPENDED UPON\tTRANSITIVE\tDIRECT")
+		for _, d := range r.MostDependedUpon {
+			fmt.Fprintf(tw, "%s\t%d\t%d\n", d.Package, d.Transitive, d.Direct)
+		}
+	}
+	if len(r.Betweenness) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "BETWEENNESS\tSCORE")
+		for _, c := range r.Betweenness {
+			fmt.Fprintf(tw, "%s\t%.4f\n", c.Package, c.Score)
+		}
+	}
+	if len(r.SinglePointsOfFailure) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "SINGLE POINT OF FAILURE\tCUTS OFF\tSHARE")
+		for _, d := range r.SinglePointsOfFailure {
+			fmt.Fprintf(tw, "%s\t%d\t%.1f%%\n", d.Package, d.Cut, 100*d.Share)
+		}
+	}
+	return tw.Flush()
+}
+
+func writeDistribution(w io.Writer, name string, d stats.Distribution) {
+	var hist []string
+	for _, deg := range slices.Sorted(maps.Keys(d.Histogram)) {
+		hist = append(hist, fmt.Sprintf("%d:%d", deg, d.Histogram[deg]))
+	}
+	fmt.Fprintf(w, "%s\t%.2f\t%d\t%d\t%d\t%s\n", name, d.Mean, d.Median, d.P90, d.Max, strings.Join(hist, " "))
+}
+
+func joinInts(values []int) string {
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = fmt.Sprint(v)
+	}
+	return strings.Join(s, " ")
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	pkgio "github.com/matzehuels/stacktower/pkg/io"
+)
+
+type whyOpts struct {
+	format   string
+	output   string
+	shortest int
+}
+
+type whyReport struct {
+	Package string     `json:"package"`
+	Paths   [][]string `json:"paths"`
+}
+
+func newWhyCmd() *cobra.Command {
+	opts := whyOpts{format: "text", shortest: 10}
+
+	cmd := &cobra.Command{
+		Use:   "why <graph.json> <package>",
+		Short: "Show the dependency paths that pull a package in",
+		Long: `List the paths from the graph's roots down to a package, shortest first, to explain why
+it is in the tree. Works on raw and normalized graphs alike: paths name real packages only.`,
+		Example: `  # The ten shortest paths to idna
+  stacktower why fastapi.json idna
+
+  # Only the three shortest
+  stacktower why fastapi.json idna -k 3
+
+  # Every path; this can take long on large, densely connected graphs
+  stacktower why fastapi.json idna -k 0`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWhy(cmd.Context(), args[0], args[1], &opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.format, "format", opts.format, "output format: text or json")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "output file (stdout if empty)")
+	cmd.Flags().IntVarP(&opts.shortest, "shortest", "k", opts.shortest, "list only the k shortest paths (0 for all)")
+
+	return cmd
+}
+
+func runWhy(ctx context.Context, input, pkg string, opts *whyOpts) error {
+	logger := loggerFromContext(ctx)
+
+	if opts.format != "text" && opts.format != "json" {
+		return fmt.Errorf("invalid format: %s (must be 'text' or 'json')", opts.format)
+	}
+
+	g, err := pkgio.ImportJSON(input)
+	if err != nil {
+		return err
+	}
+	if _, ok := g.Node(pkg); !ok {
+		return fmt.Errorf("package %s not found in %s", pkg, input)
+	}
+	report := whyReport{Package: pkg, Paths: g.PathsTo(pkg, opts.shortest)}
+	logger.Debugf("Found %d paths to %s", len(report.Paths), pkg)
+
+	out, err := openOutput(opts.output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if opts.format == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	// Only call the paths "shortest" when the limit may have cut some off.
+	return writeWhyReport(out, report, opts.shortest > 0 && len(report.Paths) == opts.shortest)
+}
+
+func writeWhyReport(w io.Writer, r whyReport, shortest bool) error {
+	if len(r.Paths) == 1 && len(r.Paths[0]) == 1 {
+		_, err := fmt.Fprintf(w, "%s is a root of the graph\n", r.Package)
+		return err
+	}
+
+	noun := "paths"
+	if len(r.Paths) == 1 {
+		noun = "path"
+	}
+	if shortest {
+		noun = "shortest " + noun
+	}
+	fmt.Fprintf(w, "%d %s to %s:\n", len(r.Paths), noun, r.Package)
+	for _, p := range r.Paths {
+		if _, err := fmt.Fprintf(w, "  %s\n", strings.Join(p, " → ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
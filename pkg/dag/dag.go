@@ -76,6 +76,14 @@ func New(meta Metadata) *DAG {
 
 func (d *DAG) Meta() Metadata { return d.meta }
 
+// BackEdgesKey holds, in graph metadata, the edges removed to break cycles.
+const BackEdgesKey = "back_edges"
+
+func (d *DAG) BackEdges() []Edge {
+	edges, _ := d.meta[BackEdgesKey].([]Edge)
+	return edges
+}
+
 func (d *DAG) AddNode(n Node) error {
 	if n.ID == "" {
 		return ErrInvalidNodeID
@@ -124,6 +132,17 @@ func (d *DAG) RemoveEdge(from, to string) {
 	d.incoming[to] = slices.DeleteFunc(d.incoming[to], func(s string) bool { return s == from })
 }
 
+// RemoveEdgesFunc removes every edge drop returns true for, in one pass.
+func (d *DAG) RemoveEdgesFunc(drop func(Edge) bool) {
+	d.edges = slices.DeleteFunc(d.edges, drop)
+	d.outgoing = make(map[string][]string, len(d.outgoing))
+	d.incoming = make(map[string][]string, len(d.incoming))
+	for _, e := range d.edges {
+		d.outgoing[e.From] = append(d.outgoing[e.From], e.To)
+		d.incoming[e.To] = append(d.incoming[e.To], e.From)
+	}
+}
+
 func (d *DAG) Nodes() []*Node {
 	nodes := make([]*Node, 0, len(d.nodes))
 	for _, n := range d.nodes {
@@ -463,3 +463,15 @@ func TestMeta(t *testing.T) {
 		t.Errorf("Meta()[graph] = %v, want %q", result["graph"], "test")
 	}
 }
+
+// buildGraph returns a graph with meta, nodes and the edges between them.
+func buildGraph(meta Metadata, nodes []Node, edges [][2]string) *DAG {
+	g := New(meta)
+	for _, n := range nodes {
+		g.AddNode(n)
+	}
+	for _, e := range edges {
+		g.AddEdge(Edge{From: e[0], To: e[1]})
+	}
+	return g
+}
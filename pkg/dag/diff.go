@@ -0,0 +1,259 @@
+package dag
+
+import (
+	"cmp"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// DiffKey marks nodes and edges of an Overlay graph with their Change.
+const DiffKey = "diff"
+
+// VolatileKeys are metadata keys whose values drift between parses with no
+// change to the package itself: counters, activity dates, release history
+// and scores derived from them. Compare ignores them.
+var VolatileKeys = []string{
+	"releases",
+	"downloads", "downloads_monthly",
+	"repo_stars", "repo_contributions",
+	"repo_last_commit", "repo_last_release",
+	"repo_open_issues", "repo_closed_issues", "repo_issue_close_days",
+	"scorecard_date",
+	"health",
+}
+
+type Change string
+
+const (
+	Added     Change = "added"
+	Removed   Change = "removed"
+	Changed   Change = "changed"
+	Unchanged Change = ""
+)
+
+// Diff is what changed between two snapshots of a dependency graph. Only
+// regular nodes are compared.
+type Diff struct {
+	AddedNodes   []string     `json:"added_nodes,omitempty"`
+	RemovedNodes []string     `json:"removed_nodes,omitempty"`
+	AddedEdges   []EdgeRef    `json:"added_edges,omitempty"`
+	RemovedEdges []EdgeRef    `json:"removed_edges,omitempty"`
+	Changed      []NodeChange `json:"changed,omitempty"`
+}
+
+type EdgeRef struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// NodeChange lists the differences of a node present in both snapshots.
+// The version is reported on its own rather than among Meta.
+type NodeChange struct {
+	ID         string       `json:"id"`
+	OldVersion string       `json:"old_version,omitempty"`
+	NewVersion string       `json:"new_version,omitempty"`
+	Meta       []MetaChange `json:"meta,omitempty"`
+}
+
+// MetaChange is a metadata value that differs; Old or New is nil when the
+// key is absent from that snapshot.
+type MetaChange struct {
+	Key string `json:"key"`
+	Old any    `json:"old,omitempty"`
+	New any    `json:"new,omitempty"`
+}
+
+func (c NodeChange) VersionChanged() bool { return c.OldVersion != c.NewVersion }
+
+func (d Diff) Empty() bool {
+	return len(d.AddedNodes) == 0 && len(d.RemovedNodes) == 0 &&
+		len(d.AddedEdges) == 0 && len(d.RemovedEdges) == 0 && len(d.Changed) == 0
+}
+
+// Compare diffs before against after. Metadata keys starting with "_" are
+// provenance bookkeeping and ignored, as are VolatileKeys.
+func Compare(before, after *DAG) Diff {
+	var d Diff
+	for _, n := range regularNodes(after) {
+		old, ok := before.Node(n.ID)
+		if !ok || old.IsSynthetic() {
+			d.AddedNodes = append(d.AddedNodes, n.ID)
+			continue
+		}
+		if c, changed := compareNodes(old, n); changed {
+			d.Changed = append(d.Changed, c)
+		}
+	}
+	for _, n := range regularNodes(before) {
+		if m, ok := after.Node(n.ID); !ok || m.IsSynthetic() {
+			d.RemovedNodes = append(d.RemovedNodes, n.ID)
+		}
+	}
+
+	oldEdges, newEdges := edgeSet(before), edgeSet(after)
+	for e := range newEdges {
+		if _, ok := oldEdges[e]; !ok {
+			d.AddedEdges = append(d.AddedEdges, e)
+		}
+	}
+	for e := range oldEdges {
+		if _, ok := newEdges[e]; !ok {
+			d.RemovedEdges = append(d.RemovedEdges, e)
+		}
+	}
+
+	slices.Sort(d.AddedNodes)
+	slices.Sort(d.RemovedNodes)
+	slices.SortFunc(d.AddedEdges, compareEdgeRefs)
+	slices.SortFunc(d.RemovedEdges, compareEdgeRefs)
+	slices.SortFunc(d.Changed, func(a, b NodeChange) int { return cmp.Compare(a.ID, b.ID) })
+	return d
+}
+
+// Overlay merges both snapshots into one graph for rendering: every node
+// and edge of after, plus the ones only before had, each tagged under
+// DiffKey. A removed edge is left out if it would close a cycle.
+func Overlay(before, after *DAG, d Diff) *DAG {
+	removed := toSet(d.RemovedNodes)
+	changes := make(map[string]Change, len(d.AddedNodes)+len(d.Changed))
+	for _, id := range d.AddedNodes {
+		changes[id] = Added
+	}
+	for _, c := range d.Changed {
+		changes[c.ID] = Changed
+	}
+
+	g := New(maps.Clone(after.Meta()))
+	for _, n := range after.Nodes() {
+		g.AddNode(tagNode(*n, changes[n.ID]))
+	}
+	for _, n := range before.Nodes() {
+		if _, ok := removed[n.ID]; ok {
+			g.AddNode(tagNode(*n, Removed))
+		}
+	}
+
+	added := make(map[EdgeRef]struct{}, len(d.AddedEdges))
+	for _, e := range d.AddedEdges {
+		added[e] = struct{}{}
+	}
+	for _, e := range after.Edges() {
+		change := Unchanged
+		if _, ok := added[EdgeRef{e.From, e.To}]; ok {
+			change = Added
+		}
+		g.AddEdge(tagEdge(e, change))
+	}
+	for _, e := range d.RemovedEdges {
+		_, okFrom := g.Node(e.From)
+		_, okTo := g.Node(e.To)
+		if !okFrom || !okTo || g.reaches(e.To, e.From) {
+			continue
+		}
+		g.AddEdge(tagEdge(Edge{From: e.From, To: e.To}, Removed))
+	}
+	return g
+}
+
+// ChangeOf reports how an Overlay node or edge changed.
+func ChangeOf(meta Metadata) Change {
+	c, _ := meta[DiffKey].(string)
+	return Change(c)
+}
+
+func compareNodes(old, cur *Node) (NodeChange, bool) {
+	c := NodeChange{ID: cur.ID}
+	c.OldVersion, _ = old.Meta["version"].(string)
+	c.NewVersion, _ = cur.Meta["version"].(string)
+
+	keys := make(map[string]struct{})
+	for k := range old.Meta {
+		keys[k] = struct{}{}
+	}
+	for k := range cur.Meta {
+		keys[k] = struct{}{}
+	}
+	for _, k := range slices.Sorted(maps.Keys(keys)) {
+		if k == "version" || strings.HasPrefix(k, "_") || slices.Contains(VolatileKeys, k) {
+			continue
+		}
+		o, n := old.Meta[k], cur.Meta[k]
+		if !reflect.DeepEqual(o, n) {
+			c.Meta = append(c.Meta, MetaChange{Key: k, Old: o, New: n})
+		}
+	}
+	return c, c.VersionChanged() || len(c.Meta) > 0
+}
+
+func regularNodes(g *DAG) []*Node {
+	var nodes []*Node
+	for _, n := range g.Nodes() {
+		if !n.IsSynthetic() {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+func edgeSet(g *DAG) map[EdgeRef]struct{} {
+	set := make(map[EdgeRef]struct{}, g.EdgeCount())
+	for _, e := range g.Edges() {
+		set[EdgeRef{e.From, e.To}] = struct{}{}
+	}
+	return set
+}
+
+func compareEdgeRefs(a, b EdgeRef) int {
+	return cmp.Or(cmp.Compare(a.From, b.From), cmp.Compare(a.To, b.To))
+}
+
+func toSet(ids []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		set[id] = struct{}{}
+	}
+	return set
+}
+
+func tagNode(n Node, c Change) Node {
+	n.Meta = maps.Clone(n.Meta)
+	if c != Unchanged {
+		if n.Meta == nil {
+			n.Meta = Metadata{}
+		}
+		n.Meta[DiffKey] = string(c)
+	}
+	return n
+}
+
+func tagEdge(e Edge, c Change) Edge {
+	e.Meta = maps.Clone(e.Meta)
+	if c != Unchanged {
+		if e.Meta == nil {
+			e.Meta = Metadata{}
+		}
+		e.Meta[DiffKey] = string(c)
+	}
+	return e
+}
+
+func (d *DAG) reaches(from, to string) bool {
+	seen := map[string]bool{from: true}
+	stack := []string{from}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if id == to {
+			return true
+		}
+		for _, c := range d.outgoing[id] {
+			if !seen[c] {
+				seen[c] = true
+				stack = append(stack, c)
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package dag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func snapshot(versions map[string]string, edges ...[2]string) *DAG {
+	var nodes []Node
+	for id, v := range versions {
+		nodes = append(nodes, Node{ID: id, Meta: Metadata{"version": v}})
+	}
+	return buildGraph(nil, nodes, edges)
+}
+
+func TestCompare(t *testing.T) {
+	before := snapshot(map[string]string{"app": "1.0", "old": "0.1", "lib": "1.0", "same": "2.0"},
+		[2]string{"app", "old"}, [2]string{"app", "lib"}, [2]string{"lib", "same"})
+	after := snapshot(map[string]string{"app": "1.0", "new": "0.2", "lib": "1.1", "same": "2.0"},
+		[2]string{"app", "new"}, [2]string{"app", "lib"}, [2]string{"lib", "same"})
+	n, _ := after.Node("same")
+	n.Meta["license"] = "MIT"
+	n.Meta["_sources"] = map[string]string{"license": "github"}
+
+	d := Compare(before, after)
+
+	if !reflect.DeepEqual(d.AddedNodes, []string{"new"}) || !reflect.DeepEqual(d.RemovedNodes, []string{"old"}) {
+		t.Errorf("nodes: added %v, removed %v", d.AddedNodes, d.RemovedNodes)
+	}
+	if !reflect.DeepEqual(d.AddedEdges, []EdgeRef{{"app", "new"}}) || !reflect.DeepEqual(d.RemovedEdges, []EdgeRef{{"app", "old"}}) {
+		t.Errorf("edges: added %v, removed %v", d.AddedEdges, d.RemovedEdges)
+	}
+
+	want := []NodeChange{
+		{ID: "lib", OldVersion: "1.0", NewVersion: "1.1"},
+		{ID: "same", OldVersion: "2.0", NewVersion: "2.0", Meta: []MetaChange{{Key: "license", New: "MIT"}}},
+	}
+	if !reflect.DeepEqual(d.Changed, want) {
+		t.Errorf("changed = %+v, want %+v", d.Changed, want)
+	}
+	if !d.Changed[0].VersionChanged() || d.Changed[1].VersionChanged() {
+		t.Error("only lib's version changed")
+	}
+
+	if !Compare(before, before).Empty() {
+		t.Error("a graph should not differ from itself")
+	}
+}
+
+func TestCompare_IgnoresVolatileKeys(t *testing.T) {
+	before := snapshot(map[string]string{"app": "1.0", "lib": "2.0"}, [2]string{"app", "lib"})
+	after := snapshot(map[string]string{"app": "1.0", "lib": "2.0"}, [2]string{"app", "lib"})
+	old, _ := before.Node("lib")
+	old.Meta["releases"] = map[string]any{"2.0": "2024-01-01"}
+	old.Meta["repo_stars"] = 10
+	cur, _ := after.Node("lib")
+	cur.Meta["releases"] = map[string]any{"2.0": "2024-01-01", "2.1": "2024-06-01"}
+	cur.Meta["repo_stars"] = 12
+
+	if d := Compare(before, after); !d.Empty() {
+		t.Errorf("expected no difference, got %+v", d)
+	}
+}
+
+func TestOverlay(t *testing.T) {
+	before := snapshot(map[string]string{"a": "1", "b": "1", "gone": "1"},
+		[2]string{"a", "b"}, [2]string{"a", "gone"})
+	after := snapshot(map[string]string{"a": "1", "b": "2", "fresh": "1"},
+		[2]string{"b", "a"}, [2]string{"a", "fresh"})
+
+	g := Overlay(before, after, Compare(before, after))
+
+	wantNodes := map[string]Change{"a": Unchanged, "b": Changed, "fresh": Added, "gone": Removed}
+	if g.NodeCount() != len(wantNodes) {
+		t.Fatalf("NodeCount() = %d, want %d", g.NodeCount(), len(wantNodes))
+	}
+	for id, want := range wantNodes {
+		n, _ := g.Node(id)
+		if got := ChangeOf(n.Meta); got != want {
+			t.Errorf("%s: change = %q, want %q", id, got, want)
+		}
+	}
+
+	edges := make(map[EdgeRef]Change)
+	for _, e := range g.Edges() {
+		edges[EdgeRef{e.From, e.To}] = ChangeOf(e.Meta)
+	}
+	wantEdges := map[EdgeRef]Change{{"b", "a"}: Added, {"a", "fresh"}: Added, {"a", "gone"}: Removed}
+	if !reflect.DeepEqual(edges, wantEdges) {
+		t.Errorf("edges = %v, want %v (a->b would close a cycle)", edges, wantEdges)
+	}
+	if err := g.detectCycles(); err != nil {
+		t.Errorf("detectCycles() = %v", err)
+	}
+
+	if n, _ := after.Node("fresh"); ChangeOf(n.Meta) != Unchanged {
+		t.Error("Overlay must not modify its inputs")
+	}
+}
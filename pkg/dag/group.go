@@ -0,0 +1,167 @@
+package dag
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// MembersKey lists, on a node made by Group, the packages it stands for.
+const MembersKey = "members"
+
+// Group merges the nodes key maps to the same non-empty name into one
+// composite node of that name. Groups of one, and groups whose name is
+// already taken by a node outside them, are left alone. A composite keeps
+// its members' edges to the rest of the graph, the metadata values they
+// all share, and their IDs under MembersKey. Merging can close dependency
+// cycles, which rendering breaks like any other.
+func (d *DAG) Group(key func(*Node) string) *DAG {
+	groups := make(map[string][]*Node)
+	for _, n := range d.Nodes() {
+		if n.IsSynthetic() {
+			continue
+		}
+		if name := key(n); name != "" {
+			groups[name] = append(groups[name], n)
+		}
+	}
+
+	into := make(map[string]string)
+	for name, members := range groups {
+		if len(members) < 2 {
+			continue
+		}
+		if n, ok := d.nodes[name]; ok && !slices.Contains(members, n) {
+			continue
+		}
+		for _, m := range members {
+			into[m.ID] = name
+		}
+	}
+	if len(into) == 0 {
+		return d.Subgraph(func(*Node) bool { return true })
+	}
+
+	g := New(maps.Clone(d.meta))
+	for _, n := range d.Nodes() {
+		if _, ok := into[n.ID]; !ok {
+			g.AddNode(*n)
+		}
+	}
+	for name, members := range groups {
+		if into[members[0].ID] == name {
+			g.AddNode(composite(name, members))
+		}
+	}
+
+	for _, e := range d.edges {
+		from, to := e.From, e.To
+		if name, ok := into[from]; ok {
+			from = name
+		}
+		if name, ok := into[to]; ok {
+			to = name
+		}
+		if from == to || slices.Contains(g.outgoing[from], to) {
+			continue
+		}
+		e.From, e.To = from, to
+		g.AddEdge(e)
+	}
+	return g
+}
+
+func composite(name string, members []*Node) Node {
+	var ids []string
+	for _, m := range members {
+		ids = append(ids, Members(m)...)
+	}
+	slices.Sort(ids)
+
+	meta := Metadata{}
+	for k, v := range members[0].Meta {
+		if k == MembersKey || k == CollapsedKey || strings.HasPrefix(k, "_") {
+			continue
+		}
+		shared := true
+		for _, m := range members[1:] {
+			if !reflect.DeepEqual(m.Meta[k], v) {
+				shared = false
+				break
+			}
+		}
+		if shared {
+			meta[k] = v
+		}
+	}
+	meta[MembersKey] = ids
+	return Node{ID: name, Meta: meta}
+}
+
+// Members returns the packages a composite node stands for, or just the
+// node itself.
+func Members(n *Node) []string {
+	switch v := n.Meta[MembersKey].(type) {
+	case []string:
+		return v
+	case []any: // as read back from a graph file
+		ids := make([]string, 0, len(v))
+		for _, id := range v {
+			ids = append(ids, fmt.Sprint(id))
+		}
+		return ids
+	}
+	return []string{n.ID}
+}
+
+// ByPrefix groups the packages whose name starts with prefix as prefix*.
+func ByPrefix(prefix string) func(*Node) string {
+	return func(n *Node) string {
+		if prefix != "" && strings.HasPrefix(n.ID, prefix) {
+			return prefix + "*"
+		}
+		return ""
+	}
+}
+
+// ByScope groups scoped npm packages such as @babel/core as @babel/*.
+func ByScope(n *Node) string {
+	if scope, _, ok := strings.Cut(n.ID, "/"); ok && strings.HasPrefix(scope, "@") {
+		return scope + "/*"
+	}
+	return ""
+}
+
+// ByRepo groups packages published from one repository, as its owner/name.
+func ByRepo(n *Node) string {
+	url, _ := n.Meta["repo_url"].(string)
+	url = strings.ToLower(strings.TrimSpace(url))
+	for _, prefix := range []string{"git+", "https://", "http://", "git://", "ssh://", "git@", "www."} {
+		url = strings.TrimPrefix(url, prefix)
+	}
+	url = strings.TrimSuffix(strings.TrimSuffix(url, "/"), ".git")
+
+	parts := strings.FieldsFunc(url, func(r rune) bool { return r == '/' || r == ':' })
+	if len(parts) < 3 {
+		return ""
+	}
+	return parts[1] + "/" + parts[2]
+}
+
+// ByMeta groups packages by the printed value of a metadata key, as
+// key=value.
+func ByMeta(key string) func(*Node) string {
+	return func(n *Node) string {
+		v, ok := n.Meta[key]
+		if !ok || v == nil {
+			return ""
+		}
+		s := fmt.Sprint(v)
+		if s == "" {
+			return ""
+		}
+		return key + "=" + s
+	}
+}
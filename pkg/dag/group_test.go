@@ -0,0 +1,84 @@
+package dag
+
+import (
+	"reflect"
+	"testing"
+)
+
+// app → {@babel/core, @babel/cli, lodash}, @babel/cli → @babel/core,
+// @babel/core → debug
+func groupFixture() *DAG {
+	repo := "https://github.com/babel/babel"
+	return buildGraph(nil, []Node{
+		{ID: "app"},
+		{ID: "@babel/core", Meta: Metadata{"repo_url": repo, "license": "MIT", "version": "7.0.0"}},
+		{ID: "@babel/cli", Meta: Metadata{"repo_url": repo + ".git", "license": "MIT", "version": "7.1.0"}},
+		{ID: "lodash", Meta: Metadata{"license": "MIT"}},
+		{ID: "debug"},
+	}, [][2]string{
+		{"app", "@babel/core"}, {"app", "@babel/cli"}, {"app", "lodash"},
+		{"@babel/cli", "@babel/core"}, {"@babel/core", "debug"},
+	})
+}
+
+func TestGroup_Scope(t *testing.T) {
+	g := groupFixture().Group(ByScope)
+
+	if want := []string{"@babel/*", "app", "debug", "lodash"}; !reflect.DeepEqual(ids(g), want) {
+		t.Fatalf("nodes = %v, want %v", ids(g), want)
+	}
+	if want := []string{"@babel/*->debug", "app->@babel/*", "app->lodash"}; !reflect.DeepEqual(edges(g), want) {
+		t.Errorf("edges = %v, want %v", edges(g), want)
+	}
+
+	n, _ := g.Node("@babel/*")
+	if got := Members(n); !reflect.DeepEqual(got, []string{"@babel/cli", "@babel/core"}) {
+		t.Errorf("members = %v", got)
+	}
+	if n.Meta["license"] != "MIT" {
+		t.Error("shared metadata should carry over")
+	}
+	if _, ok := n.Meta["version"]; ok {
+		t.Error("differing metadata should be dropped")
+	}
+}
+
+func TestGroup_Keys(t *testing.T) {
+	g := groupFixture()
+	cases := []struct {
+		name string
+		key  func(*Node) string
+		want []string
+	}{
+		{"repo", ByRepo, []string{"app", "babel/babel", "debug", "lodash"}},
+		{"prefix", ByPrefix("@babel/c"), []string{"@babel/c*", "app", "debug", "lodash"}},
+		{"meta", ByMeta("license"), []string{"app", "debug", "license=MIT"}},
+		{"single member", ByPrefix("lo"), []string{"@babel/cli", "@babel/core", "app", "debug", "lodash"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ids(g.Group(tc.key)); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("nodes = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGroup_Nested(t *testing.T) {
+	g := groupFixture().Group(ByScope).Group(ByMeta("license"))
+	n, ok := g.Node("license=MIT")
+	if !ok {
+		t.Fatalf("nodes = %v", ids(g))
+	}
+	if got := Members(n); !reflect.DeepEqual(got, []string{"@babel/cli", "@babel/core", "lodash"}) {
+		t.Errorf("members = %v", got)
+	}
+}
+
+func TestGroup_NameTaken(t *testing.T) {
+	g := groupFixture()
+	g.AddNode(Node{ID: "license=MIT"})
+	if got := g.Group(ByMeta("license")).NodeCount(); got != g.NodeCount() {
+		t.Errorf("group named after an existing node should be skipped, got %d nodes", got)
+	}
+}
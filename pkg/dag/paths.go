@@ -0,0 +1,99 @@
+package dag
+
+import (
+	"container/heap"
+	"slices"
+	"strings"
+)
+
+// PathsTo lists the paths from the graph's sources down to target, shortest
+// first, at most k of them (all when k <= 0). Paths name real packages:
+// subdividers are folded into the node they stand in for via MasterID and
+// auxiliary nodes are dropped.
+//
+// Each step extends a partial path by one parent, so the work grows with
+// the number of partial paths explored rather than with the graph. A deep
+// package in a densely connected graph can have exponentially many paths;
+// with k <= 0 every one of them is built and kept in memory. Bound k
+// unless the graph is known to be small.
+func (d *DAG) PathsTo(target string, k int) [][]string {
+	if _, ok := d.nodes[target]; !ok {
+		return nil
+	}
+
+	var (
+		paths [][]string
+		seen  = make(map[string]struct{})
+		queue = &pathQueue{{ids: []string{target}, cost: d.pathCost(target)}}
+	)
+	for queue.Len() > 0 && (k <= 0 || len(paths) < k) {
+		p := heap.Pop(queue).(partialPath)
+		head := p.ids[len(p.ids)-1]
+		parents := d.incoming[head]
+		if len(parents) == 0 {
+			path := d.realPath(p.ids)
+			key := strings.Join(path, "\x00")
+			if _, dup := seen[key]; !dup {
+				seen[key] = struct{}{}
+				paths = append(paths, path)
+			}
+			continue
+		}
+		for _, parent := range parents {
+			if slices.Contains(p.ids, parent) {
+				continue // a cycle; not a path
+			}
+			ids := append(slices.Clip(p.ids), parent)
+			heap.Push(queue, partialPath{ids: ids, cost: p.cost + d.pathCost(parent)})
+		}
+	}
+	return paths
+}
+
+// pathCost counts real packages only, so subdivider chains don't make a
+// path look longer than it is.
+func (d *DAG) pathCost(id string) int {
+	if d.nodes[id].IsSynthetic() {
+		return 0
+	}
+	return 1
+}
+
+// realPath turns a target-to-source walk into a source-to-target list of
+// the packages it passes.
+func (d *DAG) realPath(walk []string) []string {
+	path := make([]string, 0, len(walk))
+	for _, id := range slices.Backward(walk) {
+		n := d.nodes[id]
+		if n.IsAuxiliary() {
+			continue
+		}
+		if eff := n.EffectiveID(); len(path) == 0 || path[len(path)-1] != eff {
+			path = append(path, eff)
+		}
+	}
+	return path
+}
+
+type partialPath struct {
+	ids  []string // target first
+	cost int
+}
+
+type pathQueue []partialPath
+
+func (q pathQueue) Len() int { return len(q) }
+func (q pathQueue) Less(i, j int) bool {
+	if q[i].cost != q[j].cost {
+		return q[i].cost < q[j].cost
+	}
+	return slices.Compare(q[i].ids, q[j].ids) < 0
+}
+func (q pathQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *pathQueue) Push(x any)   { *q = append(*q, x.(partialPath)) }
+func (q *pathQueue) Pop() any {
+	old := *q
+	p := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return p
+}
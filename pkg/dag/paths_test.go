@@ -0,0 +1,44 @@
+package dag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPathsTo(t *testing.T) {
+	// app → web → http → idna, app → idna, app → cli → http; plus a
+	// subdivider standing in for app on a long edge to "tls".
+	g := buildGraph(nil, []Node{
+		{ID: "app", Row: 0}, {ID: "web", Row: 1}, {ID: "cli", Row: 1},
+		{ID: "http", Row: 2}, {ID: "idna", Row: 3}, {ID: "tls", Row: 3},
+		{ID: "app_sub_1", Row: 1, Kind: NodeKindSubdivider, MasterID: "app"},
+		{ID: "app_sub_2", Row: 2, Kind: NodeKindSubdivider, MasterID: "app"},
+		{ID: "other", Row: 0},
+	}, [][2]string{
+		{"app", "web"}, {"app", "cli"}, {"web", "http"}, {"cli", "http"},
+		{"http", "idna"}, {"app", "app_sub_1"}, {"app_sub_1", "app_sub_2"},
+		{"app_sub_2", "idna"}, {"app_sub_2", "tls"},
+	})
+
+	tests := []struct {
+		name   string
+		target string
+		k      int
+		want   [][]string
+	}{
+		{"all, shortest first", "idna", 0, [][]string{
+			{"app", "idna"}, {"app", "cli", "http", "idna"}, {"app", "web", "http", "idna"},
+		}},
+		{"shortest k", "idna", 2, [][]string{{"app", "idna"}, {"app", "cli", "http", "idna"}}},
+		{"through subdividers", "tls", 0, [][]string{{"app", "tls"}}},
+		{"source", "other", 0, [][]string{{"other"}}},
+		{"unknown", "missing", 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := g.PathsTo(tt.target, tt.k); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PathsTo(%q, %d) = %v, want %v", tt.target, tt.k, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package dag
+
+import (
+	"maps"
+	"slices"
+)
+
+// CollapsedKey records, on a node folded by Collapse, how many packages it
+// stands for besides itself.
+const CollapsedKey = "collapsed"
+
+// Ancestors returns the IDs of every node that can reach id, sorted.
+func (d *DAG) Ancestors(id string) []string { return d.walk(id, d.incoming) }
+
+// Descendants returns the IDs of every node id can reach, sorted.
+func (d *DAG) Descendants(id string) []string { return d.walk(id, d.outgoing) }
+
+func (d *DAG) walk(id string, next map[string][]string) []string {
+	seen := map[string]bool{id: true}
+	stack := []string{id}
+	var out []string
+	for len(stack) > 0 {
+		curr := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, n := range next[curr] {
+			if !seen[n] {
+				seen[n] = true
+				out = append(out, n)
+				stack = append(stack, n)
+			}
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+// Subgraph returns a copy of d holding the nodes keep accepts and the edges
+// between them.
+func (d *DAG) Subgraph(keep func(*Node) bool) *DAG {
+	g := New(maps.Clone(d.meta))
+	for _, n := range d.Nodes() {
+		if keep(n) {
+			g.AddNode(*n)
+		}
+	}
+	for _, e := range d.edges {
+		g.AddEdge(e) // fails, and so skips the edge, unless both ends were kept
+	}
+	return g
+}
+
+// Without drops the nodes drop matches along with whatever was reachable
+// only through them, so that no new roots appear.
+func (d *DAG) Without(drop func(*Node) bool) *DAG {
+	g := d.Subgraph(func(n *Node) bool { return !drop(n) })
+	reachable := make(map[string]bool)
+	for _, src := range d.Sources() {
+		if _, ok := g.nodes[src.ID]; !ok {
+			continue
+		}
+		reachable[src.ID] = true
+		for _, id := range g.Descendants(src.ID) {
+			reachable[id] = true
+		}
+	}
+	return g.Subgraph(func(n *Node) bool { return reachable[n.ID] })
+}
+
+// Prune keeps the nodes at most maxDepth edges below a root, measured along
+// the shortest path.
+func (d *DAG) Prune(maxDepth int) *DAG {
+	depth := make(map[string]int, len(d.nodes))
+	var queue []string
+	for _, n := range d.Sources() {
+		depth[n.ID] = 0
+		queue = append(queue, n.ID)
+	}
+	for len(queue) > 0 {
+		curr := queue[0]
+		queue = queue[1:]
+		for _, c := range d.outgoing[curr] {
+			if _, ok := depth[c]; !ok {
+				depth[c] = depth[curr] + 1
+				queue = append(queue, c)
+			}
+		}
+	}
+	return d.Subgraph(func(n *Node) bool {
+		dep, ok := depth[n.ID]
+		return ok && dep <= maxDepth
+	})
+}
+
+// Collapse folds everything reachable only through id into id itself,
+// which then depends directly on the shared packages its subtree used.
+// The number of folded packages is recorded under CollapsedKey.
+func (d *DAG) Collapse(id string) *DAG {
+	if _, ok := d.nodes[id]; !ok {
+		return d.Subgraph(func(*Node) bool { return true })
+	}
+
+	// Whatever the other roots still reach without passing through id is
+	// shared; the rest of id's subtree is folded.
+	shared := make(map[string]bool)
+	for _, src := range d.Sources() {
+		if src.ID == id {
+			continue
+		}
+		shared[src.ID] = true
+		stack := []string{src.ID}
+		for len(stack) > 0 {
+			curr := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, c := range d.outgoing[curr] {
+				if c != id && !shared[c] {
+					shared[c] = true
+					stack = append(stack, c)
+				}
+			}
+		}
+	}
+	folded := make(map[string]bool)
+	for _, desc := range d.Descendants(id) {
+		if !shared[desc] {
+			folded[desc] = true
+		}
+	}
+
+	g := d.Subgraph(func(n *Node) bool { return !folded[n.ID] })
+	if len(folded) == 0 {
+		return g
+	}
+	n := g.nodes[id]
+	n.Meta = maps.Clone(n.Meta)
+	n.Meta[CollapsedKey] = len(folded)
+
+	for _, e := range d.edges {
+		if folded[e.From] && !folded[e.To] && !slices.Contains(g.outgoing[id], e.To) {
+			g.AddEdge(Edge{From: id, To: e.To})
+		}
+	}
+	return g
+}
@@ -0,0 +1,101 @@
+package dag
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// app → web → {http, tmpl}, app → cli → http, http → idna, tmpl → escape
+func subgraphFixture() *DAG {
+	var nodes []Node
+	for _, id := range []string{"app", "web", "cli", "http", "tmpl", "idna", "escape"} {
+		nodes = append(nodes, Node{ID: id})
+	}
+	return buildGraph(Metadata{"root": "app"}, nodes, [][2]string{
+		{"app", "web"}, {"app", "cli"}, {"web", "http"}, {"web", "tmpl"},
+		{"cli", "http"}, {"http", "idna"}, {"tmpl", "escape"},
+	})
+}
+
+func ids(g *DAG) []string {
+	return slices.Sorted(slices.Values(NodeIDs(g.Nodes())))
+}
+
+func edges(g *DAG) []string {
+	var out []string
+	for _, e := range g.Edges() {
+		out = append(out, e.From+"->"+e.To)
+	}
+	slices.Sort(out)
+	return out
+}
+
+func TestAncestorsDescendants(t *testing.T) {
+	g := subgraphFixture()
+	if got := g.Ancestors("http"); !reflect.DeepEqual(got, []string{"app", "cli", "web"}) {
+		t.Errorf("Ancestors(http) = %v", got)
+	}
+	if got := g.Descendants("web"); !reflect.DeepEqual(got, []string{"escape", "http", "idna", "tmpl"}) {
+		t.Errorf("Descendants(web) = %v", got)
+	}
+}
+
+func TestSubgraph(t *testing.T) {
+	g := subgraphFixture()
+	sub := g.Subgraph(func(n *Node) bool { return n.ID != "web" })
+
+	if want := []string{"app", "cli", "escape", "http", "idna", "tmpl"}; !reflect.DeepEqual(ids(sub), want) {
+		t.Errorf("nodes = %v, want %v", ids(sub), want)
+	}
+	if want := []string{"app->cli", "cli->http", "http->idna", "tmpl->escape"}; !reflect.DeepEqual(edges(sub), want) {
+		t.Errorf("edges = %v, want %v", edges(sub), want)
+	}
+	if sub.Meta()["root"] != "app" || g.NodeCount() != 7 {
+		t.Error("Subgraph should copy graph metadata and leave the original intact")
+	}
+}
+
+func TestWithout(t *testing.T) {
+	g := subgraphFixture()
+	sub := g.Without(func(n *Node) bool { return strings.HasPrefix(n.ID, "we") })
+
+	// tmpl and escape were only reachable through web; http still is via cli.
+	if want := []string{"app", "cli", "http", "idna"}; !reflect.DeepEqual(ids(sub), want) {
+		t.Errorf("nodes = %v, want %v", ids(sub), want)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	g := subgraphFixture()
+	if want := []string{"app", "cli", "web"}; !reflect.DeepEqual(ids(g.Prune(1)), want) {
+		t.Errorf("Prune(1) = %v, want %v", ids(g.Prune(1)), want)
+	}
+	if got := ids(g.Prune(0)); !reflect.DeepEqual(got, []string{"app"}) {
+		t.Errorf("Prune(0) = %v", got)
+	}
+}
+
+func TestCollapse(t *testing.T) {
+	g := subgraphFixture()
+	g.AddNode(Node{ID: "tool"})
+	g.AddEdge(Edge{From: "tool", To: "http"})
+
+	sub := g.Collapse("app")
+
+	// tool keeps http and idna around; app now depends on http directly.
+	if want := []string{"app", "http", "idna", "tool"}; !reflect.DeepEqual(ids(sub), want) {
+		t.Errorf("nodes = %v, want %v", ids(sub), want)
+	}
+	if want := []string{"app->http", "http->idna", "tool->http"}; !reflect.DeepEqual(edges(sub), want) {
+		t.Errorf("edges = %v, want %v", edges(sub), want)
+	}
+	app, _ := sub.Node("app")
+	if app.Meta[CollapsedKey] != 4 {
+		t.Errorf("collapsed = %v, want 4", app.Meta[CollapsedKey])
+	}
+	if orig, _ := g.Node("app"); orig.Meta[CollapsedKey] != nil {
+		t.Error("Collapse must not modify the original graph")
+	}
+}
@@ -0,0 +1,114 @@
+package transform
+
+import (
+	"cmp"
+	"container/heap"
+	"math"
+	"slices"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+)
+
+// CoffmanGraham fills rows bottom-up with at most Width nodes each, trading
+// height for a narrower tower. A Width of zero or less picks the square
+// root of the node count. Expects a transitively reduced graph.
+type CoffmanGraham struct {
+	Width int
+}
+
+func (c CoffmanGraham) Layers(g *dag.DAG) map[string]int {
+	nodes := g.Nodes()
+	if len(nodes) == 0 {
+		return map[string]int{}
+	}
+	width := c.Width
+	if width <= 0 {
+		width = int(math.Ceil(math.Sqrt(float64(len(nodes)))))
+	}
+
+	label := coffmanGrahamLabels(g)
+	order := dag.NodeIDs(nodes)
+	slices.SortFunc(order, func(a, b string) int { return cmp.Compare(label[b], label[a]) })
+
+	// Highest label first, put each node on the lowest row, counting from
+	// the bottom, that is above all its children and not yet full.
+	level := make(map[string]int, len(nodes))
+	count := make(map[int]int)
+	for _, id := range order {
+		l := 0
+		for _, c := range g.Children(id) {
+			l = max(l, level[c]+1)
+		}
+		for count[l] >= width {
+			l++
+		}
+		level[id] = l
+		count[l]++
+	}
+
+	top := 0
+	for _, l := range level {
+		top = max(top, l)
+	}
+	rows := make(map[string]int, len(level))
+	for id, l := range level {
+		rows[id] = top - l
+	}
+	return rows
+}
+
+// coffmanGrahamLabels numbers nodes top-down: each step labels the node,
+// among those whose parents are all labelled, whose parent labels sorted
+// in decreasing order are lexicographically smallest. A node's parent
+// labels are final once it becomes ready, so they are gathered then and
+// the ready nodes kept in a heap.
+func coffmanGrahamLabels(g *dag.DAG) map[string]int {
+	nodes := g.Nodes()
+	label := make(map[string]int, len(nodes))
+	unlabeled := make(map[string]int, len(nodes))
+	ready := &labelQueue{}
+	for _, n := range nodes {
+		unlabeled[n.ID] = g.InDegree(n.ID)
+		if unlabeled[n.ID] == 0 {
+			heap.Push(ready, readyNode{id: n.ID})
+		}
+	}
+
+	for next := 1; ready.Len() > 0; next++ {
+		id := heap.Pop(ready).(readyNode).id
+		label[id] = next
+		for _, c := range g.Children(id) {
+			unlabeled[c]--
+			if unlabeled[c] > 0 {
+				continue
+			}
+			parents := make([]int, 0, g.InDegree(c))
+			for _, p := range g.Parents(c) {
+				parents = append(parents, label[p])
+			}
+			slices.SortFunc(parents, func(a, b int) int { return cmp.Compare(b, a) })
+			heap.Push(ready, readyNode{id: c, parents: parents})
+		}
+	}
+	return label
+}
+
+type readyNode struct {
+	id      string
+	parents []int // parent labels, decreasing
+}
+
+type labelQueue []readyNode
+
+func (q labelQueue) Len() int { return len(q) }
+func (q labelQueue) Less(i, j int) bool {
+	return cmp.Or(slices.Compare(q[i].parents, q[j].parents), cmp.Compare(q[i].id, q[j].id)) < 0
+}
+func (q labelQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *labelQueue) Push(x any)   { *q = append(*q, x.(readyNode)) }
+func (q *labelQueue) Pop() any {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
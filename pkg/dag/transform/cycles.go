@@ -0,0 +1,111 @@
+package transform
+
+import (
+	"slices"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+)
+
+// BreakCycles makes g acyclic by removing a feedback arc set: the edges
+// pointing backwards in a vertex order chosen by the Eades–Lin–Smyth
+// heuristic, minus any that turn out not to close a cycle on their own.
+// The removed edges are recorded as g's back edges and returned.
+func BreakCycles(g *dag.DAG) []dag.Edge {
+	pos := dag.PosMap(feedbackOrder(g))
+
+	var candidates []dag.Edge
+	for _, e := range g.Edges() {
+		if pos[e.From] >= pos[e.To] {
+			candidates = append(candidates, e)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	for _, e := range candidates {
+		g.RemoveEdge(e.From, e.To)
+	}
+
+	// The heuristic can overshoot; restore every edge that the graph can
+	// take back without a cycle, so the removed set is minimal.
+	var back []dag.Edge
+	for _, e := range candidates {
+		if e.From != e.To && !slices.Contains(g.Descendants(e.To), e.From) {
+			g.AddEdge(e)
+			continue
+		}
+		back = append(back, e)
+	}
+
+	g.Meta()[dag.BackEdgesKey] = append(g.BackEdges(), back...)
+	return back
+}
+
+// feedbackOrder repeatedly peels sinks off the end and sources off the
+// front; when neither is left it moves the node with the greatest surplus
+// of outgoing over incoming edges to the front.
+func feedbackOrder(g *dag.DAG) []string {
+	ids := dag.NodeIDs(g.Nodes())
+	slices.Sort(ids)
+
+	in := make(map[string]int, len(ids))
+	out := make(map[string]int, len(ids))
+	for _, e := range g.Edges() {
+		if e.From != e.To {
+			out[e.From]++
+			in[e.To]++
+		}
+	}
+
+	remaining := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		remaining[id] = true
+	}
+	remove := func(id string) {
+		delete(remaining, id)
+		for _, c := range g.Children(id) {
+			if c != id && remaining[c] {
+				in[c]--
+			}
+		}
+		for _, p := range g.Parents(id) {
+			if p != id && remaining[p] {
+				out[p]--
+			}
+		}
+	}
+
+	var front, back []string
+	for len(remaining) > 0 {
+		peeled := false
+		for _, id := range ids {
+			if remaining[id] && out[id] == 0 {
+				back = append(back, id)
+				remove(id)
+				peeled = true
+			}
+		}
+		for _, id := range ids {
+			if remaining[id] && in[id] == 0 {
+				front = append(front, id)
+				remove(id)
+				peeled = true
+			}
+		}
+		if peeled {
+			continue
+		}
+
+		best := ""
+		for _, id := range ids {
+			if remaining[id] && (best == "" || out[id]-in[id] > out[best]-in[best]) {
+				best = id
+			}
+		}
+		front = append(front, best)
+		remove(best)
+	}
+
+	slices.Reverse(back)
+	return append(front, back...)
+}
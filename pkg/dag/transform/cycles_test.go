@@ -0,0 +1,67 @@
+package transform
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBreakCycles(t *testing.T) {
+	tests := []struct {
+		name     string
+		ids      []string
+		edges    [][2]string
+		wantBack int
+	}{
+		{"acyclic", []string{"a", "b", "c"}, [][2]string{{"a", "b"}, {"b", "c"}}, 0},
+		{"peer loop", []string{"app", "a", "b"}, [][2]string{{"app", "a"}, {"a", "b"}, {"b", "a"}}, 1},
+		{"self loop", []string{"a", "b"}, [][2]string{{"a", "b"}, {"b", "b"}}, 1},
+		{"no root", []string{"a", "b", "c"}, [][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}}, 1},
+		{"two cycles sharing an edge", []string{"a", "b", "c", "d"},
+			[][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}, {"b", "d"}, {"d", "a"}}, 1},
+		{"disjoint cycles", []string{"a", "b", "c", "d"},
+			[][2]string{{"a", "b"}, {"b", "a"}, {"c", "d"}, {"d", "c"}}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := buildGraph(tt.ids, tt.edges)
+			back := BreakCycles(g)
+
+			if len(back) != tt.wantBack {
+				t.Fatalf("removed %v, want %d edges", back, tt.wantBack)
+			}
+			if g.EdgeCount() != len(tt.edges)-len(back) {
+				t.Errorf("EdgeCount() = %d, want %d", g.EdgeCount(), len(tt.edges)-len(back))
+			}
+			if len(g.BackEdges()) != len(back) {
+				t.Errorf("BackEdges() = %v, want %v", g.BackEdges(), back)
+			}
+			AssignLayers(g)
+			Subdivide(g)
+			if err := g.Validate(); err != nil {
+				t.Errorf("graph still invalid: %v", err)
+			}
+			// Every removed edge must be needed: putting it back closes a cycle.
+			for _, e := range back {
+				if e.From != e.To && !slices.Contains(g.Descendants(e.To), e.From) {
+					t.Errorf("%s->%s was removed needlessly", e.From, e.To)
+				}
+			}
+		})
+	}
+}
+
+func TestNormalize_Cyclic(t *testing.T) {
+	g := buildGraph([]string{"app", "a", "b", "c"},
+		[][2]string{{"app", "a"}, {"a", "b"}, {"b", "c"}, {"c", "a"}})
+
+	Normalize(g)
+
+	checkRow(t, g, "app", 0)
+	checkRow(t, g, "a", 1)
+	checkRow(t, g, "b", 2)
+	checkRow(t, g, "c", 3)
+	if back := g.BackEdges(); len(back) != 1 || back[0].From != "c" || back[0].To != "a" {
+		t.Errorf("BackEdges() = %v, want [c->a]", back)
+	}
+}
@@ -2,7 +2,21 @@ package transform
 
 import "github.com/matzehuels/stacktower/pkg/dag"
 
-func AssignLayers(g *dag.DAG) {
+// Layerer assigns every node a row such that each edge points down.
+type Layerer interface {
+	Layers(g *dag.DAG) map[string]int
+}
+
+// AssignLayers rows g by longest path from its sources.
+func AssignLayers(g *dag.DAG) { AssignLayersWith(g, LongestPath{}) }
+
+func AssignLayersWith(g *dag.DAG, l Layerer) { g.SetRows(l.Layers(g)) }
+
+// LongestPath puts every source on the top row and each other node one row
+// below its lowest parent, as high as it can go.
+type LongestPath struct{}
+
+func (LongestPath) Layers(g *dag.DAG) map[string]int {
 	nodes := g.Nodes()
 	inDegree := make(map[string]int, len(nodes))
 	rows := make(map[string]int, len(nodes))
@@ -31,5 +45,53 @@ func AssignLayers(g *dag.DAG) {
 		}
 	}
 
-	g.SetRows(rows)
+	return rows
+}
+
+// LongestPathFromSinks puts every sink on the bottom row and each other
+// node one row above its highest child, as low as it can go. Leaf libraries
+// then sit right under their users instead of far above the bottom.
+type LongestPathFromSinks struct{}
+
+func (LongestPathFromSinks) Layers(g *dag.DAG) map[string]int {
+	nodes := g.Nodes()
+	outDegree := make(map[string]int, len(nodes))
+	height := make(map[string]int, len(nodes))
+	queue := make([]string, 0, len(nodes))
+
+	for _, n := range nodes {
+		degree := g.OutDegree(n.ID)
+		outDegree[n.ID] = degree
+		if degree == 0 {
+			queue = append(queue, n.ID)
+		}
+	}
+
+	maxHeight := 0
+	for len(queue) > 0 {
+		curr := queue[0]
+		queue = queue[1:]
+		maxHeight = max(maxHeight, height[curr])
+
+		for _, parent := range g.Parents(curr) {
+			if h := height[curr] + 1; h > height[parent] {
+				height[parent] = h
+			}
+			outDegree[parent]--
+			if outDegree[parent] == 0 {
+				queue = append(queue, parent)
+			}
+		}
+	}
+
+	rows := make(map[string]int, len(nodes))
+	for id, h := range height {
+		rows[id] = maxHeight - h
+	}
+	for _, n := range nodes {
+		if _, ok := rows[n.ID]; !ok {
+			rows[n.ID] = maxHeight
+		}
+	}
+	return rows
 }
@@ -1,6 +1,8 @@
 package transform
 
 import (
+	"fmt"
+	"slices"
 	"testing"
 
 	"github.com/matzehuels/stacktower/pkg/dag"
@@ -190,3 +192,204 @@ func TestAssignLayers_FanInFanOut(t *testing.T) {
 	checkRow(t, g, "e", 2)
 	checkRow(t, g, "f", 3)
 }
+
+func TestLongestPathFromSinks(t *testing.T) {
+	// a → b → c → d, x → d: x sits right above d instead of on the top row.
+	g := buildGraph([]string{"a", "b", "c", "d", "x"},
+		[][2]string{{"a", "b"}, {"b", "c"}, {"c", "d"}, {"x", "d"}})
+
+	AssignLayersWith(g, LongestPathFromSinks{})
+
+	checkRow(t, g, "a", 0)
+	checkRow(t, g, "c", 2)
+	checkRow(t, g, "x", 2)
+	checkRow(t, g, "d", 3)
+}
+
+func TestCoffmanGraham_BoundsWidth(t *testing.T) {
+	// A root with six leaves would be seven wide with longest-path layering.
+	ids := []string{"root", "l1", "l2", "l3", "l4", "l5", "l6"}
+	var edges [][2]string
+	for _, id := range ids[1:] {
+		edges = append(edges, [2]string{"root", id})
+	}
+	g := buildGraph(ids, edges)
+
+	AssignLayersWith(g, CoffmanGraham{Width: 2})
+
+	checkLayering(t, g)
+	for _, r := range g.RowIDs() {
+		if n := len(g.NodesInRow(r)); n > 2 {
+			t.Errorf("row %d holds %d nodes, want at most 2", r, n)
+		}
+	}
+	checkRow(t, g, "root", 0)
+}
+
+func TestNetworkSimplex_MinimizesEdgeSpans(t *testing.T) {
+	graphs := map[string][][2]string{
+		"late source":  {{"a", "b"}, {"b", "c"}, {"c", "d"}, {"x", "d"}},
+		"shared leaf":  {{"a", "b"}, {"b", "c"}, {"a", "d"}, {"c", "e"}, {"d", "e"}, {"f", "d"}},
+		"two tiers":    {{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}, {"e", "c"}, {"e", "f"}},
+		"disconnected": {{"a", "b"}, {"b", "c"}, {"d", "e"}, {"f", "e"}},
+	}
+	for name, edges := range graphs {
+		t.Run(name, func(t *testing.T) {
+			g := buildGraph(nodesOf(edges), edges)
+			AssignLayersWith(g, NetworkSimplex{})
+
+			checkLayering(t, g)
+			if got, want := totalSpan(g, nil), minimumSpan(g); got != want {
+				t.Errorf("total edge span = %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestNetworkSimplex_IncrementalUpdates(t *testing.T) {
+	g := randomDAG(300, 3, 7)
+	ids := dag.NodeIDs(g.Nodes())
+	slices.Sort(ids)
+	s := newSimplexGraph(g, ids, LongestPath{}.Layers(g))
+	s.feasibleTree()
+	s.buildTree()
+	s.cutValues()
+
+	// After every pivot the updated tree must match one rebuilt from
+	// scratch, and every cut value its definition.
+	for pivot := range 100 {
+		parents, sizes, cut := slices.Clone(s.parentEdge), subtreeSizes(s), slices.Clone(s.cut)
+		s.buildTree()
+		if !slices.Equal(parents, s.parentEdge) || !slices.Equal(sizes, subtreeSizes(s)) {
+			t.Fatalf("pivot %d: rooted tree drifted", pivot)
+		}
+		for e := range s.edges {
+			if !s.inTree[e] {
+				continue
+			}
+			if s.slack(e) != 0 {
+				t.Fatalf("pivot %d: tree edge %d has slack %d", pivot, e, s.slack(e))
+			}
+			if want := bruteCutValue(s, e); cut[e] != want {
+				t.Fatalf("pivot %d: edge %d has cut value %d, want %d", pivot, e, cut[e], want)
+			}
+		}
+
+		leave := s.leaveEdge()
+		if leave < 0 {
+			return
+		}
+		enter := s.enterEdge(leave)
+		if enter < 0 {
+			t.Fatalf("pivot %d: no edge to enter", pivot)
+		}
+		s.exchange(leave, enter)
+	}
+}
+
+func BenchmarkNetworkSimplex(b *testing.B) {
+	for _, n := range []int{1_000, 5_000} {
+		b.Run(fmt.Sprintf("nodes=%d", n), func(b *testing.B) {
+			g := randomDAG(n, 4, 1)
+			for b.Loop() {
+				NetworkSimplex{}.Layers(g)
+			}
+		})
+	}
+}
+
+func subtreeSizes(s *simplexGraph) []int {
+	sizes := make([]int, len(s.ids))
+	for v := range sizes {
+		sizes[v] = len(s.subtree(v))
+	}
+	return sizes
+}
+
+// bruteCutValue counts every edge crossing the cut of a tree edge.
+func bruteCutValue(s *simplexGraph, tree int) int {
+	child := s.below(tree)
+	tailBelow := s.edges[tree].from == child
+
+	cut := 0
+	for _, edge := range s.edges {
+		fromBelow, toBelow := s.inSubtree(edge.from, child), s.inSubtree(edge.to, child)
+		switch {
+		case fromBelow == toBelow:
+		case fromBelow == tailBelow:
+			cut++
+		default:
+			cut--
+		}
+	}
+	return cut
+}
+
+func nodesOf(edges [][2]string) []string {
+	var ids []string
+	for _, e := range edges {
+		for _, id := range e {
+			if !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// checkLayering verifies every edge points down and rows start at zero.
+func checkLayering(t *testing.T, g *dag.DAG) {
+	t.Helper()
+	for _, e := range g.Edges() {
+		from, _ := g.Node(e.From)
+		to, _ := g.Node(e.To)
+		if to.Row <= from.Row {
+			t.Errorf("edge %s->%s points from row %d to row %d", e.From, e.To, from.Row, to.Row)
+		}
+	}
+	if len(g.NodesInRow(0)) == 0 {
+		t.Error("row 0 is empty")
+	}
+}
+
+func totalSpan(g *dag.DAG, rows map[string]int) int {
+	row := func(id string) int {
+		if rows != nil {
+			return rows[id]
+		}
+		n, _ := g.Node(id)
+		return n.Row
+	}
+	total := 0
+	for _, e := range g.Edges() {
+		total += row(e.To) - row(e.From)
+	}
+	return total
+}
+
+// minimumSpan brute-forces the least total span of any valid layering.
+func minimumSpan(g *dag.DAG) int {
+	ids := dag.NodeIDs(g.Nodes())
+	rows := make(map[string]int, len(ids))
+	best := -1
+	var try func(i int)
+	try = func(i int) {
+		if i == len(ids) {
+			for _, e := range g.Edges() {
+				if rows[e.To] <= rows[e.From] {
+					return
+				}
+			}
+			if span := totalSpan(g, rows); best < 0 || span < best {
+				best = span
+			}
+			return
+		}
+		for r := range len(ids) {
+			rows[ids[i]] = r
+			try(i + 1)
+		}
+	}
+	try(0)
+	return best
+}
@@ -0,0 +1,372 @@
+package transform
+
+import (
+	"slices"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+)
+
+// NetworkSimplex minimizes the total number of rows edges span (Gansner et
+// al., "A Technique for Drawing Directed Graphs"), which keeps dependencies
+// next to their users and so needs the fewest subdividers. MaxIterations
+// bounds the pivots; zero or less means ten per node.
+type NetworkSimplex struct {
+	MaxIterations int
+}
+
+func (ns NetworkSimplex) Layers(g *dag.DAG) map[string]int {
+	rows := LongestPath{}.Layers(g)
+	ids := dag.NodeIDs(g.Nodes())
+	slices.Sort(ids)
+
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		comp := newSimplexGraph(g, component(g, id, seen), rows)
+		maxIter := ns.MaxIterations
+		if maxIter <= 0 {
+			maxIter = 10 * len(comp.ids)
+		}
+		comp.solve(maxIter)
+
+		low := comp.rank[0]
+		for _, r := range comp.rank {
+			low = min(low, r)
+		}
+		for i, id := range comp.ids {
+			rows[id] = comp.rank[i] - low
+		}
+	}
+	return rows
+}
+
+// component collects the weakly connected component holding id, sorted.
+func component(g *dag.DAG, id string, seen map[string]bool) []string {
+	seen[id] = true
+	stack, out := []string{id}, []string{id}
+	for len(stack) > 0 {
+		curr := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, next := range append(slices.Clip(g.Children(curr)), g.Parents(curr)...) {
+			if !seen[next] {
+				seen[next] = true
+				out = append(out, next)
+				stack = append(stack, next)
+			}
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+type simplexEdge struct{ from, to int }
+
+type simplexGraph struct {
+	ids    []string
+	rank   []int
+	edges  []simplexEdge
+	adj    [][]int // edge indices incident to each node
+	inTree []bool  // per edge
+
+	// Rooted view of the spanning tree, kept up to date across pivots: a
+	// node's subtree holds exactly the nodes whose lim lies in [low, lim],
+	// which postorder lists in order. cut holds each tree edge's cut value.
+	treeAdj    [][]int // tree edge indices incident to each node
+	parentEdge []int
+	low, lim   []int
+	postorder  []int
+	cut        []int
+	search     int // edge leaveEdge resumes its scan from
+}
+
+func newSimplexGraph(g *dag.DAG, ids []string, rows map[string]int) *simplexGraph {
+	index := dag.PosMap(ids)
+	s := &simplexGraph{
+		ids:  ids,
+		rank: make([]int, len(ids)),
+		adj:  make([][]int, len(ids)),
+	}
+	for i, id := range ids {
+		s.rank[i] = rows[id]
+		for _, c := range g.Children(id) {
+			e := len(s.edges)
+			s.edges = append(s.edges, simplexEdge{i, index[c]})
+			s.adj[i] = append(s.adj[i], e)
+			s.adj[index[c]] = append(s.adj[index[c]], e)
+		}
+	}
+	s.inTree = make([]bool, len(s.edges))
+	return s
+}
+
+func (s *simplexGraph) slack(e int) int {
+	return s.rank[s.edges[e].to] - s.rank[s.edges[e].from] - 1
+}
+
+func (s *simplexGraph) solve(maxIter int) {
+	if len(s.ids) < 2 {
+		return
+	}
+	s.feasibleTree()
+	s.buildTree()
+	s.cutValues()
+	for range maxIter {
+		leave := s.leaveEdge()
+		if leave < 0 {
+			return
+		}
+		enter := s.enterEdge(leave)
+		if enter < 0 {
+			return
+		}
+		s.exchange(leave, enter)
+	}
+}
+
+// feasibleTree grows a spanning tree of tight edges from node 0, shifting
+// the tree's ranks to tighten the least slack edge leaving it whenever it
+// gets stuck.
+func (s *simplexGraph) feasibleTree() {
+	tree := make([]bool, len(s.ids))
+	tree[0] = true
+	size := 1
+	for {
+		stack := make([]int, 0, len(s.ids))
+		for v := range s.ids {
+			if tree[v] {
+				stack = append(stack, v)
+			}
+		}
+		for len(stack) > 0 {
+			v := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, e := range s.adj[v] {
+				w := s.other(e, v)
+				if !tree[w] && s.slack(e) == 0 {
+					tree[w], s.inTree[e] = true, true
+					size++
+					stack = append(stack, w)
+				}
+			}
+		}
+		if size == len(s.ids) {
+			return
+		}
+
+		best, delta := -1, 0
+		for e, edge := range s.edges {
+			if tree[edge.from] == tree[edge.to] {
+				continue
+			}
+			if best < 0 || s.slack(e) < s.slack(best) {
+				best = e
+			}
+		}
+		delta = s.slack(best)
+		if tree[s.edges[best].to] {
+			delta = -delta
+		}
+		for v := range s.ids {
+			if tree[v] {
+				s.rank[v] += delta
+			}
+		}
+	}
+}
+
+func (s *simplexGraph) other(e, v int) int {
+	if s.edges[e].from == v {
+		return s.edges[e].to
+	}
+	return s.edges[e].from
+}
+
+// buildTree roots the spanning tree at node 0 and numbers it in postorder.
+func (s *simplexGraph) buildTree() {
+	n := len(s.ids)
+	s.treeAdj = make([][]int, n)
+	for e, edge := range s.edges {
+		if s.inTree[e] {
+			s.treeAdj[edge.from] = append(s.treeAdj[edge.from], e)
+			s.treeAdj[edge.to] = append(s.treeAdj[edge.to], e)
+		}
+	}
+	s.parentEdge = make([]int, n)
+	s.low, s.lim = make([]int, n), make([]int, n)
+	s.postorder = make([]int, n)
+	s.number(0, -1, 0)
+}
+
+// number labels the subtree of v, entered through tree edge via, in
+// postorder from next on, and returns the next free number.
+func (s *simplexGraph) number(v, via, next int) int {
+	s.parentEdge[v] = via
+	s.low[v] = next
+	for _, e := range s.treeAdj[v] {
+		if e != via {
+			next = s.number(s.other(e, v), e, next)
+		}
+	}
+	s.lim[v] = next
+	s.postorder[next] = v
+	return next + 1
+}
+
+// below returns the endpoint of tree edge e farther from the root.
+func (s *simplexGraph) below(e int) int {
+	if s.parentEdge[s.edges[e].to] == e {
+		return s.edges[e].to
+	}
+	return s.edges[e].from
+}
+
+// subtree lists the nodes in v's subtree.
+func (s *simplexGraph) subtree(v int) []int {
+	return s.postorder[s.low[v] : s.lim[v]+1]
+}
+
+func (s *simplexGraph) inSubtree(v, root int) bool {
+	return s.low[root] <= s.lim[v] && s.lim[v] <= s.lim[root]
+}
+
+// cutValues sets the cut value of every tree edge: the edges crossing from
+// its tail component to its head component, minus those crossing back. A
+// negative value means lengthening the tree edge shortens the drawing.
+//
+// Rather than testing every edge against every tree edge, one postorder
+// pass derives the value of a node's edge to its parent from the node's
+// own edges and the already known values of its children's tree edges
+// (Gansner et al., section 2.4), in time linear in the graph.
+func (s *simplexGraph) cutValues() {
+	if len(s.cut) != len(s.edges) {
+		s.cut = make([]int, len(s.edges))
+	}
+	for _, v := range s.postorder {
+		tree := s.parentEdge[v]
+		if tree < 0 {
+			continue
+		}
+		vIsTail := s.edges[tree].from == v
+		cut := 1
+		for _, e := range s.adj[v] {
+			if e == tree {
+				continue
+			}
+			// An edge leaving v's side in the same direction as the tree
+			// edge counts for it; one in the opposite direction against.
+			// A child's tree edge also brings its own crossings, which
+			// were counted from the child's side.
+			sameWay := (s.edges[e].from == v) == vIsTail
+			switch {
+			case sameWay && s.inTree[e]:
+				cut += 1 - s.cut[e]
+			case sameWay:
+				cut++
+			case s.inTree[e]:
+				cut += s.cut[e] - 1
+			default:
+				cut--
+			}
+		}
+		s.cut[tree] = cut
+	}
+}
+
+// leaveSearch is how many negative tree edges leaveEdge weighs before
+// settling on the most negative, as in Graphviz.
+const leaveSearch = 30
+
+// leaveEdge picks a tree edge with a negative cut value. The scan resumes
+// where the last one stopped, so edges early in the list don't keep
+// winning, and settles on the most negative of the first few it meets.
+func (s *simplexGraph) leaveEdge() int {
+	best, found := -1, 0
+	for i := range s.edges {
+		e := (s.search + i) % len(s.edges)
+		if !s.inTree[e] || s.cut[e] >= 0 {
+			continue
+		}
+		if best < 0 || s.cut[e] < s.cut[best] {
+			best = e
+		}
+		if found++; found == leaveSearch {
+			break
+		}
+	}
+	if best >= 0 {
+		s.search = best + 1
+	}
+	return best
+}
+
+// enterEdge picks the least slack edge crossing from the leaving edge's
+// head component back to its tail component. Such an edge has exactly one
+// endpoint below the leaving edge, so only that subtree is searched.
+func (s *simplexGraph) enterEdge(leave int) int {
+	child := s.below(leave)
+	tailBelow := s.edges[leave].from == child
+
+	best := -1
+	for _, v := range s.subtree(child) {
+		for _, e := range s.adj[v] {
+			if s.inTree[e] || s.inSubtree(s.other(e, v), child) {
+				continue
+			}
+			if fromBelow := s.edges[e].from == v; fromBelow == tailBelow {
+				continue
+			}
+			if best < 0 || s.slack(e) < s.slack(best) || (s.slack(e) == s.slack(best) && e < best) {
+				best = e
+			}
+		}
+	}
+	return best
+}
+
+// exchange swaps the entering edge into the tree for the leaving one. Only
+// what the pivot touches is updated (Gansner et al., section 2.4): the
+// ranks of the subtree below the leaving edge, which shift to tighten the
+// entering edge; the cut values along the tree path the entering edge
+// closes; and the numbering of the subtree holding that path.
+func (s *simplexGraph) exchange(leave, enter int) {
+	child := s.below(leave)
+	delta := s.slack(enter)
+	if s.edges[leave].from == child {
+		delta = -delta
+	}
+	for _, v := range s.subtree(child) {
+		s.rank[v] += delta
+	}
+
+	cut := s.cut[leave]
+	lca := s.updatePath(s.edges[enter].from, s.edges[enter].to, cut, true)
+	s.updatePath(s.edges[enter].to, s.edges[enter].from, cut, false)
+	s.cut[enter], s.cut[leave] = -cut, 0
+	s.inTree[leave], s.inTree[enter] = false, true
+	for _, v := range []int{s.edges[leave].from, s.edges[leave].to} {
+		s.treeAdj[v] = slices.DeleteFunc(s.treeAdj[v], func(e int) bool { return e == leave })
+	}
+	for _, v := range []int{s.edges[enter].from, s.edges[enter].to} {
+		s.treeAdj[v] = append(s.treeAdj[v], enter)
+	}
+	s.number(lca, s.parentEdge[lca], s.low[lca])
+}
+
+// updatePath climbs from v until its subtree holds w, shifting the cut
+// value of each tree edge passed by cut: up for edges pointing the same
+// way as the walk when forward, down otherwise. It returns where it
+// stopped, the lowest common ancestor of v and w.
+func (s *simplexGraph) updatePath(v, w, cut int, forward bool) int {
+	for !s.inSubtree(w, v) {
+		e := s.parentEdge[v]
+		if (s.edges[e].from == v) == forward {
+			s.cut[e] += cut
+		} else {
+			s.cut[e] -= cut
+		}
+		v = s.other(e, v)
+	}
+	return v
+}
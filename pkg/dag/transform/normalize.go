@@ -3,8 +3,14 @@ package transform
 import "github.com/matzehuels/stacktower/pkg/dag"
 
 func Normalize(g *dag.DAG) *dag.DAG {
+	return NormalizeWith(g, LongestPath{})
+}
+
+// NormalizeWith normalizes g like Normalize, assigning rows with l.
+func NormalizeWith(g *dag.DAG, l Layerer) *dag.DAG {
+	BreakCycles(g)
 	TransitiveReduction(g)
-	AssignLayers(g)
+	AssignLayersWith(g, l)
 	Subdivide(g)
 	ResolveSpanOverlaps(g)
 	return g
@@ -1,15 +1,97 @@
 package transform
 
-import "github.com/matzehuels/stacktower/pkg/dag"
+import (
+	"math/bits"
 
+	"github.com/matzehuels/stacktower/pkg/dag"
+)
+
+// reachabilityBudget caps the bytes reachability bitsets take at once.
+// Larger graphs are reduced in blocks of target columns.
+var reachabilityBudget = 64 << 20
+
+// TransitiveReduction removes every edge u→v for which v is also reachable
+// through another child of u. Nodes on a cycle keep all their edges.
 func TransitiveReduction(g *dag.DAG) {
 	nodes := g.Nodes()
-	if len(nodes) == 0 {
+	n := len(nodes)
+	if n == 0 {
 		return
 	}
 
 	nodeIndex := dag.NodePosMap(nodes)
-	adjacency := make([][]int, len(nodes))
+	adjacency := adjacencyOf(g, nodeIndex)
+
+	order := topologicalOrder(adjacency)
+	blockWidth := 64 * max(1, reachabilityBudget/8/n)
+
+	type edgeKey struct{ from, to int }
+	redundant := make(map[edgeKey]bool)
+	backing := make([]uint64, n*(min(blockWidth, n+63)/64))
+	for lo := 0; lo < n; lo += blockWidth {
+		hi := min(n, lo+blockWidth)
+		clear(backing)
+		reach := reachabilityBlock(adjacency, order, lo, hi, backing)
+		for src, children := range adjacency {
+			for _, dst := range children {
+				if dst < lo || dst >= hi {
+					continue
+				}
+				for _, intermediate := range children {
+					if intermediate != dst && reach[intermediate].has(dst-lo) {
+						redundant[edgeKey{src, dst}] = true
+						break
+					}
+				}
+			}
+		}
+	}
+
+	if len(redundant) > 0 {
+		g.RemoveEdgesFunc(func(e dag.Edge) bool {
+			return redundant[edgeKey{nodeIndex[e.From], nodeIndex[e.To]}]
+		})
+	}
+}
+
+// AncestorCounts returns, for every node, how many others reach it. It
+// reuses TransitiveReduction's reachability bitsets, filled in one reverse
+// topological pass per block of target columns, so it stays within the
+// same memory budget. Nodes on a cycle, and those only reachable through
+// one, are not counted as reaching anything.
+func AncestorCounts(g *dag.DAG) map[string]int {
+	nodes := g.Nodes()
+	n := len(nodes)
+	counts := make(map[string]int, n)
+	if n == 0 {
+		return counts
+	}
+
+	adjacency := adjacencyOf(g, dag.NodePosMap(nodes))
+	order := topologicalOrder(adjacency)
+	blockWidth := 64 * max(1, reachabilityBudget/8/n)
+
+	column := make([]int, n)
+	backing := make([]uint64, n*(min(blockWidth, n+63)/64))
+	for lo := 0; lo < n; lo += blockWidth {
+		hi := min(n, lo+blockWidth)
+		clear(backing)
+		for _, set := range reachabilityBlock(adjacency, order, lo, hi, backing) {
+			for w, word := range set {
+				for ; word != 0; word &= word - 1 {
+					column[lo+64*w+bits.TrailingZeros64(word)]++
+				}
+			}
+		}
+	}
+	for i, node := range nodes {
+		counts[node.ID] = column[i]
+	}
+	return counts
+}
+
+func adjacencyOf(g *dag.DAG, nodeIndex map[string]int) [][]int {
+	adjacency := make([][]int, len(nodeIndex))
 	for _, e := range g.Edges() {
 		if src, ok := nodeIndex[e.From]; ok {
 			if dst, ok := nodeIndex[e.To]; ok {
@@ -17,40 +99,72 @@ func TransitiveReduction(g *dag.DAG) {
 			}
 		}
 	}
+	return adjacency
+}
 
-	reachability := computeReachability(adjacency)
+// computeReachability returns, for every node, the set of nodes it reaches.
+func computeReachability(adjacency [][]int) []bitset {
+	n := len(adjacency)
+	return reachabilityBlock(adjacency, topologicalOrder(adjacency), 0, n, make([]uint64, n*((n+63)/64)))
+}
 
-	for _, e := range g.Edges() {
-		src, dst := nodeIndex[e.From], nodeIndex[e.To]
-		for _, intermediate := range adjacency[src] {
-			if intermediate != dst && reachability[intermediate][dst] {
-				g.RemoveEdge(e.From, e.To)
-				break
+// reachabilityBlock computes which of the nodes lo..hi-1 each node reaches,
+// bit i standing for node lo+i. Children are merged into their parents in
+// reverse topological order, so each edge is visited once. backing must
+// be zeroed and hold a word per 64 columns for every node.
+func reachabilityBlock(adjacency [][]int, order []int, lo, hi int, backing []uint64) []bitset {
+	words := (hi - lo + 63) / 64
+	reach := make([]bitset, len(adjacency))
+	for i := range reach {
+		reach[i] = backing[i*words : (i+1)*words : (i+1)*words]
+	}
+
+	for i := len(order) - 1; i >= 0; i-- {
+		u := order[i]
+		for _, c := range adjacency[u] {
+			reach[u].or(reach[c])
+			if c >= lo && c < hi {
+				reach[u].set(c - lo)
 			}
 		}
 	}
+	return reach
 }
 
-func computeReachability(adjacency [][]int) [][]bool {
-	n := len(adjacency)
-	reachable := make([][]bool, n)
-	for i := range reachable {
-		reachable[i] = make([]bool, n)
+// topologicalOrder sorts nodes parents first. Nodes on a cycle, and those
+// only reachable through one, are left out.
+func topologicalOrder(adjacency [][]int) []int {
+	inDegree := make([]int, len(adjacency))
+	for _, children := range adjacency {
+		for _, c := range children {
+			inDegree[c]++
+		}
 	}
 
-	var dfs func(source, current int)
-	dfs = func(source, current int) {
-		if reachable[source][current] {
-			return
+	order := make([]int, 0, len(adjacency))
+	for v, d := range inDegree {
+		if d == 0 {
+			order = append(order, v)
 		}
-		reachable[source][current] = true
-		for _, next := range adjacency[current] {
-			dfs(source, next)
+	}
+	for i := 0; i < len(order); i++ {
+		for _, c := range adjacency[order[i]] {
+			inDegree[c]--
+			if inDegree[c] == 0 {
+				order = append(order, c)
+			}
 		}
 	}
+	return order
+}
+
+type bitset []uint64
+
+func (b bitset) set(i int)      { b[i/64] |= 1 << (i % 64) }
+func (b bitset) has(i int) bool { return b[i/64]&(1<<(i%64)) != 0 }
 
-	for i := range reachable {
-		dfs(i, i)
+func (b bitset) or(other bitset) {
+	for i, w := range other {
+		b[i] |= w
 	}
-	return reachable
 }
@@ -1,6 +1,9 @@
 package transform
 
 import (
+	"fmt"
+	"math/rand"
+	"slices"
 	"testing"
 
 	"github.com/matzehuels/stacktower/pkg/dag"
@@ -180,13 +183,13 @@ func TestComputeReachability_SimpleChain(t *testing.T) {
 
 	reach := computeReachability(adj)
 
-	if !reach[0][1] || !reach[0][2] {
+	if !reach[0].has(1) || !reach[0].has(2) {
 		t.Error("node 0 should reach nodes 1 and 2")
 	}
-	if !reach[1][2] {
+	if !reach[1].has(2) {
 		t.Error("node 1 should reach node 2")
 	}
-	if reach[2][0] || reach[2][1] {
+	if reach[2].has(0) || reach[2].has(1) {
 		t.Error("node 2 should not reach any nodes")
 	}
 }
@@ -201,13 +204,90 @@ func TestComputeReachability_Diamond(t *testing.T) {
 
 	reach := computeReachability(adj)
 
-	if !reach[0][3] {
+	if !reach[0].has(3) {
 		t.Error("node 0 should reach node 3 through multiple paths")
 	}
-	if !reach[1][3] {
+	if !reach[1].has(3) {
 		t.Error("node 1 should reach node 3")
 	}
-	if !reach[2][3] {
+	if !reach[2].has(3) {
 		t.Error("node 2 should reach node 3")
 	}
 }
+
+func TestTransitiveReduction_Blocked(t *testing.T) {
+	want := edgeList(reduced(randomDAG(600, 4, 1)))
+
+	defer func(budget int) { reachabilityBudget = budget }(reachabilityBudget)
+	reachabilityBudget = 1 // one 64-column block at a time
+	if got := edgeList(reduced(randomDAG(600, 4, 1))); !slices.Equal(got, want) {
+		t.Errorf("blocked reduction kept %d edges, unblocked %d", len(got), len(want))
+	}
+}
+
+func TestAncestorCounts(t *testing.T) {
+	g := randomDAG(600, 4, 2)
+
+	defer func(budget int) { reachabilityBudget = budget }(reachabilityBudget)
+	for _, budget := range []int{reachabilityBudget, 1} {
+		reachabilityBudget = budget
+		counts := AncestorCounts(g)
+		for _, n := range g.Nodes() {
+			if want := len(g.Ancestors(n.ID)); counts[n.ID] != want {
+				t.Fatalf("budget %d: %s has %d ancestors, want %d", budget, n.ID, counts[n.ID], want)
+			}
+		}
+	}
+}
+
+func TestTransitiveReduction_CycleKeepsEdges(t *testing.T) {
+	g := buildGraph([]string{"a", "b", "c"}, [][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}, {"a", "c"}})
+	TransitiveReduction(g)
+	if g.EdgeCount() != 4 {
+		t.Errorf("edges on a cycle should be kept, got %d", g.EdgeCount())
+	}
+}
+
+func BenchmarkTransitiveReduction(b *testing.B) {
+	for _, n := range []int{1_000, 10_000, 50_000} {
+		b.Run(fmt.Sprintf("nodes=%d", n), func(b *testing.B) {
+			for b.Loop() {
+				b.StopTimer()
+				g := randomDAG(n, 4, 1)
+				b.StartTimer()
+				TransitiveReduction(g)
+			}
+		})
+	}
+}
+
+// randomDAG links each node to up to degree earlier nodes, mostly nearby
+// ones, the way package graphs cluster.
+func randomDAG(n, degree int, seed int64) *dag.DAG {
+	r := rand.New(rand.NewSource(seed))
+	g := dag.New(nil)
+	for i := range n {
+		_ = g.AddNode(dag.Node{ID: fmt.Sprintf("p%d", i)})
+	}
+	for i := 1; i < n; i++ {
+		for range 1 + r.Intn(degree) {
+			j := max(0, i-1-r.Intn(min(i, 200)))
+			_ = g.AddEdge(dag.Edge{From: fmt.Sprintf("p%d", j), To: fmt.Sprintf("p%d", i)})
+		}
+	}
+	return g
+}
+
+func reduced(g *dag.DAG) *dag.DAG {
+	TransitiveReduction(g)
+	return g
+}
+
+func edgeList(g *dag.DAG) []string {
+	var out []string
+	for _, e := range g.Edges() {
+		out = append(out, e.From+"->"+e.To)
+	}
+	slices.Sort(out)
+	return out
+}
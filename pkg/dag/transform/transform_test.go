@@ -6,6 +6,17 @@ import (
 	"github.com/matzehuels/stacktower/pkg/dag"
 )
 
+func buildGraph(ids []string, edges [][2]string) *dag.DAG {
+	g := dag.New(nil)
+	for _, id := range ids {
+		_ = g.AddNode(dag.Node{ID: id})
+	}
+	for _, e := range edges {
+		_ = g.AddEdge(dag.Edge{From: e[0], To: e[1]})
+	}
+	return g
+}
+
 func buildSimpleDAG() *dag.DAG {
 	g := dag.New(nil)
 	_ = g.AddNode(dag.Node{ID: "a", Row: 0})
@@ -0,0 +1,62 @@
+package health
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+)
+
+// Key is the metadata key Annotate stores each node's Result under.
+const Key = "health"
+
+type Finding struct {
+	Package string `json:"package"`
+	Result
+}
+
+type Report struct {
+	Packages []Finding `json:"packages"`
+	Unscored []string  `json:"unscored,omitempty"`
+}
+
+// Assess scores every package in g, least healthy first.
+func Assess(g *dag.DAG, p Policy) Report {
+	var r Report
+	now := time.Now()
+	for _, n := range g.Nodes() {
+		if n.IsSynthetic() {
+			continue
+		}
+		res, ok := p.score(n, now)
+		if !ok {
+			r.Unscored = append(r.Unscored, n.ID)
+			continue
+		}
+		r.Packages = append(r.Packages, Finding{Package: n.ID, Result: res})
+	}
+
+	slices.SortFunc(r.Packages, func(a, b Finding) int {
+		return cmp.Or(cmp.Compare(a.Score, b.Score), cmp.Compare(a.Package, b.Package))
+	})
+	slices.Sort(r.Unscored)
+	return r
+}
+
+// Annotate stores the Result of every scorable package in g under Key, so
+// the scores travel with the graph, and returns how many it scored.
+func Annotate(g *dag.DAG, p Policy) int {
+	scored := 0
+	now := time.Now()
+	for _, n := range g.Nodes() {
+		if n.IsSynthetic() {
+			continue
+		}
+		if res, ok := p.score(n, now); ok {
+			n.Meta[Key] = res
+			scored++
+		}
+	}
+	return scored
+}
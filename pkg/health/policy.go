@@ -0,0 +1,99 @@
+package health
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Policy weighs and bounds the signals that make up a health score. Each
+// signal scores 1 up to its "fresh" or "healthy" bound and falls linearly
+// to 0 at its "stale" bound. The release bounds apply to the interval
+// between releases.
+type Policy struct {
+	Weights Weights `json:"weights"`
+
+	CommitFreshDays  int `json:"commit_fresh_days"`
+	CommitStaleDays  int `json:"commit_stale_days"`
+	ReleaseFreshDays int `json:"release_fresh_days"`
+	ReleaseStaleDays int `json:"release_stale_days"`
+	// IssueFreshDays and IssueStaleDays bound the median time to close
+	// recent issues.
+	IssueFreshDays int `json:"issue_fresh_days"`
+	IssueStaleDays int `json:"issue_stale_days"`
+
+	// MaxTopShare is the largest share of recent commits by a single
+	// contributor that still counts as a healthy bus factor.
+	MaxTopShare float64 `json:"max_top_share"`
+	// MinCloseRatio is the share of all issues closed at which issue
+	// handling scores full marks, when close times are unknown.
+	MinCloseRatio float64 `json:"min_close_ratio"`
+	// ArchivedCap bounds the score of archived repositories, 0 to 100.
+	ArchivedCap int `json:"archived_cap"`
+}
+
+type Weights struct {
+	Commits      float64 `json:"commits"`
+	Releases     float64 `json:"releases"`
+	Contributors float64 `json:"contributors"`
+	Issues       float64 `json:"issues"`
+}
+
+func DefaultPolicy() Policy {
+	return Policy{
+		Weights:          Weights{Commits: 0.35, Releases: 0.2, Contributors: 0.3, Issues: 0.15},
+		CommitFreshDays:  90,
+		CommitStaleDays:  730,
+		ReleaseFreshDays: 180,
+		ReleaseStaleDays: 1095,
+		IssueFreshDays:   14,
+		IssueStaleDays:   365,
+		MaxTopShare:      0.5,
+		MinCloseRatio:    0.8,
+		ArchivedCap:      0,
+	}
+}
+
+// LoadPolicy reads a JSON policy. Fields missing from the file keep their
+// DefaultPolicy values.
+func LoadPolicy(path string) (Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Policy{}, err
+	}
+	p := DefaultPolicy()
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&p); err != nil {
+		return Policy{}, fmt.Errorf("policy %s: %w", path, err)
+	}
+	if err := p.validate(); err != nil {
+		return Policy{}, fmt.Errorf("policy %s: %w", path, err)
+	}
+	return p, nil
+}
+
+func (p Policy) validate() error {
+	w := p.Weights
+	switch {
+	case w.Commits < 0 || w.Releases < 0 || w.Contributors < 0 || w.Issues < 0:
+		return errors.New("weights must not be negative")
+	case w.Commits+w.Releases+w.Contributors+w.Issues == 0:
+		return errors.New("at least one weight must be positive")
+	case p.CommitStaleDays <= p.CommitFreshDays:
+		return errors.New("commit_stale_days must exceed commit_fresh_days")
+	case p.ReleaseStaleDays <= p.ReleaseFreshDays:
+		return errors.New("release_stale_days must exceed release_fresh_days")
+	case p.IssueStaleDays <= p.IssueFreshDays:
+		return errors.New("issue_stale_days must exceed issue_fresh_days")
+	case p.MaxTopShare <= 0 || p.MaxTopShare >= 1:
+		return errors.New("max_top_share must be between 0 and 1")
+	case p.MinCloseRatio <= 0 || p.MinCloseRatio > 1:
+		return errors.New("min_close_ratio must be in (0, 1]")
+	case p.ArchivedCap < 0 || p.ArchivedCap > 100:
+		return errors.New("archived_cap must be between 0 and 100")
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package health
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadPolicy(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "health.json")
+	os.WriteFile(path, []byte(`{"weights": {"commits": 1}, "commit_stale_days": 365, "archived_cap": 20}`), 0o644)
+
+	p, err := LoadPolicy(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Weights.Commits != 1 || p.Weights.Releases != DefaultPolicy().Weights.Releases {
+		t.Errorf("unexpected weights %+v", p.Weights)
+	}
+	if p.CommitStaleDays != 365 || p.ArchivedCap != 20 {
+		t.Errorf("unexpected overrides %+v", p)
+	}
+	if p.ReleaseStaleDays != DefaultPolicy().ReleaseStaleDays {
+		t.Error("missing fields should keep their defaults")
+	}
+
+	invalid := map[string]string{
+		"unknown field":       `{"stars": 1}`,
+		"zero weights":        `{"weights": {"commits": 0, "releases": 0, "contributors": 0, "issues": 0}}`,
+		"inverted days":       `{"commit_fresh_days": 900}`,
+		"inverted issue days": `{"issue_fresh_days": 400}`,
+		"bad share":           `{"max_top_share": 1}`,
+	}
+	for name, body := range invalid {
+		os.WriteFile(path, []byte(body), 0o644)
+		if _, err := LoadPolicy(path); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
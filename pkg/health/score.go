@@ -0,0 +1,253 @@
+package health
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+)
+
+type Signal string
+
+const (
+	SignalCommits      Signal = "commits"
+	SignalReleases     Signal = "releases"
+	SignalContributors Signal = "contributors"
+	SignalIssues       Signal = "issues"
+)
+
+type Grade string
+
+const (
+	GradeA Grade = "A"
+	GradeB Grade = "B"
+	GradeC Grade = "C"
+	GradeD Grade = "D"
+	GradeF Grade = "F"
+)
+
+// Grades lists every grade from healthiest to least healthy.
+var Grades = []Grade{GradeA, GradeB, GradeC, GradeD, GradeF}
+
+// GradeFor buckets a 0–100 score into 20-point grades.
+func GradeFor(score int) Grade {
+	switch {
+	case score >= 80:
+		return GradeA
+	case score >= 60:
+		return GradeB
+	case score >= 40:
+		return GradeC
+	case score >= 20:
+		return GradeD
+	default:
+		return GradeF
+	}
+}
+
+// Result is a node's score from 0 (unmaintained) to 100 alongside the
+// per-signal scores, each 0 to 1, that produced it. Signals without data
+// are listed in Missing and their weight is spread over the others.
+// Approximate lists the signals scored from a stand-in: release recency
+// when there is no release history, and the share of closed issues when
+// close times are unknown.
+type Result struct {
+	Score       int                `json:"score"`
+	Grade       Grade              `json:"grade"`
+	Signals     map[Signal]float64 `json:"signals"`
+	Missing     []Signal           `json:"missing,omitempty"`
+	Approximate []Signal           `json:"approximate,omitempty"`
+	Archived    bool               `json:"archived,omitempty"`
+}
+
+// cadenceWindow is how many of the latest release intervals set the
+// release cadence.
+const cadenceWindow = 10
+
+// Score rates n under the policy. It reports false when the node carries
+// none of the repository metadata the signals need.
+func (p Policy) Score(n *dag.Node) (Result, bool) {
+	return p.score(n, time.Now())
+}
+
+func (p Policy) score(n *dag.Node, now time.Time) (Result, bool) {
+	if n == nil || n.Meta == nil {
+		return Result{}, false
+	}
+	m := n.Meta
+
+	signals := make(map[Signal]float64)
+	var approximate []Signal
+	if t := parseDate(m["repo_last_commit"]); !t.IsZero() {
+		signals[SignalCommits] = decay(now.Sub(t), p.CommitFreshDays, p.CommitStaleDays)
+	}
+	if interval, ok := releaseInterval(releaseDates(m["releases"]), now); ok {
+		signals[SignalReleases] = decay(interval, p.ReleaseFreshDays, p.ReleaseStaleDays)
+	} else if t := parseDate(m["repo_last_release"]); !t.IsZero() {
+		signals[SignalReleases] = decay(now.Sub(t), p.ReleaseFreshDays, p.ReleaseStaleDays)
+		approximate = append(approximate, SignalReleases)
+	}
+	if share, ok := topShare(m["repo_contributions"], m["repo_maintainers"]); ok {
+		signals[SignalContributors] = clamp((1 - share) / (1 - p.MaxTopShare))
+	}
+	open, closed := asInt(m["repo_open_issues"]), asInt(m["repo_closed_issues"])
+	if days := asFloat(m["repo_issue_close_days"]); days > 0 {
+		signals[SignalIssues] = decay(time.Duration(days*24*float64(time.Hour)), p.IssueFreshDays, p.IssueStaleDays)
+	} else if open+closed > 0 {
+		signals[SignalIssues] = clamp(float64(closed) / float64(open+closed) / p.MinCloseRatio)
+		approximate = append(approximate, SignalIssues)
+	}
+	archived, _ := m["repo_archived"].(bool)
+
+	if len(signals) == 0 && !archived {
+		return Result{}, false
+	}
+
+	var missing []Signal
+	for _, s := range []Signal{SignalCommits, SignalReleases, SignalContributors, SignalIssues} {
+		if _, ok := signals[s]; !ok {
+			missing = append(missing, s)
+		}
+	}
+
+	weights := map[Signal]float64{
+		SignalCommits:      p.Weights.Commits,
+		SignalReleases:     p.Weights.Releases,
+		SignalContributors: p.Weights.Contributors,
+		SignalIssues:       p.Weights.Issues,
+	}
+	var sum, total float64
+	for s, v := range signals {
+		sum += weights[s] * v
+		total += weights[s]
+	}
+
+	score := 0
+	if total > 0 {
+		score = int(math.Round(100 * sum / total))
+	}
+	if archived {
+		score = min(score, p.ArchivedCap)
+	}
+	return Result{
+		Score:       score,
+		Grade:       GradeFor(score),
+		Signals:     signals,
+		Missing:     missing,
+		Approximate: approximate,
+		Archived:    archived,
+	}, true
+}
+
+// releaseInterval is the median gap between the latest releases, or the
+// time since the last one when that is longer: a project that shipped
+// monthly but stopped a year ago is a year behind its cadence. It needs at
+// least two dated releases.
+func releaseInterval(dates []time.Time, now time.Time) (time.Duration, bool) {
+	if len(dates) < 2 {
+		return 0, false
+	}
+	slices.SortFunc(dates, func(a, b time.Time) int { return a.Compare(b) })
+	dates = dates[max(0, len(dates)-cadenceWindow-1):]
+	gaps := make([]time.Duration, len(dates)-1)
+	for i := range gaps {
+		gaps[i] = dates[i+1].Sub(dates[i])
+	}
+	slices.Sort(gaps)
+	return max(gaps[(len(gaps)-1)/2], now.Sub(dates[len(dates)-1])), true
+}
+
+// releaseDates reads the release history, a version to date map, as built
+// by the parsers or decoded from JSON.
+func releaseDates(v any) []time.Time {
+	var dates []time.Time
+	add := func(day any) {
+		if t := parseDate(day); !t.IsZero() {
+			dates = append(dates, t)
+		}
+	}
+	switch x := v.(type) {
+	case map[string]string:
+		for _, day := range x {
+			add(day)
+		}
+	case map[string]any:
+		for _, day := range x {
+			add(day)
+		}
+	}
+	return dates
+}
+
+func decay(age time.Duration, freshDays, staleDays int) float64 {
+	days := age.Hours() / 24
+	return clamp(1 - (days-float64(freshDays))/float64(staleDays-freshDays))
+}
+
+// topShare is the largest contributor's share of commits. Without counts
+// it assumes an even split among the listed maintainers.
+func topShare(contributions, maintainers any) (float64, bool) {
+	counts := asInts(contributions)
+	total, top := 0, 0
+	for _, c := range counts {
+		total += c
+		top = max(top, c)
+	}
+	if total > 0 {
+		return float64(top) / float64(total), true
+	}
+	if n := len(asInts(maintainers)); n > 0 {
+		return 1 / float64(n), true
+	}
+	return 0, false
+}
+
+func clamp(v float64) float64 { return math.Max(0, math.Min(1, v)) }
+
+func parseDate(v any) time.Time {
+	s, _ := v.(string)
+	t, _ := time.Parse("2006-01-02", s)
+	return t
+}
+
+func asFloat(v any) float64 {
+	switch x := v.(type) {
+	case float64:
+		return x
+	case int:
+		return float64(x)
+	}
+	return 0
+}
+
+func asInt(v any) int {
+	switch x := v.(type) {
+	case int:
+		return x
+	case int64:
+		return int(x)
+	case float64:
+		return int(x)
+	}
+	return 0
+}
+
+// asInts converts a metadata list, as built in memory or decoded from JSON.
+// Non-numeric lists, such as maintainer logins, yield zeros of the same
+// length.
+func asInts(v any) []int {
+	switch x := v.(type) {
+	case []int:
+		return x
+	case []string:
+		return make([]int, len(x))
+	case []any:
+		out := make([]int, len(x))
+		for i, e := range x {
+			out[i] = asInt(e)
+		}
+		return out
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package health
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+)
+
+var now = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func daysAgo(d int) string { return now.AddDate(0, 0, -d).Format("2006-01-02") }
+
+func TestPolicy_Score(t *testing.T) {
+	p := DefaultPolicy()
+
+	tests := []struct {
+		name  string
+		meta  dag.Metadata
+		score int
+		grade Grade
+	}{
+		{
+			"active and well staffed",
+			dag.Metadata{
+				"repo_last_commit":   daysAgo(10),
+				"repo_last_release":  daysAgo(30),
+				"repo_contributions": []int{40, 30, 20},
+				"repo_open_issues":   10,
+				"repo_closed_issues": 90,
+			},
+			100, GradeA,
+		},
+		{
+			"single author, otherwise fresh",
+			dag.Metadata{
+				"repo_last_commit":   daysAgo(10),
+				"repo_last_release":  daysAgo(30),
+				"repo_contributions": []int{50},
+			},
+			65, GradeB,
+		},
+		{
+			"stale commits and releases",
+			dag.Metadata{
+				"repo_last_commit":  daysAgo(1000),
+				"repo_last_release": daysAgo(1500),
+			},
+			0, GradeF,
+		},
+		{
+			"commits halfway to stale",
+			dag.Metadata{"repo_last_commit": daysAgo(410)},
+			50, GradeC,
+		},
+		{
+			"maintainer list without counts, decoded from JSON",
+			dag.Metadata{"repo_maintainers": []any{"a", "b", "c", "d"}},
+			100, GradeA,
+		},
+		{
+			"archived is capped",
+			dag.Metadata{"repo_last_commit": daysAgo(1), "repo_archived": true},
+			0, GradeF,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := p.score(&dag.Node{ID: "pkg", Meta: tt.meta}, now)
+			if !ok {
+				t.Fatal("expected a score")
+			}
+			if got.Score != tt.score || got.Grade != tt.grade {
+				t.Errorf("got %d (%s), want %d (%s); signals %v", got.Score, got.Grade, tt.score, tt.grade, got.Signals)
+			}
+		})
+	}
+
+	if _, ok := p.score(&dag.Node{ID: "pkg", Meta: dag.Metadata{"version": "1.0"}}, now); ok {
+		t.Error("expected no score without repository metadata")
+	}
+}
+
+func TestPolicy_ScoreCadence(t *testing.T) {
+	p := DefaultPolicy()
+	monthly := map[string]any{}
+	for i := range 12 {
+		monthly[fmt.Sprintf("1.%d", i)] = daysAgo(400 + 30*i)
+	}
+
+	// Releases every month until 400 days ago: the gap since the last one
+	// sets the cadence, not the monthly median.
+	got, _ := p.score(&dag.Node{ID: "pkg", Meta: dag.Metadata{"releases": monthly}}, now)
+	if want := decay(400*24*time.Hour, p.ReleaseFreshDays, p.ReleaseStaleDays); got.Signals[SignalReleases] != want {
+		t.Errorf("got release signal %.3f, want %.3f", got.Signals[SignalReleases], want)
+	}
+
+	// Releases a year apart, the last one recent: the median gap counts.
+	yearly := dag.Metadata{"releases": map[string]string{"1.0": daysAgo(740), "2.0": daysAgo(370), "3.0": daysAgo(5)}}
+	got, _ = p.score(&dag.Node{ID: "pkg", Meta: yearly}, now)
+	if want := decay(365*24*time.Hour, p.ReleaseFreshDays, p.ReleaseStaleDays); math.Abs(got.Signals[SignalReleases]-want) > 0.01 {
+		t.Errorf("got release signal %.3f, want about %.3f", got.Signals[SignalReleases], want)
+	}
+	if len(got.Approximate) != 0 {
+		t.Errorf("history-based cadence should not be approximate, got %v", got.Approximate)
+	}
+}
+
+func TestPolicy_ScoreMissingAndApproximate(t *testing.T) {
+	p := DefaultPolicy()
+
+	got, _ := p.score(&dag.Node{ID: "pkg", Meta: dag.Metadata{
+		"repo_last_release":  daysAgo(30),
+		"repo_open_issues":   5,
+		"repo_closed_issues": 5,
+	}}, now)
+	if want := []Signal{SignalCommits, SignalContributors}; !slices.Equal(got.Missing, want) {
+		t.Errorf("got missing %v, want %v", got.Missing, want)
+	}
+	if want := []Signal{SignalReleases, SignalIssues}; !slices.Equal(got.Approximate, want) {
+		t.Errorf("got approximate %v, want %v", got.Approximate, want)
+	}
+
+	got, _ = p.score(&dag.Node{ID: "pkg", Meta: dag.Metadata{
+		"repo_issue_close_days": 3.5,
+		"repo_open_issues":      5,
+		"repo_closed_issues":    5,
+	}}, now)
+	if got.Signals[SignalIssues] != 1 || slices.Contains(got.Approximate, SignalIssues) {
+		t.Errorf("fast close times should score issues fully, got %v (approximate %v)", got.Signals, got.Approximate)
+	}
+}
+
+func TestAssess(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "app", Meta: dag.Metadata{"repo_last_commit": time.Now().Format("2006-01-02")}})
+	g.AddNode(dag.Node{ID: "old", Meta: dag.Metadata{"repo_last_commit": "2015-01-01"}})
+	g.AddNode(dag.Node{ID: "bare"})
+
+	r := Assess(g, DefaultPolicy())
+	if len(r.Packages) != 2 || r.Packages[0].Package != "old" {
+		t.Errorf("expected least healthy first, got %+v", r.Packages)
+	}
+	if len(r.Unscored) != 1 || r.Unscored[0] != "bare" {
+		t.Errorf("unexpected unscored %v", r.Unscored)
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "app", Meta: dag.Metadata{"repo_last_commit": time.Now().Format("2006-01-02")}})
+	g.AddNode(dag.Node{ID: "bare"})
+
+	if n := Annotate(g, DefaultPolicy()); n != 1 {
+		t.Errorf("expected one scored package, got %d", n)
+	}
+	app, _ := g.Node("app")
+	if res, ok := app.Meta[Key].(Result); !ok || res.Grade != GradeA {
+		t.Errorf("expected a grade A result on app, got %v", app.Meta[Key])
+	}
+	bare, _ := g.Node("bare")
+	if _, ok := bare.Meta[Key]; ok {
+		t.Error("unscored package should not be annotated")
+	}
+}
@@ -0,0 +1,208 @@
+package httputil
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+const (
+	ModeEnv     = "STACKTOWER_HTTP_MODE"
+	FixturesEnv = "STACKTOWER_HTTP_FIXTURES"
+
+	defaultFixturesDir = "testdata/http"
+)
+
+type Mode string
+
+const (
+	ModeLive   Mode = ""
+	ModeRecord Mode = "record"
+	ModeReplay Mode = "replay"
+)
+
+var ErrNoFixture = errors.New("no recorded fixture")
+
+// ReplayTransport records responses to fixture files or serves them back
+// without touching the network. Fixtures are keyed by method, URL and body;
+// request headers such as Authorization never end up on disk.
+type ReplayTransport struct {
+	Mode Mode
+	Dir  string
+	Next http.RoundTripper
+}
+
+type fixture struct {
+	Method string            `json:"method"`
+	URL    string            `json:"url"`
+	Status int               `json:"status"`
+	Header map[string]string `json:"header,omitempty"`
+	Body   string            `json:"body"`
+}
+
+var recordedHeaders = []string{"Content-Type", "Retry-After"}
+
+func TransportFromEnv(next http.RoundTripper) (http.RoundTripper, error) {
+	mode := Mode(os.Getenv(ModeEnv))
+	switch mode {
+	case ModeLive:
+		return next, nil
+	case ModeRecord, ModeReplay:
+	default:
+		return nil, fmt.Errorf("%s: unknown mode %q (want record or replay)", ModeEnv, mode)
+	}
+
+	dir := os.Getenv(FixturesEnv)
+	if dir == "" {
+		dir = defaultFixturesDir
+	}
+	return &ReplayTransport{Mode: mode, Dir: dir, Next: next}, nil
+}
+
+// RoundTrip leaves req as it is. Hashing the body reads a copy from
+// req.GetBody; a request without GetBody has its body drained and is sent
+// on as a clone carrying what was read.
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, drained, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	path := t.fixturePath(req, body)
+	if t.Mode == ModeReplay {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return t.replay(req, path)
+	}
+	if drained {
+		req.Body.Close()
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	return t.record(req, path)
+}
+
+// readBody returns the request body, reporting whether it had to drain
+// req.Body to get it.
+func readBody(req *http.Request) (body []byte, drained bool, err error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, false, nil
+	}
+	if req.GetBody == nil {
+		body, err = io.ReadAll(req.Body)
+		return body, true, err
+	}
+	rc, err := req.GetBody()
+	if err != nil {
+		return nil, false, err
+	}
+	defer rc.Close()
+	body, err = io.ReadAll(rc)
+	return body, false, err
+}
+
+func (t *ReplayTransport) replay(req *http.Request, path string) (*http.Response, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoFixture, req.Method, req.URL)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var f fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("fixture %s: %w", path, err)
+	}
+	return f.response(req), nil
+}
+
+func (t *ReplayTransport) record(req *http.Request, path string) (*http.Response, error) {
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	f := fixture{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Status: resp.StatusCode,
+		Body:   string(body),
+	}
+	for _, h := range recordedHeaders {
+		if v := resp.Header.Get(h); v != "" {
+			if f.Header == nil {
+				f.Header = make(map[string]string)
+			}
+			f.Header[h] = v
+		}
+	}
+	if err := t.save(path, f); err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+func (t *ReplayTransport) save(path string, f fixture) error {
+	if err := os.MkdirAll(t.Dir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+var unsafeChars = regexp.MustCompile(`[^a-zA-Z0-9.-]+`)
+
+// fixturePath hashes the request body along with method and URL, since
+// query APIs such as OSV POST different bodies to the same endpoint.
+func (t *ReplayTransport) fixturePath(req *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(req.Method + " " + req.URL.String()))
+	if body != nil {
+		h.Write([]byte("\n"))
+		h.Write(body)
+	}
+	name := unsafeChars.ReplaceAllString(req.URL.Host, "_") + "_" + hex.EncodeToString(h.Sum(nil)[:8]) + ".json"
+	return filepath.Join(t.Dir, name)
+}
+
+func (f fixture) response(req *http.Request) *http.Response {
+	header := make(http.Header, len(f.Header))
+	for k, v := range f.Header {
+		header.Set(k, v)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.Status, http.StatusText(f.Status)),
+		StatusCode:    f.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewBufferString(f.Body)),
+		ContentLength: int64(len(f.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,214 @@
+package httputil
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestReplayTransport_RecordThenReplay(t *testing.T) {
+	dir := t.TempDir()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			t.Error("expected Authorization header on live request")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Secret", "do-not-record")
+		w.Write([]byte(`{"name":"flask"}`))
+	}))
+
+	rec := &http.Client{Transport: &ReplayTransport{Mode: ModeRecord, Dir: dir, Next: server.Client().Transport}}
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/pypi/flask/json", nil)
+	req.Header.Set("Authorization", "Bearer secret-token")
+	resp, err := rec.Do(req)
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != `{"name":"flask"}` {
+		t.Errorf("recorded body = %q", body)
+	}
+	server.Close()
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Fatalf("got %d fixtures, want 1", len(entries))
+	}
+	raw, _ := os.ReadFile(dir + "/" + entries[0].Name())
+	for _, secret := range []string{"secret-token", "do-not-record"} {
+		if strings.Contains(string(raw), secret) {
+			t.Errorf("fixture leaks %q", secret)
+		}
+	}
+
+	play := &http.Client{Transport: &ReplayTransport{Mode: ModeReplay, Dir: dir}}
+	resp, err = play.Get(server.URL + "/pypi/flask/json")
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ = io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+	if string(body) != `{"name":"flask"}` {
+		t.Errorf("replayed body = %q", body)
+	}
+}
+
+func TestReplayTransport_RecordsErrorStatus(t *testing.T) {
+	dir := t.TempDir()
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	rec := &http.Client{Transport: &ReplayTransport{Mode: ModeRecord, Dir: dir, Next: server.Client().Transport}}
+	resp, err := rec.Get(server.URL + "/missing")
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	resp.Body.Close()
+
+	play := &http.Client{Transport: &ReplayTransport{Mode: ModeReplay, Dir: dir}}
+	resp, err = play.Get(server.URL + "/missing")
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestReplayTransport_KeysByBody(t *testing.T) {
+	dir := t.TempDir()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body)
+	}))
+
+	rec := &http.Client{Transport: &ReplayTransport{Mode: ModeRecord, Dir: dir, Next: server.Client().Transport}}
+	for _, q := range []string{`{"q":"a"}`, `{"q":"b"}`} {
+		resp, err := rec.Post(server.URL+"/query", "application/json", strings.NewReader(q))
+		if err != nil {
+			t.Fatalf("record: %v", err)
+		}
+		resp.Body.Close()
+	}
+	server.Close()
+
+	play := &http.Client{Transport: &ReplayTransport{Mode: ModeReplay, Dir: dir}}
+	for _, q := range []string{`{"q":"a"}`, `{"q":"b"}`} {
+		resp, err := play.Post(server.URL+"/query", "application/json", strings.NewReader(q))
+		if err != nil {
+			t.Fatalf("replay: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if string(body) != q {
+			t.Errorf("replayed %q for query %q", body, q)
+		}
+	}
+}
+
+func TestReplayTransport_LeavesRequestAlone(t *testing.T) {
+	dir := t.TempDir()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body)
+	}))
+	defer server.Close()
+	rec := &ReplayTransport{Mode: ModeRecord, Dir: dir, Next: server.Client().Transport}
+
+	// With GetBody the body is hashed from a copy and req.Body is sent as is.
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/query", strings.NewReader(`{"q":"a"}`))
+	body := req.Body
+	resp, err := rec.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	got, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if req.Body != body {
+		t.Error("RoundTrip replaced req.Body")
+	}
+	if string(got) != `{"q":"a"}` {
+		t.Errorf("server saw body %q", got)
+	}
+
+	// Without GetBody the body is drained and sent on a clone.
+	req, _ = http.NewRequest(http.MethodPost, server.URL+"/query", io.NopCloser(strings.NewReader(`{"q":"b"}`)))
+	body = req.Body
+	resp, err = rec.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	got, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if req.Body != body {
+		t.Error("RoundTrip replaced req.Body")
+	}
+	if string(got) != `{"q":"b"}` {
+		t.Errorf("server saw body %q", got)
+	}
+}
+
+func TestReplayTransport_Miss(t *testing.T) {
+	play := &http.Client{Transport: &ReplayTransport{Mode: ModeReplay, Dir: t.TempDir()}}
+	_, err := play.Get("http://registry.invalid/pkg")
+	if !errors.Is(err, ErrNoFixture) {
+		t.Errorf("got error %v, want ErrNoFixture", err)
+	}
+}
+
+func TestTransportFromEnv(t *testing.T) {
+	next := http.DefaultTransport
+
+	tests := []struct {
+		mode     string
+		dir      string
+		wantMode Mode
+		wantDir  string
+		wantErr  bool
+	}{
+		{mode: "", wantMode: ModeLive},
+		{mode: "record", dir: "fixtures", wantMode: ModeRecord, wantDir: "fixtures"},
+		{mode: "replay", wantMode: ModeReplay, wantDir: defaultFixturesDir},
+		{mode: "bogus", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			t.Setenv(ModeEnv, tt.mode)
+			t.Setenv(FixturesEnv, tt.dir)
+
+			rt, err := TransportFromEnv(next)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.wantMode == ModeLive {
+				if rt != next {
+					t.Error("live mode should return the next transport unchanged")
+				}
+				return
+			}
+			rp, ok := rt.(*ReplayTransport)
+			if !ok {
+				t.Fatalf("got %T, want *ReplayTransport", rt)
+			}
+			if rp.Mode != tt.wantMode || rp.Dir != tt.wantDir {
+				t.Errorf("got mode=%q dir=%q, want mode=%q dir=%q", rp.Mode, rp.Dir, tt.wantMode, tt.wantDir)
+			}
+		})
+	}
+}
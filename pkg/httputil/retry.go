@@ -3,39 +3,111 @@ package httputil
 import (
 	"context"
 	"errors"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
 	"time"
 )
 
 type RetryableError struct {
-	Err error
+	Err   error
+	After time.Duration // server-requested delay (Retry-After), if any
 }
 
 func (e *RetryableError) Error() string { return e.Err.Error() }
 func (e *RetryableError) Unwrap() error { return e.Err }
 
-func Retry(ctx context.Context, max int, delay time.Duration, fn func() error) error {
-	if max < 1 {
-		max = 1
+type RetryPolicy struct {
+	Attempts  int
+	BaseDelay time.Duration
+	MaxDelay  time.Duration // caps backoff; a longer Retry-After aborts instead of waiting
+	Jitter    float64       // fraction of each delay that is randomized, 0 to 1
+	Statuses  []int         // retryable HTTP statuses; nil means 429 and any 5xx
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	Attempts:  3,
+	BaseDelay: time.Second,
+	MaxDelay:  30 * time.Second,
+	Jitter:    0.2,
+}
+
+func (p RetryPolicy) RetryableStatus(code int) bool {
+	if p.Statuses == nil {
+		return code == http.StatusTooManyRequests || code >= 500
 	}
+	return slices.Contains(p.Statuses, code)
+}
+
+func (p RetryPolicy) Do(ctx context.Context, fn func() error) error {
+	attempts := max(p.Attempts, 1)
 
 	var lastErr error
-	for attempt := 0; attempt < max; attempt++ {
-		if err := fn(); err == nil {
+	for attempt := 0; attempt < attempts; attempt++ {
+		err := fn()
+		if err == nil {
 			return nil
-		} else if lastErr = err; !errors.As(err, new(*RetryableError)) {
+		}
+		lastErr = err
+
+		var re *RetryableError
+		if !errors.As(err, &re) {
 			return err
 		}
+		if attempt == attempts-1 {
+			break
+		}
+
+		delay := p.backoff(attempt)
+		if re.After > delay {
+			if p.MaxDelay > 0 && re.After > p.MaxDelay {
+				return err
+			}
+			delay = re.After
+		}
 
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		case <-time.After(delay):
-			delay *= 2
 		}
 	}
 	return lastErr
 }
 
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << attempt
+	if attempt >= 63 || delay>>attempt != p.BaseDelay {
+		delay = math.MaxInt64
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if p.Jitter > 0 {
+		delay -= time.Duration(rand.Float64() * min(p.Jitter, 1) * float64(delay))
+	}
+	return delay
+}
+
+func ParseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return max(time.Duration(secs)*time.Second, 0)
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(t.Sub(now), 0)
+	}
+	return 0
+}
+
+func Retry(ctx context.Context, max int, delay time.Duration, fn func() error) error {
+	return RetryPolicy{Attempts: max, BaseDelay: delay}.Do(ctx, fn)
+}
+
 func RetryWithBackoff(ctx context.Context, fn func() error) error {
-	return Retry(ctx, 3, time.Second, fn)
+	return DefaultRetryPolicy.Do(ctx, fn)
 }
@@ -167,3 +167,118 @@ func TestRetryableError(t *testing.T) {
 		t.Errorf("got Unwrap() = %v, want %v", got, inner)
 	}
 }
+
+func TestRetryPolicy_RetryAfter(t *testing.T) {
+	p := RetryPolicy{Attempts: 2, BaseDelay: time.Millisecond}
+	attempts := 0
+	start := time.Now()
+
+	err := p.Do(context.Background(), func() error {
+		attempts++
+		if attempts == 1 {
+			return &RetryableError{Err: errors.New("slow down"), After: 50 * time.Millisecond}
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("elapsed %v, want >= 50ms (Retry-After)", elapsed)
+	}
+}
+
+func TestRetryPolicy_RetryAfterExceedsMaxDelay(t *testing.T) {
+	p := RetryPolicy{Attempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+	attempts := 0
+
+	err := p.Do(context.Background(), func() error {
+		attempts++
+		return &RetryableError{Err: errors.New("slow down"), After: time.Hour}
+	})
+
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if attempts != 1 {
+		t.Errorf("got %d attempts, want 1", attempts)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		attempt int
+		min     time.Duration
+		max     time.Duration
+	}{
+		{"first", RetryPolicy{BaseDelay: time.Second}, 0, time.Second, time.Second},
+		{"doubling", RetryPolicy{BaseDelay: time.Second}, 3, 8 * time.Second, 8 * time.Second},
+		{"capped", RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}, 4, 5 * time.Second, 5 * time.Second},
+		{"overflowCapped", RetryPolicy{BaseDelay: time.Second, MaxDelay: time.Minute}, 70, time.Minute, time.Minute},
+		{"jitter", RetryPolicy{BaseDelay: time.Second, Jitter: 0.5}, 1, time.Second, 2 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 20 {
+				got := tt.policy.backoff(tt.attempt)
+				if got < tt.min || got > tt.max {
+					t.Fatalf("got %v, want in [%v, %v]", got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_RetryableStatus(t *testing.T) {
+	def := RetryPolicy{}
+	custom := RetryPolicy{Statuses: []int{503}}
+
+	tests := []struct {
+		policy RetryPolicy
+		code   int
+		want   bool
+	}{
+		{def, 429, true},
+		{def, 500, true},
+		{def, 503, true},
+		{def, 404, false},
+		{def, 403, false},
+		{custom, 503, true},
+		{custom, 500, false},
+		{custom, 429, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.policy.RetryableStatus(tt.code); got != tt.want {
+			t.Errorf("RetryableStatus(%d) with %v = %v, want %v", tt.code, tt.policy.Statuses, got, tt.want)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		input string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"120", 2 * time.Minute},
+		{"-5", 0},
+		{"Wed, 01 Jan 2025 12:00:30 GMT", 30 * time.Second},
+		{"Wed, 01 Jan 2025 11:00:00 GMT", 0},
+		{"garbage", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := ParseRetryAfter(tt.input, now); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package httputil
+
+import (
+	"context"
+	"sync"
+)
+
+// Group collapses concurrent calls with the same key onto one execution.
+// The zero value is ready to use.
+type Group struct {
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+type call struct {
+	done chan struct{}
+	data []byte
+	err  error
+}
+
+// Do runs fn once per key among concurrent callers. Callers that join an
+// in-flight call receive its result with shared set to true. fn runs under
+// a context detached from the first caller's cancellation, so one caller
+// giving up does not fail the others; each caller only waits as long as
+// its own ctx allows.
+func (g *Group) Do(ctx context.Context, key string, fn func(context.Context) ([]byte, error)) (data []byte, shared bool, err error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*call)
+	}
+	c, shared := g.calls[key]
+	if !shared {
+		c = &call{done: make(chan struct{})}
+		g.calls[key] = c
+		go g.run(context.WithoutCancel(ctx), key, c, fn)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.data, shared, c.err
+	case <-ctx.Done():
+		return nil, shared, ctx.Err()
+	}
+}
+
+func (g *Group) run(ctx context.Context, key string, c *call, fn func(context.Context) ([]byte, error)) {
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(c.done)
+	}()
+	c.data, c.err = fn(ctx)
+}
@@ -0,0 +1,150 @@
+package httputil
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroup_Do(t *testing.T) {
+	var g Group
+	data, shared, err := g.Do(context.Background(), "key", func(context.Context) ([]byte, error) {
+		return []byte("value"), nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if shared {
+		t.Error("single caller should not be shared")
+	}
+	if string(data) != "value" {
+		t.Errorf("got %q, want %q", data, "value")
+	}
+}
+
+func TestGroup_DoDeduplicates(t *testing.T) {
+	var g Group
+	var calls atomic.Int32
+	release := make(chan struct{})
+
+	const n = 10
+	var wg sync.WaitGroup
+	results := make([]string, n)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data, _, err := g.Do(context.Background(), "key", func(context.Context) ([]byte, error) {
+				calls.Add(1)
+				<-release
+				return []byte("value"), nil
+			})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			results[i] = string(data)
+		}()
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("got %d calls, want 1", got)
+	}
+	for i, r := range results {
+		if r != "value" {
+			t.Errorf("result %d = %q, want %q", i, r, "value")
+		}
+	}
+}
+
+func TestGroup_DoPropagatesError(t *testing.T) {
+	var g Group
+	want := errors.New("boom")
+	_, _, err := g.Do(context.Background(), "key", func(context.Context) ([]byte, error) {
+		return nil, want
+	})
+	if !errors.Is(err, want) {
+		t.Errorf("got error %v, want %v", err, want)
+	}
+
+	// A failed call must not poison later calls for the same key.
+	data, _, err := g.Do(context.Background(), "key", func(context.Context) ([]byte, error) {
+		return []byte("ok"), nil
+	})
+	if err != nil || string(data) != "ok" {
+		t.Errorf("got %q, %v; want ok, nil", data, err)
+	}
+}
+
+func TestGroup_DoWaiterContextCancelled(t *testing.T) {
+	var g Group
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+
+	go g.Do(context.Background(), "key", func(context.Context) ([]byte, error) {
+		close(started)
+		<-release
+		return nil, nil
+	})
+	<-started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, shared, err := g.Do(ctx, "key", func(context.Context) ([]byte, error) {
+		t.Error("waiter should not run fn")
+		return nil, nil
+	})
+	if !shared {
+		t.Error("waiter should be shared")
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+}
+
+func TestGroup_DoLeaderCancelledWaiterSucceeds(t *testing.T) {
+	var g Group
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, _, err := g.Do(leaderCtx, "key", func(ctx context.Context) ([]byte, error) {
+			close(started)
+			<-release
+			return []byte("value"), ctx.Err()
+		})
+		leaderErr <- err
+	}()
+	<-started
+
+	waiter := make(chan string, 1)
+	go func() {
+		data, _, err := g.Do(context.Background(), "key", func(context.Context) ([]byte, error) {
+			t.Error("waiter should not run fn")
+			return nil, nil
+		})
+		if err != nil {
+			t.Errorf("waiter got error %v", err)
+		}
+		waiter <- string(data)
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("leader got error %v, want context.Canceled", err)
+	}
+	close(release)
+	if got := <-waiter; got != "value" {
+		t.Errorf("waiter got %q, want value", got)
+	}
+}
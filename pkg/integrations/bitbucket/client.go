@@ -0,0 +1,152 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/matzehuels/stacktower/pkg/integrations"
+)
+
+var repoURLPattern = regexp.MustCompile(`https?://(?:[^@/]+@)?bitbucket\.org/([^/]+)/([^/]+)`)
+
+const (
+	commitSample    = 50
+	maxContributors = 5
+)
+
+type Client struct {
+	integrations.BaseClient
+	baseURL string
+	headers map[string]string
+}
+
+// NewClient accepts either a repository/workspace access token or an
+// app password in "username:app-password" form. An empty token uses the
+// anonymous API.
+func NewClient(token string, cacheTTL time.Duration) (*Client, error) {
+	base, err := integrations.NewBaseClient(cacheTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := map[string]string{"Accept": "application/json"}
+	if user, pass, ok := strings.Cut(token, ":"); ok {
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+pass))
+	} else if token != "" {
+		headers["Authorization"] = "Bearer " + token
+	}
+
+	base.Scope = integrations.ScopeOf("https://api.bitbucket.org/2.0", token)
+	return &Client{
+		BaseClient: base,
+		baseURL:    "https://api.bitbucket.org/2.0",
+		headers:    headers,
+	}, nil
+}
+
+func (c *Client) Fetch(ctx context.Context, workspace, repo string, refresh bool) (*integrations.RepoMetrics, error) {
+	cacheKey := "bitbucket:" + workspace + "/" + repo
+
+	var m integrations.RepoMetrics
+	err := c.FetchWithCache(ctx, cacheKey, refresh, func(ctx context.Context, v any) error {
+		return c.fetchMetrics(ctx, workspace, repo, v.(*integrations.RepoMetrics))
+	}, &m)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (c *Client) fetchMetrics(ctx context.Context, workspace, repo string, m *integrations.RepoMetrics) error {
+	base := fmt.Sprintf("%s/repositories/%s/%s", c.baseURL, workspace, repo)
+
+	var data repoResponse
+	if err := c.DoRequest(ctx, base, c.headers, &data); err != nil {
+		if errors.Is(err, integrations.ErrNotFound) {
+			return fmt.Errorf("%w: bitbucket repo %s/%s", err, workspace, repo)
+		}
+		return err
+	}
+
+	*m = integrations.RepoMetrics{
+		RepoURL:  data.Links.HTML.Href,
+		Owner:    workspace,
+		SizeKB:   data.Size / 1024,
+		Language: data.Language,
+	}
+	if m.RepoURL == "" {
+		m.RepoURL = fmt.Sprintf("https://bitbucket.org/%s/%s", workspace, repo)
+	}
+	if data.UpdatedOn != nil {
+		m.LastCommitAt = data.UpdatedOn
+	}
+
+	// Bitbucket has no stars; watchers are the closest signal.
+	var watchers pageResponse
+	if err := c.DoRequest(ctx, base+"/watchers?pagelen=1", c.headers, &watchers); err == nil {
+		m.Stars = watchers.Size
+	}
+
+	var commits commitsResponse
+	if err := c.DoRequest(ctx, fmt.Sprintf("%s/commits?pagelen=%d", base, commitSample), c.headers, &commits); err == nil {
+		if len(commits.Values) > 0 {
+			m.LastCommitAt = &commits.Values[0].Date
+		}
+		logins := make([]string, len(commits.Values))
+		for i, c := range commits.Values {
+			logins[i] = c.Author.User.Nickname
+		}
+		m.Contributors = integrations.RankContributors(logins, maxContributors)
+	}
+
+	var tags tagsResponse
+	if err := c.DoRequest(ctx, base+"/refs/tags?sort=-target.date&pagelen=1", c.headers, &tags); err == nil && len(tags.Values) > 0 {
+		m.LastReleaseAt = &tags.Values[0].Target.Date
+	}
+	return nil
+}
+
+func ExtractURL(projectURLs map[string]string, homepage string) (workspace, repo string, ok bool) {
+	return integrations.ExtractRepoURL(repoURLPattern, projectURLs, homepage)
+}
+
+type repoResponse struct {
+	Size      int        `json:"size"`
+	Language  string     `json:"language"`
+	UpdatedOn *time.Time `json:"updated_on"`
+	Links     struct {
+		HTML struct {
+			Href string `json:"href"`
+		} `json:"html"`
+	} `json:"links"`
+}
+
+type pageResponse struct {
+	Size int `json:"size"`
+}
+
+type commit struct {
+	Date   time.Time `json:"date"`
+	Author struct {
+		User struct {
+			Nickname string `json:"nickname"`
+		} `json:"user"`
+	} `json:"author"`
+}
+
+type commitsResponse struct {
+	Values []commit `json:"values"`
+}
+
+type tagsResponse struct {
+	Values []struct {
+		Target struct {
+			Date time.Time `json:"date"`
+		} `json:"target"`
+	} `json:"values"`
+}
@@ -0,0 +1,117 @@
+package bitbucket
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_Fetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/repositories/ws/repo":
+			w.Write([]byte(`{"size": 2048, "language": "python", "links": {"html": {"href": "https://bitbucket.org/ws/repo"}}}`))
+		case "/repositories/ws/repo/watchers":
+			w.Write([]byte(`{"size": 42, "values": []}`))
+		case "/repositories/ws/repo/commits":
+			w.Write([]byte(`{"values": [
+				{"date": "2024-05-01T10:00:00+00:00", "author": {"user": {"nickname": "bob"}}},
+				{"date": "2024-04-01T10:00:00+00:00", "author": {"user": {"nickname": "alice"}}},
+				{"date": "2024-03-01T10:00:00+00:00", "author": {"user": {"nickname": "alice"}}},
+				{"date": "2024-02-01T10:00:00+00:00", "author": {"raw": "Unlinked <x@example.com>"}}
+			]}`))
+		case "/repositories/ws/repo/refs/tags":
+			w.Write([]byte(`{"values": [{"name": "v1.0", "target": {"date": "2024-01-15T00:00:00+00:00"}}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, _ := NewClient("", time.Hour)
+	c.HTTP = server.Client()
+	c.baseURL = server.URL
+
+	m, err := c.Fetch(context.Background(), "ws", "repo", true)
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+
+	if m.Stars != 42 {
+		t.Errorf("expected 42 stars from watchers, got %d", m.Stars)
+	}
+	if m.SizeKB != 2 {
+		t.Errorf("expected 2 KB, got %d", m.SizeKB)
+	}
+	if m.LastCommitAt == nil || m.LastCommitAt.Format("2006-01-02") != "2024-05-01" {
+		t.Errorf("unexpected last commit %v", m.LastCommitAt)
+	}
+	if m.LastReleaseAt == nil || m.LastReleaseAt.Format("2006-01-02") != "2024-01-15" {
+		t.Errorf("unexpected last release %v", m.LastReleaseAt)
+	}
+	if len(m.Contributors) != 2 || m.Contributors[0].Login != "alice" || m.Contributors[0].Contributions != 2 {
+		t.Errorf("unexpected contributors %+v", m.Contributors)
+	}
+}
+
+func TestNewClient_Auth(t *testing.T) {
+	tests := []struct {
+		token, prefix string
+	}{
+		{"user:app-pass", "Basic "},
+		{"access-token", "Bearer "},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		c, err := NewClient(tt.token, time.Hour)
+		if err != nil {
+			t.Fatalf("NewClient failed: %v", err)
+		}
+		got := c.headers["Authorization"]
+		if tt.prefix == "" && got != "" || !strings.HasPrefix(got, tt.prefix) {
+			t.Errorf("token %q: got Authorization %q, want prefix %q", tt.token, got, tt.prefix)
+		}
+	}
+}
+
+func TestExtractURL(t *testing.T) {
+	tests := []struct {
+		urls          map[string]string
+		home          string
+		wantWorkspace string
+		wantRepo      string
+		wantOK        bool
+	}{
+		{
+			urls:          map[string]string{"Source": "https://bitbucket.org/foo/bar"},
+			wantWorkspace: "foo",
+			wantRepo:      "bar",
+			wantOK:        true,
+		},
+		{
+			home:          "https://someone@bitbucket.org/baz/qux.git",
+			wantWorkspace: "baz",
+			wantRepo:      "qux",
+			wantOK:        true,
+		},
+		{
+			urls:   map[string]string{"Source": "https://github.com/foo/bar"},
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		ws, repo, ok := ExtractURL(tt.urls, tt.home)
+		if ok != tt.wantOK {
+			t.Errorf("got ok=%v, want %v", ok, tt.wantOK)
+		}
+		if ok && (ws != tt.wantWorkspace || repo != tt.wantRepo) {
+			t.Errorf("got %s/%s, want %s/%s", ws, repo, tt.wantWorkspace, tt.wantRepo)
+		}
+	}
+}
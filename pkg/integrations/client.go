@@ -1,36 +1,119 @@
 package integrations
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"net/http"
+	"reflect"
+	"strings"
+	"time"
 
 	"github.com/matzehuels/stacktower/pkg/httputil"
 )
 
+// inflight is shared by all clients so that parsers created per request
+// still collapse identical fetches onto a single HTTP round trip. Fetches
+// are only shared between clients with the same Scope and cache.
+var inflight httputil.Group
+
 type BaseClient struct {
 	HTTP  *http.Client
 	Cache *httputil.Cache
+	Retry *httputil.RetryPolicy // nil uses httputil.DefaultRetryPolicy
+
+	// Scope tells apart clients whose responses differ for the same cache
+	// key, such as ones with different tokens or base URLs.
+	Scope string
+}
+
+// ScopeOf derives a BaseClient.Scope from whatever sets a client's
+// responses apart, without keeping credentials around in plain text.
+func ScopeOf(parts ...string) string {
+	h := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(h[:8])
+}
+
+func (c *BaseClient) retryPolicy() httputil.RetryPolicy {
+	if c.Retry != nil {
+		return *c.Retry
+	}
+	return httputil.DefaultRetryPolicy
 }
 
-func (c *BaseClient) FetchWithCache(ctx context.Context, key string, refresh bool, fetch func() error, v any) error {
+// WithRetry runs fn under the client's retry policy, for requests whose
+// results are cached piecemeal rather than through FetchWithCache.
+func (c *BaseClient) WithRetry(ctx context.Context, fn func() error) error {
+	return c.retryPolicy().Do(ctx, fn)
+}
+
+// FetchWithCache serves v, a pointer, from the cache or fills it with
+// fetch, sharing the fetch with concurrent callers for the same key. fetch
+// fills a fresh value of v's type under a context that outlives any single
+// caller's cancellation, so a caller that gives up is never written to
+// later; each caller gets a copy only once the fetch succeeds.
+func (c *BaseClient) FetchWithCache(ctx context.Context, key string, refresh bool, fetch func(ctx context.Context, v any) error, v any) error {
 	if !refresh {
 		if ok, _ := c.Cache.Get(key, v); ok {
 			return nil
 		}
 	}
 
-	if err := httputil.RetryWithBackoff(ctx, fetch); err != nil {
+	data, _, err := inflight.Do(ctx, c.flightKey(key), func(ctx context.Context) ([]byte, error) {
+		var fresh any
+		err := c.retryPolicy().Do(ctx, func() error {
+			fresh = reflect.New(reflect.TypeOf(v).Elem()).Interface()
+			return fetch(ctx, fresh)
+		})
+		if err != nil {
+			return nil, err
+		}
+		_ = c.Cache.Set(key, fresh)
+		return json.Marshal(fresh)
+	})
+	if err != nil {
 		return err
 	}
+	return json.Unmarshal(data, v)
+}
 
-	_ = c.Cache.Set(key, v)
-	return nil
+func (c *BaseClient) flightKey(key string) string {
+	dir := ""
+	if c.Cache != nil {
+		dir = c.Cache.Dir
+	}
+	return c.Scope + "\x00" + dir + "\x00" + key
 }
 
 func (c *BaseClient) DoRequest(ctx context.Context, url string, headers map[string]string, v any) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	return c.do(ctx, http.MethodGet, url, headers, nil, v)
+}
+
+func (c *BaseClient) PostJSON(ctx context.Context, url string, headers map[string]string, body, v any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encode: %w", err)
+	}
+	h := maps.Clone(headers)
+	if h == nil {
+		h = make(map[string]string, 1)
+	}
+	h["Content-Type"] = "application/json"
+	return c.do(ctx, http.MethodPost, url, h, data, v)
+}
+
+func (c *BaseClient) do(ctx context.Context, method, url string, headers map[string]string, body []byte, v any) error {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, r)
 	if err != nil {
 		return err
 	}
@@ -40,18 +123,25 @@ func (c *BaseClient) DoRequest(ctx context.Context, url string, headers map[stri
 	}
 
 	resp, err := c.HTTP.Do(req)
+	if errors.Is(err, httputil.ErrNoFixture) {
+		return err
+	}
 	if err != nil {
 		return &httputil.RetryableError{Err: fmt.Errorf("%w: %v", ErrNetwork, err)}
 	}
 	defer resp.Body.Close()
 
 	switch {
+	case resp.StatusCode == http.StatusOK:
 	case resp.StatusCode == http.StatusNotFound:
 		return ErrNotFound
-	case resp.StatusCode >= 500:
-		return &httputil.RetryableError{Err: fmt.Errorf("%w: %d", ErrNetwork, resp.StatusCode)}
-	case resp.StatusCode != http.StatusOK:
-		return fmt.Errorf("%w: %d", ErrNetwork, resp.StatusCode)
+	case c.retryPolicy().RetryableStatus(resp.StatusCode):
+		return &httputil.RetryableError{
+			Err:   statusError(resp.StatusCode),
+			After: httputil.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	default:
+		return statusError(resp.StatusCode)
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
@@ -59,3 +149,10 @@ func (c *BaseClient) DoRequest(ctx context.Context, url string, headers map[stri
 	}
 	return nil
 }
+
+func statusError(code int) error {
+	if code == http.StatusTooManyRequests {
+		return fmt.Errorf("%w: %d", ErrRateLimited, code)
+	}
+	return fmt.Errorf("%w: %d", ErrNetwork, code)
+}
@@ -0,0 +1,241 @@
+package integrations
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/matzehuels/stacktower/pkg/httputil"
+)
+
+func TestBaseClient_FetchWithCache_Deduplicates(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte(`{"name":"jinja2","version":"3.1.0"}`))
+	}))
+	defer server.Close()
+
+	type pkg struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+
+	const n = 8
+	var wg sync.WaitGroup
+	results := make([]pkg, n)
+	cacheDir := t.TempDir()
+	for i := range n {
+		// Separate clients mirror the server creating a parser per request.
+		c := &BaseClient{
+			HTTP:  server.Client(),
+			Cache: &httputil.Cache{Dir: cacheDir, TTL: time.Hour},
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := context.Background()
+			err := c.FetchWithCache(ctx, "test:dedupe:jinja2", false, func(ctx context.Context, v any) error {
+				return c.DoRequest(ctx, server.URL, nil, v)
+			}, &results[i])
+			if err != nil {
+				t.Errorf("FetchWithCache: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := hits.Load(); got != 1 {
+		t.Errorf("got %d requests, want 1", got)
+	}
+	for i, r := range results {
+		if r.Name != "jinja2" || r.Version != "3.1.0" {
+			t.Errorf("result %d = %+v, want jinja2 3.1.0", i, r)
+		}
+	}
+}
+
+func TestBaseClient_FetchWithCache_ScopesFlights(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte(`{"token":"` + r.Header.Get("Authorization") + `"}`))
+	}))
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	tokens := []string{"alice", "bob"}
+	results := make([]struct{ Token string }, len(tokens))
+	var wg sync.WaitGroup
+	for i, token := range tokens {
+		c := &BaseClient{
+			HTTP:  server.Client(),
+			Cache: &httputil.Cache{Dir: cacheDir, TTL: time.Hour},
+			Scope: ScopeOf(server.URL, token),
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := context.Background()
+			err := c.FetchWithCache(ctx, "test:scoped", true, func(ctx context.Context, v any) error {
+				return c.DoRequest(ctx, server.URL, map[string]string{"Authorization": token}, v)
+			}, &results[i])
+			if err != nil {
+				t.Errorf("FetchWithCache: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := hits.Load(); got != 2 {
+		t.Errorf("got %d requests, want one per scope", got)
+	}
+	for i, r := range results {
+		if r.Token != tokens[i] {
+			t.Errorf("client %d got %q's response", i, r.Token)
+		}
+	}
+}
+
+func TestBaseClient_FetchWithCache_LeaderGivesUp(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	var once sync.Once
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		once.Do(func() { close(started) })
+		<-release
+		w.Write([]byte(`{"name":"jinja2"}`))
+	}))
+	defer server.Close()
+
+	c := &BaseClient{
+		HTTP:  server.Client(),
+		Cache: &httputil.Cache{Dir: t.TempDir(), TTL: time.Hour},
+	}
+	fetch := func(ctx context.Context, v any) error {
+		return c.DoRequest(ctx, server.URL, nil, v)
+	}
+
+	type pkg struct{ Name string }
+	var leader, waiter pkg
+	ctx, cancel := context.WithCancel(context.Background())
+	leaderDone := make(chan error)
+	go func() { leaderDone <- c.FetchWithCache(ctx, "test:gives-up", true, fetch, &leader) }()
+	<-started
+
+	waiterDone := make(chan error)
+	go func() { waiterDone <- c.FetchWithCache(context.Background(), "test:gives-up", true, fetch, &waiter) }()
+	cancel()
+	if err := <-leaderDone; !errors.Is(err, context.Canceled) {
+		t.Fatalf("leader got %v, want context.Canceled", err)
+	}
+
+	// The waiter returns once the flight is over, whether it joined the
+	// leader's or, having come too late, ran its own.
+	close(release)
+	if err := <-waiterDone; err != nil {
+		t.Fatalf("waiter: %v", err)
+	}
+	if waiter.Name != "jinja2" {
+		t.Errorf("waiter = %+v, want jinja2", waiter)
+	}
+	if leader != (pkg{}) {
+		t.Errorf("leader's value was written after it gave up: %+v", leader)
+	}
+}
+
+func TestBaseClient_DoRequest_RateLimited(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	c := &BaseClient{
+		HTTP:  server.Client(),
+		Cache: &httputil.Cache{Dir: t.TempDir(), TTL: time.Hour},
+		Retry: &httputil.RetryPolicy{Attempts: 3, BaseDelay: time.Millisecond},
+	}
+
+	ctx := context.Background()
+	var out struct{ OK bool }
+	err := c.FetchWithCache(ctx, "test:ratelimit", true, func(ctx context.Context, v any) error {
+		return c.DoRequest(ctx, server.URL, nil, v)
+	}, &out)
+	if err != nil {
+		t.Fatalf("FetchWithCache: %v", err)
+	}
+	if !out.OK {
+		t.Error("expected decoded response after retry")
+	}
+	if got := hits.Load(); got != 2 {
+		t.Errorf("got %d requests, want 2", got)
+	}
+}
+
+func TestBaseClient_DoRequest_ReplayMissNotRetried(t *testing.T) {
+	c := &BaseClient{
+		HTTP:  &http.Client{Transport: &httputil.ReplayTransport{Mode: httputil.ModeReplay, Dir: t.TempDir()}},
+		Cache: &httputil.Cache{Dir: t.TempDir(), TTL: time.Hour},
+		Retry: &httputil.RetryPolicy{Attempts: 3, BaseDelay: time.Millisecond},
+	}
+
+	ctx := context.Background()
+	attempts := 0
+	var out struct{ OK bool }
+	err := c.FetchWithCache(ctx, "test:replay-miss", true, func(ctx context.Context, v any) error {
+		attempts++
+		return c.DoRequest(ctx, "http://registry.invalid/pkg", nil, v)
+	}, &out)
+	if !errors.Is(err, httputil.ErrNoFixture) {
+		t.Errorf("got error %v, want ErrNoFixture", err)
+	}
+	if attempts != 1 {
+		t.Errorf("got %d attempts, want 1", attempts)
+	}
+}
+
+func TestBaseClient_DoRequest_StatusErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		statuses  []int
+		want      error
+		retryable bool
+	}{
+		{"notFound", http.StatusNotFound, nil, ErrNotFound, false},
+		{"rateLimited", http.StatusTooManyRequests, nil, ErrRateLimited, true},
+		{"serverError", http.StatusBadGateway, nil, ErrNetwork, true},
+		{"forbidden", http.StatusForbidden, nil, ErrNetwork, false},
+		{"customNotRetryable", http.StatusBadGateway, []int{503}, ErrNetwork, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			c := &BaseClient{HTTP: server.Client(), Retry: &httputil.RetryPolicy{Statuses: tt.statuses}}
+			err := c.DoRequest(context.Background(), server.URL, nil, &struct{}{})
+			if !errors.Is(err, tt.want) {
+				t.Errorf("got error %v, want %v", err, tt.want)
+			}
+			if got := errors.As(err, new(*httputil.RetryableError)); got != tt.retryable {
+				t.Errorf("retryable = %v, want %v", got, tt.retryable)
+			}
+		})
+	}
+}
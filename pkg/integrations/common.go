@@ -1,21 +1,21 @@
 package integrations
 
 import (
+	"cmp"
 	"errors"
-	"net/http"
 	"net/url"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/matzehuels/stacktower/pkg/httputil"
 )
 
-const httpTimeout = 10 * time.Second
-
 var (
-	ErrNotFound = errors.New("resource not found")
-	ErrNetwork  = errors.New("network error")
+	ErrNotFound    = errors.New("resource not found")
+	ErrNetwork     = errors.New("network error")
+	ErrRateLimited = errors.New("rate limited")
 )
 
 type RepoMetrics struct {
@@ -30,6 +30,13 @@ type RepoMetrics struct {
 	Language      string        `json:"language,omitempty"`
 	Topics        []string      `json:"topics,omitempty"`
 	Archived      bool          `json:"archived"`
+	OpenIssues    int           `json:"open_issues,omitempty"`
+	ClosedIssues  int           `json:"closed_issues,omitempty"`
+	Funding       []string      `json:"funding,omitempty"`
+
+	// IssueCloseDays is the median time from opening to closing of recently
+	// closed issues. Like the issue totals, only GitHub's GraphQL API fills it.
+	IssueCloseDays float64 `json:"issue_close_days,omitempty"`
 }
 
 type Contributor struct {
@@ -61,17 +68,35 @@ func ExtractRepoURL(re *regexp.Regexp, projectURLs map[string]string, homepage s
 }
 
 func matchRepoURL(re *regexp.Regexp, url string) (owner, repo string, ok bool) {
-	if strings.Contains(url, "/sponsors/") {
+	if IsFundingURL(url) {
 		return "", "", false
 	}
 	if match := re.FindStringSubmatch(url); len(match) >= 3 {
-		return match[1], match[2], true
+		repo, _, _ = strings.Cut(match[2], "#")
+		repo, _, _ = strings.Cut(repo, "?")
+		return match[1], strings.TrimSuffix(repo, ".git"), true
 	}
 	return "", "", false
 }
 
-func NewHTTPClient() *http.Client {
-	return &http.Client{Timeout: httpTimeout}
+// RankContributors counts commits per author login, most active first, for
+// hosts that expose commit history but no contributor statistics.
+func RankContributors(logins []string, limit int) []Contributor {
+	counts := make(map[string]int)
+	for _, login := range logins {
+		if login != "" {
+			counts[login]++
+		}
+	}
+
+	contributors := make([]Contributor, 0, len(counts))
+	for login, n := range counts {
+		contributors = append(contributors, Contributor{Login: login, Contributions: n})
+	}
+	slices.SortFunc(contributors, func(a, b Contributor) int {
+		return cmp.Or(cmp.Compare(b.Contributions, a.Contributions), cmp.Compare(a.Login, b.Login))
+	})
+	return contributors[:min(len(contributors), limit)]
 }
 
 func NewCache(ttl time.Duration) (*httputil.Cache, error) {
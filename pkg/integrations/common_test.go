@@ -42,6 +42,14 @@ func TestExtractRepoURL(t *testing.T) {
 			wantRepo:    "widget",
 			wantOK:      true,
 		},
+		{
+			name:        "strips .git suffix and fragment",
+			pattern:     githubPattern,
+			projectURLs: map[string]string{"Source": "https://github.com/foo/bar.git#readme"},
+			wantOwner:   "foo",
+			wantRepo:    "bar",
+			wantOK:      true,
+		},
 		{
 			name:        "no match",
 			pattern:     githubPattern,
@@ -18,6 +18,9 @@ type CrateInfo struct {
 	Description  string
 	License      string
 	Downloads    int
+	Yanked       bool
+	YankMessage  string
+	Releases     map[string]string // version to release day, yanked versions excluded
 }
 
 type Client struct {
@@ -27,17 +30,14 @@ type Client struct {
 }
 
 func NewClient(cacheTTL time.Duration) (*Client, error) {
-	cache, err := integrations.NewCache(cacheTTL)
+	base, err := integrations.NewBaseClient(cacheTTL)
 	if err != nil {
 		return nil, err
 	}
 	return &Client{
-		BaseClient: integrations.BaseClient{
-			HTTP:  integrations.NewHTTPClient(),
-			Cache: cache,
-		},
-		baseURL: "https://crates.io/api/v1",
-		headers: map[string]string{"User-Agent": "github.com/matzehuels/stacktower/1.0 (https://github.com/stacktower)"},
+		BaseClient: base,
+		baseURL:    "https://crates.io/api/v1",
+		headers:    map[string]string{"User-Agent": "github.com/matzehuels/stacktower/1.0 (https://github.com/stacktower)"},
 	}, nil
 }
 
@@ -45,8 +45,8 @@ func (c *Client) FetchCrate(ctx context.Context, crate string, refresh bool) (*C
 	cacheKey := "crates:" + crate
 
 	var info CrateInfo
-	err := c.FetchWithCache(ctx, cacheKey, refresh, func() error {
-		return c.fetchCrate(ctx, crate, &info)
+	err := c.FetchWithCache(ctx, cacheKey, refresh, func(ctx context.Context, v any) error {
+		return c.fetchCrate(ctx, crate, v.(*CrateInfo))
 	}, &info)
 	if err != nil {
 		return nil, err
@@ -78,6 +78,15 @@ func (c *Client) fetchCrate(ctx context.Context, crate string, info *CrateInfo)
 		Downloads:    crateData.Crate.Downloads,
 		Dependencies: deps,
 	}
+	info.Releases = make(map[string]string, len(crateData.Versions))
+	for _, v := range crateData.Versions {
+		if v.Num == info.Version {
+			info.Yanked, info.YankMessage = v.Yanked, v.YankMessage
+		}
+		if day := integrations.ReleaseDay(v.CreatedAt); day != "" && !v.Yanked {
+			info.Releases[v.Num] = day
+		}
+	}
 	return nil
 }
 
@@ -99,7 +108,8 @@ func (c *Client) fetchDependencies(ctx context.Context, crate, version string) (
 }
 
 type crateResponse struct {
-	Crate crateData `json:"crate"`
+	Crate    crateData     `json:"crate"`
+	Versions []versionData `json:"versions"`
 }
 
 type crateData struct {
@@ -112,6 +122,13 @@ type crateData struct {
 	Downloads   int    `json:"downloads"`
 }
 
+type versionData struct {
+	Num         string `json:"num"`
+	Yanked      bool   `json:"yanked"`
+	YankMessage string `json:"yank_message"`
+	CreatedAt   string `json:"created_at"`
+}
+
 type depsResponse struct {
 	Dependencies []dependency `json:"dependencies"`
 }
@@ -29,6 +29,10 @@ func TestClient_FetchCrate(t *testing.T) {
 			Repository:  "https://github.com/serde-rs/serde",
 			Downloads:   1000000,
 		},
+		Versions: []versionData{
+			{Num: "1.0.1", CreatedAt: "2024-05-02T08:00:00.000000+00:00"},
+			{Num: "1.0.0", Yanked: true, YankMessage: "unsound", CreatedAt: "2024-01-01T08:00:00.000000+00:00"},
+		},
 	}
 	depsResp := depsResponse{
 		Dependencies: []dependency{
@@ -70,6 +74,12 @@ func TestClient_FetchCrate(t *testing.T) {
 	if info.Dependencies[0] != "serde_derive" {
 		t.Errorf("expected serde_derive, got %s", info.Dependencies[0])
 	}
+	if !info.Yanked || info.YankMessage != "unsound" {
+		t.Errorf("expected yanked 1.0.0, got %v %q", info.Yanked, info.YankMessage)
+	}
+	if len(info.Releases) != 1 || info.Releases["1.0.1"] != "2024-05-02" {
+		t.Errorf("expected yanked versions left out of releases, got %v", info.Releases)
+	}
 }
 
 func TestClient_FetchCrate_NotFound(t *testing.T) {
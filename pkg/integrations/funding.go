@@ -0,0 +1,62 @@
+package integrations
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// fundingPlatforms are URL prefixes, without scheme, of donation and
+// sponsorship platforms.
+var fundingPlatforms = []string{
+	"github.com/sponsors/",
+	"opencollective.com/",
+	"patreon.com/",
+	"ko-fi.com/",
+	"liberapay.com/",
+	"tidelift.com/funding/",
+	"tidelift.com/subscription/",
+	"buymeacoffee.com/",
+	"polar.sh/",
+	"thanks.dev/",
+	"issuehunt.io/r/",
+	"funding.communitybridge.org/",
+}
+
+func IsFundingURL(u string) bool {
+	u = strings.ToLower(u)
+	u = strings.TrimPrefix(strings.TrimPrefix(u, "https://"), "http://")
+	u = strings.TrimPrefix(u, "www.")
+	for _, p := range fundingPlatforms {
+		if strings.HasPrefix(u, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsWebURL reports whether u is an absolute http or https link. Funding
+// links come from package manifests and end up as hrefs in rendered
+// output, so anything else, such as a javascript: URL, is dropped.
+func IsWebURL(u string) bool {
+	parsed, err := url.Parse(u)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// FundingURLs picks funding links out of a package's project URLs: those
+// labelled as such ("Funding", "Sponsor", "Donate") and those pointing at a
+// known funding platform.
+func FundingURLs(projectURLs map[string]string) []string {
+	var urls []string
+	for label, u := range projectURLs {
+		if !IsWebURL(u) {
+			continue
+		}
+		l := strings.ToLower(label)
+		if strings.Contains(l, "fund") || strings.Contains(l, "sponsor") || strings.Contains(l, "donat") || IsFundingURL(u) {
+			urls = append(urls, u)
+		}
+	}
+	slices.Sort(urls)
+	return slices.Compact(urls)
+}
@@ -0,0 +1,54 @@
+package integrations
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestIsFundingURL(t *testing.T) {
+	tests := map[string]bool{
+		"https://github.com/sponsors/davidism":   true,
+		"https://opencollective.com/webpack":     true,
+		"https://www.patreon.com/someone":        true,
+		"https://tidelift.com/funding/github/x":  true,
+		"https://github.com/pallets/flask":       false,
+		"https://example.com/opencollective.com": false,
+	}
+	for u, want := range tests {
+		if got := IsFundingURL(u); got != want {
+			t.Errorf("IsFundingURL(%q) = %v, want %v", u, got, want)
+		}
+	}
+}
+
+func TestIsWebURL(t *testing.T) {
+	tests := map[string]bool{
+		"https://opencollective.com/webpack": true,
+		"HTTP://example.com/donate":          true,
+		"javascript:alert(1)":                false,
+		"JavaScript://example.com/%0Aalert":  false,
+		"data:text/html,<script>":            false,
+		"//example.com/donate":               false,
+		"https:///no-host":                   false,
+	}
+	for u, want := range tests {
+		if got := IsWebURL(u); got != want {
+			t.Errorf("IsWebURL(%q) = %v, want %v", u, got, want)
+		}
+	}
+}
+
+func TestFundingURLs(t *testing.T) {
+	urls := map[string]string{
+		"Donate":        "https://palletsprojects.com/donate",
+		"Funding":       "https://palletsprojects.com/donate",
+		"Source":        "https://github.com/pallets/flask",
+		"Chat":          "https://opencollective.com/pallets",
+		"Documentation": "https://flask.palletsprojects.com",
+		"Sponsor":       "javascript:alert(1)",
+	}
+	want := []string{"https://opencollective.com/pallets", "https://palletsprojects.com/donate"}
+	if got := FundingURLs(urls); !slices.Equal(got, want) {
+		t.Errorf("FundingURLs = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,152 @@
+package gitea
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/matzehuels/stacktower/pkg/integrations"
+)
+
+const (
+	DefaultBaseURL = "https://codeberg.org"
+
+	commitSample    = 50
+	maxContributors = 5
+)
+
+// Client talks to any Gitea-compatible API: Gitea, Forgejo and Codeberg,
+// hosted or self-hosted.
+type Client struct {
+	integrations.BaseClient
+	host       string
+	apiURL     string
+	headers    map[string]string
+	urlPattern *regexp.Regexp
+}
+
+func NewClient(baseURL, token string, cacheTTL time.Duration) (*Client, error) {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid gitea base URL %q", baseURL)
+	}
+
+	base, err := integrations.NewBaseClient(cacheTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := map[string]string{"Accept": "application/json"}
+	if token != "" {
+		headers["Authorization"] = "token " + token
+	}
+
+	base.Scope = integrations.ScopeOf(u.String(), token)
+	return &Client{
+		BaseClient: base,
+		host:       u.Host,
+		apiURL:     u.String() + "/api/v1",
+		headers:    headers,
+		urlPattern: regexp.MustCompile(`https?://` + regexp.QuoteMeta(u.Host+u.Path) + `/([^/]+)/([^/]+)`),
+	}, nil
+}
+
+func (c *Client) Host() string { return c.host }
+
+func (c *Client) Fetch(ctx context.Context, owner, repo string, refresh bool) (*integrations.RepoMetrics, error) {
+	cacheKey := "gitea:" + c.host + ":" + owner + "/" + repo
+
+	var m integrations.RepoMetrics
+	err := c.FetchWithCache(ctx, cacheKey, refresh, func(ctx context.Context, v any) error {
+		return c.fetchMetrics(ctx, owner, repo, v.(*integrations.RepoMetrics))
+	}, &m)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (c *Client) fetchMetrics(ctx context.Context, owner, repo string, m *integrations.RepoMetrics) error {
+	base := fmt.Sprintf("%s/repos/%s/%s", c.apiURL, owner, repo)
+
+	var data repoResponse
+	if err := c.DoRequest(ctx, base, c.headers, &data); err != nil {
+		if errors.Is(err, integrations.ErrNotFound) {
+			return fmt.Errorf("%w: %s repo %s/%s", err, c.host, owner, repo)
+		}
+		return err
+	}
+
+	*m = integrations.RepoMetrics{
+		RepoURL:  data.HTMLURL,
+		Owner:    data.Owner.Login,
+		Stars:    data.Stars,
+		SizeKB:   data.Size,
+		Language: data.Language,
+		Topics:   data.Topics,
+		Archived: data.Archived,
+	}
+	if data.UpdatedAt != nil {
+		m.LastCommitAt = data.UpdatedAt
+	}
+
+	var commits []commit
+	commitsURL := fmt.Sprintf("%s/commits?limit=%d&stat=false&verification=false&files=false", base, commitSample)
+	if err := c.DoRequest(ctx, commitsURL, c.headers, &commits); err == nil {
+		if len(commits) > 0 {
+			m.LastCommitAt = &commits[0].Commit.Author.Date
+		}
+		logins := make([]string, 0, len(commits))
+		for _, c := range commits {
+			if c.Author != nil {
+				logins = append(logins, c.Author.Login)
+			}
+		}
+		m.Contributors = integrations.RankContributors(logins, maxContributors)
+	}
+
+	var releases []release
+	if err := c.DoRequest(ctx, base+"/releases?limit=1", c.headers, &releases); err == nil && len(releases) > 0 {
+		m.LastReleaseAt = &releases[0].PublishedAt
+	}
+	return nil
+}
+
+func (c *Client) ExtractURL(projectURLs map[string]string, homepage string) (owner, repo string, ok bool) {
+	return integrations.ExtractRepoURL(c.urlPattern, projectURLs, homepage)
+}
+
+type repoResponse struct {
+	HTMLURL   string     `json:"html_url"`
+	Stars     int        `json:"stars_count"`
+	Size      int        `json:"size"`
+	Language  string     `json:"language"`
+	Topics    []string   `json:"topics"`
+	Archived  bool       `json:"archived"`
+	UpdatedAt *time.Time `json:"updated_at"`
+	Owner     struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+}
+
+type commit struct {
+	Commit struct {
+		Author struct {
+			Date time.Time `json:"date"`
+		} `json:"author"`
+	} `json:"commit"`
+	Author *struct {
+		Login string `json:"login"`
+	} `json:"author"`
+}
+
+type release struct {
+	PublishedAt time.Time `json:"published_at"`
+}
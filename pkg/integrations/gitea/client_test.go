@@ -0,0 +1,98 @@
+package gitea
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_Fetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/v1/repos/owner/repo":
+			w.Write([]byte(`{"html_url": "https://codeberg.org/owner/repo", "stars_count": 7, "size": 300, "archived": true, "owner": {"login": "owner"}}`))
+		case "/api/v1/repos/owner/repo/commits":
+			w.Write([]byte(`[
+				{"commit": {"author": {"date": "2024-06-02T12:00:00Z"}}, "author": {"login": "carol"}},
+				{"commit": {"author": {"date": "2024-06-01T12:00:00Z"}}, "author": null}
+			]`))
+		case "/api/v1/repos/owner/repo/releases":
+			w.Write([]byte(`[]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewClient(server.URL, "tok", time.Hour)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	c.HTTP = server.Client()
+
+	m, err := c.Fetch(context.Background(), "owner", "repo", true)
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+
+	if m.Stars != 7 || !m.Archived {
+		t.Errorf("unexpected metrics %+v", m)
+	}
+	if m.LastCommitAt == nil || m.LastCommitAt.Format("2006-01-02") != "2024-06-02" {
+		t.Errorf("unexpected last commit %v", m.LastCommitAt)
+	}
+	if m.LastReleaseAt != nil {
+		t.Errorf("expected no release, got %v", m.LastReleaseAt)
+	}
+	if len(m.Contributors) != 1 || m.Contributors[0].Login != "carol" {
+		t.Errorf("unexpected contributors %+v", m.Contributors)
+	}
+}
+
+func TestClient_ExtractURL(t *testing.T) {
+	c, err := NewClient("https://git.example.com/", "", time.Hour)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	if c.Host() != "git.example.com" {
+		t.Errorf("got host %q", c.Host())
+	}
+
+	tests := []struct {
+		urls      map[string]string
+		wantOwner string
+		wantRepo  string
+		wantOK    bool
+	}{
+		{
+			urls:      map[string]string{"Source": "https://git.example.com/team/lib"},
+			wantOwner: "team",
+			wantRepo:  "lib",
+			wantOK:    true,
+		},
+		{
+			urls:   map[string]string{"Source": "https://codeberg.org/team/lib"},
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		owner, repo, ok := c.ExtractURL(tt.urls, "")
+		if ok != tt.wantOK {
+			t.Errorf("%v: got ok=%v, want %v", tt.urls, ok, tt.wantOK)
+		}
+		if ok && (owner != tt.wantOwner || repo != tt.wantRepo) {
+			t.Errorf("got %s/%s, want %s/%s", owner, repo, tt.wantOwner, tt.wantRepo)
+		}
+	}
+}
+
+func TestNewClient_InvalidURL(t *testing.T) {
+	if _, err := NewClient("not a url", "", time.Hour); err == nil {
+		t.Error("expected error for base URL without host")
+	}
+}
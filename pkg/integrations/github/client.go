@@ -20,7 +20,7 @@ type Client struct {
 }
 
 func NewClient(token string, cacheTTL time.Duration) (*Client, error) {
-	cache, err := integrations.NewCache(cacheTTL)
+	base, err := integrations.NewBaseClient(cacheTTL)
 	if err != nil {
 		return nil, err
 	}
@@ -30,23 +30,21 @@ func NewClient(token string, cacheTTL time.Duration) (*Client, error) {
 		headers["Authorization"] = "Bearer " + token
 	}
 
+	base.Scope = integrations.ScopeOf("https://api.github.com", token)
 	return &Client{
-		BaseClient: integrations.BaseClient{
-			HTTP:  integrations.NewHTTPClient(),
-			Cache: cache,
-		},
-		token:   token,
-		baseURL: "https://api.github.com",
-		headers: headers,
+		BaseClient: base,
+		token:      token,
+		baseURL:    "https://api.github.com",
+		headers:    headers,
 	}, nil
 }
 
 func (c *Client) Fetch(ctx context.Context, owner, repo string, refresh bool) (*integrations.RepoMetrics, error) {
-	cacheKey := "github:" + owner + "/" + repo
+	cacheKey := RepoRef{owner, repo}.cacheKey()
 
 	var m integrations.RepoMetrics
-	err := c.FetchWithCache(ctx, cacheKey, refresh, func() error {
-		return c.fetchMetrics(ctx, owner, repo, &m)
+	err := c.FetchWithCache(ctx, cacheKey, refresh, func(ctx context.Context, v any) error {
+		return c.fetchMetrics(ctx, owner, repo, v.(*integrations.RepoMetrics))
 	}, &m)
 	if err != nil {
 		return nil, err
@@ -79,6 +77,9 @@ func (c *Client) fetchMetrics(ctx context.Context, owner, repo string, m *integr
 	if contributors, err := c.fetchContributors(ctx, owner, repo); err == nil {
 		m.Contributors = contributors
 	}
+	if funding, err := c.fetchFunding(ctx, owner, repo); err == nil {
+		m.Funding = funding
+	}
 	return nil
 }
 
@@ -133,9 +134,9 @@ func (c *Client) SearchPackageRepo(ctx context.Context, pkgName, manifestFile st
 	cacheKey := fmt.Sprintf("github:search:%s:%s", manifestFile, pkgName)
 
 	var result searchCacheEntry
-	err := c.FetchWithCache(ctx, cacheKey, false, func() error {
+	err := c.FetchWithCache(ctx, cacheKey, false, func(ctx context.Context, v any) error {
 		o, r, found := c.doCodeSearch(ctx, pkgName, manifestFile)
-		result = searchCacheEntry{Owner: o, Repo: r, Found: found}
+		*v.(*searchCacheEntry) = searchCacheEntry{Owner: o, Repo: r, Found: found}
 		return nil
 	}, &result)
 
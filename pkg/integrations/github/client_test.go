@@ -2,12 +2,16 @@ package github
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"slices"
 	"testing"
 	"time"
+
+	"github.com/matzehuels/stacktower/pkg/httputil"
 )
 
 func TestClient_Fetch(t *testing.T) {
@@ -30,6 +34,11 @@ func TestClient_Fetch(t *testing.T) {
 			json.NewEncoder(w).Encode([]contributorResponse{
 				{Login: "user1", Contributions: 10, Type: "User"},
 			})
+		case path == "/repos/owner/repo/contents/.github/FUNDING.yml":
+			json.NewEncoder(w).Encode(contentResponse{
+				Content:  base64.StdEncoding.EncodeToString([]byte("github: owner\ncustom: https://example.com/donate\n")),
+				Encoding: "base64",
+			})
 		default:
 			http.NotFound(w, r)
 		}
@@ -38,6 +47,7 @@ func TestClient_Fetch(t *testing.T) {
 
 	c, _ := NewClient("", time.Hour)
 	c.HTTP = server.Client()
+	c.Cache, _ = httputil.NewCache(t.TempDir(), time.Hour)
 	c.baseURL = server.URL
 
 	metrics, err := c.Fetch(context.Background(), "owner", "repo", false)
@@ -51,6 +61,35 @@ func TestClient_Fetch(t *testing.T) {
 	if metrics.SizeKB != 500 {
 		t.Errorf("expected 500 KB, got %d", metrics.SizeKB)
 	}
+	want := []string{"https://github.com/sponsors/owner", "https://example.com/donate"}
+	if !slices.Equal(metrics.Funding, want) {
+		t.Errorf("funding = %v, want %v", metrics.Funding, want)
+	}
+}
+
+func TestParseFunding(t *testing.T) {
+	data := `# These are supported funding model platforms
+github: [alice, "bob"]
+patreon: carol # monthly
+open_collective:
+ko_fi: dave
+tidelift: pypi/flask
+unknown_platform: eve
+custom:
+  - https://example.com/donate
+  - 'javascript:alert(1)'
+`
+	want := []string{
+		"https://github.com/sponsors/alice",
+		"https://github.com/sponsors/bob",
+		"https://www.patreon.com/carol",
+		"https://ko-fi.com/dave",
+		"https://tidelift.com/funding/github/pypi/flask",
+		"https://example.com/donate",
+	}
+	if got := parseFunding(data); !slices.Equal(got, want) {
+		t.Errorf("parseFunding = %v, want %v", got, want)
+	}
 }
 
 func TestExtractURL(t *testing.T) {
@@ -0,0 +1,105 @@
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/matzehuels/stacktower/pkg/integrations"
+)
+
+// fundingPlatforms maps FUNDING.yml keys to the page an account name links
+// to, as GitHub itself renders the sponsor button.
+var fundingPlatforms = map[string]string{
+	"github":           "https://github.com/sponsors/%s",
+	"patreon":          "https://www.patreon.com/%s",
+	"open_collective":  "https://opencollective.com/%s",
+	"ko_fi":            "https://ko-fi.com/%s",
+	"tidelift":         "https://tidelift.com/funding/github/%s",
+	"community_bridge": "https://funding.communitybridge.org/projects/%s",
+	"liberapay":        "https://liberapay.com/%s",
+	"issuehunt":        "https://issuehunt.io/r/%s",
+	"polar":            "https://polar.sh/%s",
+	"buy_me_a_coffee":  "https://buymeacoffee.com/%s",
+	"thanks_dev":       "https://thanks.dev/%s",
+}
+
+// fetchFunding reads the repository's .github/FUNDING.yml through the
+// contents API, the REST counterpart of the GraphQL fundingLinks field.
+func (c *Client) fetchFunding(ctx context.Context, owner, repo string) ([]string, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/contents/.github/FUNDING.yml", c.baseURL, owner, repo)
+
+	var data contentResponse
+	if err := c.DoRequest(ctx, url, c.headers, &data); err != nil {
+		return nil, fmt.Errorf("no funding file")
+	}
+	if data.Encoding != "base64" {
+		return nil, fmt.Errorf("funding file: unsupported encoding %q", data.Encoding)
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(data.Content, "\n", ""))
+	if err != nil {
+		return nil, fmt.Errorf("funding file: %w", err)
+	}
+	return parseFunding(string(raw)), nil
+}
+
+// parseFunding turns FUNDING.yml into links. The file is a flat map of
+// platform keys to an account name or a list of them, written inline or
+// as a block; custom entries are links already. Only http(s) links are
+// kept.
+func parseFunding(data string) []string {
+	var urls []string
+	add := func(key, value string) {
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		if value == "" {
+			return
+		}
+		u := value
+		if key != "custom" {
+			pattern, ok := fundingPlatforms[key]
+			if !ok {
+				return
+			}
+			u = fmt.Sprintf(pattern, value)
+		}
+		if integrations.IsWebURL(u) && !slices.Contains(urls, u) {
+			urls = append(urls, u)
+		}
+	}
+
+	var key string
+	for line := range strings.Lines(data) {
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if item, ok := strings.CutPrefix(trimmed, "- "); ok {
+			add(key, item)
+			continue
+		}
+		k, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(k)
+		value = strings.TrimSpace(value)
+		if list, ok := strings.CutPrefix(value, "["); ok {
+			for _, item := range strings.Split(strings.TrimSuffix(list, "]"), ",") {
+				add(key, item)
+			}
+			continue
+		}
+		add(key, value)
+	}
+	return urls
+}
+
+type contentResponse struct {
+	Content  string `json:"content"`
+	Encoding string `json:"encoding"`
+}
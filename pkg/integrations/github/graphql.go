@@ -0,0 +1,280 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/matzehuels/stacktower/pkg/integrations"
+)
+
+// batchSize bounds the repositories per GraphQL request. Each one costs a
+// commit-history connection, and GitHub rejects queries above 500k nodes.
+const batchSize = 25
+
+type RepoRef struct {
+	Owner string
+	Repo  string
+}
+
+func (r RepoRef) cacheKey() string { return "github:" + r.Owner + "/" + r.Repo }
+
+// graphQLCacheKey keeps GraphQL results apart from REST ones: the two rank
+// contributors differently (recent commits versus contributor statistics),
+// so a cache entry must not depend on which path happened to fill it.
+func (r RepoRef) graphQLCacheKey() string { return "github:graphql:" + r.Owner + "/" + r.Repo }
+
+// FetchBatch returns metrics for many repositories, keyed by reference.
+// With a token it asks the GraphQL API for batchSize repositories per
+// request; without one, or when a batch fails, it falls back to Fetch per
+// repository. Once GraphQL reports its rate limit, the rest of the batch
+// goes through REST, which is metered separately. The map is partial when
+// the returned error is non-nil.
+func (c *Client) FetchBatch(ctx context.Context, refs []RepoRef, refresh bool) (map[RepoRef]*integrations.RepoMetrics, error) {
+	results := make(map[RepoRef]*integrations.RepoMetrics, len(refs))
+	var errs []error
+
+	useGraphQL := c.token != ""
+	var pending []RepoRef
+	for _, ref := range refs {
+		if _, seen := results[ref]; seen {
+			continue
+		}
+		key := ref.cacheKey()
+		if useGraphQL {
+			key = ref.graphQLCacheKey()
+		}
+		var m integrations.RepoMetrics
+		if !refresh {
+			if ok, _ := c.Cache.Get(key, &m); ok {
+				results[ref] = &m
+				continue
+			}
+		}
+		results[ref] = nil
+		pending = append(pending, ref)
+	}
+
+	for len(pending) > 0 {
+		chunk := pending[:min(batchSize, len(pending))]
+		pending = pending[len(chunk):]
+
+		if useGraphQL {
+			err := c.fetchGraphQL(ctx, chunk, results, &errs)
+			if err == nil {
+				continue
+			}
+			if errors.Is(err, integrations.ErrRateLimited) {
+				useGraphQL = false
+			}
+		}
+		for _, ref := range chunk {
+			m, err := c.Fetch(ctx, ref.Owner, ref.Repo, refresh)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			results[ref] = m
+		}
+	}
+
+	for ref, m := range results {
+		if m == nil {
+			delete(results, ref)
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+func (c *Client) fetchGraphQL(ctx context.Context, refs []RepoRef, results map[RepoRef]*integrations.RepoMetrics, errs *[]error) error {
+	query, vars := batchQuery(refs)
+
+	var resp graphQLResponse
+	err := c.WithRetry(ctx, func() error {
+		resp = graphQLResponse{}
+		return c.PostJSON(ctx, c.baseURL+"/graphql", c.headers, graphQLRequest{Query: query, Variables: vars}, &resp)
+	})
+	if err != nil {
+		return err
+	}
+	if resp.Data == nil {
+		if len(resp.Errors) == 0 {
+			return errors.New("graphql: empty response")
+		}
+		if resp.Errors[0].Type == "RATE_LIMITED" {
+			return fmt.Errorf("%w: %s", integrations.ErrRateLimited, resp.Errors[0].Message)
+		}
+		return fmt.Errorf("graphql: %s", resp.Errors[0].Message)
+	}
+
+	for i, ref := range refs {
+		node := resp.Data[fmt.Sprintf("r%d", i)]
+		if node == nil {
+			*errs = append(*errs, fmt.Errorf("%w: github repo %s/%s", integrations.ErrNotFound, ref.Owner, ref.Repo))
+			continue
+		}
+		m := node.metrics(ref)
+		_ = c.Cache.Set(ref.graphQLCacheKey(), m)
+		results[ref] = m
+	}
+	return nil
+}
+
+const repoFragment = `fragment repo on Repository {
+  stargazerCount
+  diskUsage
+  pushedAt
+  isArchived
+  licenseInfo { spdxId }
+  primaryLanguage { name }
+  repositoryTopics(first: 20) { nodes { topic { name } } }
+  latestRelease { publishedAt }
+  openIssues: issues(states: OPEN) { totalCount }
+  closedIssues: issues(states: CLOSED) { totalCount }
+  recentlyClosed: issues(first: 20, states: CLOSED, orderBy: {field: UPDATED_AT, direction: DESC}) { nodes { createdAt closedAt } }
+  fundingLinks { url }
+  owner { ... on Sponsorable { hasSponsorsListing } }
+  defaultBranchRef { target { ... on Commit { history(first: 50) { nodes { author { user { login } } } } } } }
+}`
+
+func batchQuery(refs []RepoRef) (string, map[string]string) {
+	var params, fields strings.Builder
+	vars := make(map[string]string, 2*len(refs))
+	for i, ref := range refs {
+		fmt.Fprintf(&params, "$o%d: String!, $n%d: String!, ", i, i)
+		fmt.Fprintf(&fields, "  r%d: repository(owner: $o%d, name: $n%d) { ...repo }\n", i, i, i)
+		vars[fmt.Sprintf("o%d", i)] = ref.Owner
+		vars[fmt.Sprintf("n%d", i)] = ref.Repo
+	}
+	query := fmt.Sprintf("query(%s) {\n%s}\n%s", strings.TrimSuffix(params.String(), ", "), fields.String(), repoFragment)
+	return query, vars
+}
+
+type graphQLRequest struct {
+	Query     string            `json:"query"`
+	Variables map[string]string `json:"variables"`
+}
+
+type graphQLResponse struct {
+	Data   map[string]*repoNode `json:"data"`
+	Errors []struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+type totalCount struct {
+	TotalCount int `json:"totalCount"`
+}
+
+type repoNode struct {
+	StargazerCount int        `json:"stargazerCount"`
+	DiskUsage      int        `json:"diskUsage"`
+	PushedAt       *time.Time `json:"pushedAt"`
+	IsArchived     bool       `json:"isArchived"`
+	LicenseInfo    *struct {
+		SPDXID string `json:"spdxId"`
+	} `json:"licenseInfo"`
+	PrimaryLanguage *struct {
+		Name string `json:"name"`
+	} `json:"primaryLanguage"`
+	RepositoryTopics struct {
+		Nodes []struct {
+			Topic struct {
+				Name string `json:"name"`
+			} `json:"topic"`
+		} `json:"nodes"`
+	} `json:"repositoryTopics"`
+	LatestRelease *struct {
+		PublishedAt time.Time `json:"publishedAt"`
+	} `json:"latestRelease"`
+	OpenIssues     totalCount `json:"openIssues"`
+	ClosedIssues   totalCount `json:"closedIssues"`
+	RecentlyClosed struct {
+		Nodes []struct {
+			CreatedAt time.Time  `json:"createdAt"`
+			ClosedAt  *time.Time `json:"closedAt"`
+		} `json:"nodes"`
+	} `json:"recentlyClosed"`
+	FundingLinks []struct {
+		URL string `json:"url"`
+	} `json:"fundingLinks"`
+	Owner struct {
+		HasSponsorsListing bool `json:"hasSponsorsListing"`
+	} `json:"owner"`
+	DefaultBranchRef *struct {
+		Target struct {
+			History struct {
+				Nodes []struct {
+					Author struct {
+						User *struct {
+							Login string `json:"login"`
+						} `json:"user"`
+					} `json:"author"`
+				} `json:"nodes"`
+			} `json:"history"`
+		} `json:"target"`
+	} `json:"defaultBranchRef"`
+}
+
+// metrics converts a GraphQL node to the REST-shaped metrics. GraphQL has
+// no contributor statistics, so maintainers are ranked from recent commits;
+// bot authors carry no user and drop out. Issue close time is the median
+// over the most recently updated closed issues. Funding lists the owner's
+// Sponsors page, when there is one, ahead of the FUNDING.yml links that are
+// http(s) URLs.
+func (n *repoNode) metrics(ref RepoRef) *integrations.RepoMetrics {
+	m := &integrations.RepoMetrics{
+		RepoURL:      fmt.Sprintf("https://github.com/%s/%s", ref.Owner, ref.Repo),
+		Owner:        ref.Owner,
+		Stars:        n.StargazerCount,
+		SizeKB:       n.DiskUsage,
+		LastCommitAt: n.PushedAt,
+		Archived:     n.IsArchived,
+		OpenIssues:   n.OpenIssues.TotalCount,
+		ClosedIssues: n.ClosedIssues.TotalCount,
+	}
+	if n.LicenseInfo != nil {
+		m.License = n.LicenseInfo.SPDXID
+	}
+	if n.PrimaryLanguage != nil {
+		m.Language = n.PrimaryLanguage.Name
+	}
+	for _, t := range n.RepositoryTopics.Nodes {
+		m.Topics = append(m.Topics, t.Topic.Name)
+	}
+	if n.Owner.HasSponsorsListing {
+		m.Funding = append(m.Funding, "https://github.com/sponsors/"+ref.Owner)
+	}
+	for _, f := range n.FundingLinks {
+		if integrations.IsWebURL(f.URL) && !slices.Contains(m.Funding, f.URL) {
+			m.Funding = append(m.Funding, f.URL)
+		}
+	}
+	var closeDays []float64
+	for _, is := range n.RecentlyClosed.Nodes {
+		if is.ClosedAt != nil {
+			closeDays = append(closeDays, is.ClosedAt.Sub(is.CreatedAt).Hours()/24)
+		}
+	}
+	if len(closeDays) > 0 {
+		slices.Sort(closeDays)
+		m.IssueCloseDays = closeDays[(len(closeDays)-1)/2]
+	}
+	if n.LatestRelease != nil {
+		m.LastReleaseAt = &n.LatestRelease.PublishedAt
+	}
+	if n.DefaultBranchRef != nil {
+		var logins []string
+		for _, c := range n.DefaultBranchRef.Target.History.Nodes {
+			if c.Author.User != nil {
+				logins = append(logins, c.Author.User.Login)
+			}
+		}
+		m.Contributors = integrations.RankContributors(logins, 5)
+	}
+	return m
+}
@@ -0,0 +1,246 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matzehuels/stacktower/pkg/httputil"
+	"github.com/matzehuels/stacktower/pkg/integrations"
+)
+
+func TestClient_FetchBatch_GraphQL(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graphql" || r.Method != http.MethodPost {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		requests++
+
+		var req graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if req.Variables["o0"] != "owner" || req.Variables["n1"] != "missing" {
+			t.Errorf("unexpected variables %v", req.Variables)
+		}
+		if !strings.Contains(req.Query, "r1: repository(owner: $o1, name: $n1)") {
+			t.Errorf("query lacks aliased repository:\n%s", req.Query)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"data": {
+				"r0": {
+					"stargazerCount": 120,
+					"diskUsage": 900,
+					"pushedAt": "2024-03-01T00:00:00Z",
+					"licenseInfo": {"spdxId": "MIT"},
+					"primaryLanguage": {"name": "Go"},
+					"repositoryTopics": {"nodes": [{"topic": {"name": "cli"}}]},
+					"latestRelease": {"publishedAt": "2024-02-01T00:00:00Z"},
+					"openIssues": {"totalCount": 3},
+					"closedIssues": {"totalCount": 27},
+					"recentlyClosed": {"nodes": [
+						{"createdAt": "2024-01-01T00:00:00Z", "closedAt": "2024-01-03T00:00:00Z"},
+						{"createdAt": "2024-01-01T00:00:00Z", "closedAt": "2024-01-11T00:00:00Z"},
+						{"createdAt": "2024-01-01T00:00:00Z", "closedAt": "2024-03-01T00:00:00Z"}
+					]},
+					"fundingLinks": [{"platform": "OPEN_COLLECTIVE", "url": "https://opencollective.com/repo"}, {"platform": "GITHUB", "url": "https://github.com/sponsors/owner"}],
+					"owner": {"hasSponsorsListing": true},
+					"defaultBranchRef": {"target": {"history": {"nodes": [
+						{"author": {"user": {"login": "ann"}}},
+						{"author": {"user": null}},
+						{"author": {"user": {"login": "ann"}}},
+						{"author": {"user": {"login": "ben"}}}
+					]}}}
+				},
+				"r1": null
+			},
+			"errors": [{"type": "NOT_FOUND", "message": "Could not resolve to a Repository"}]
+		}`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient("token", time.Hour)
+	c.HTTP = server.Client()
+	c.Cache, _ = httputil.NewCache(t.TempDir(), time.Hour)
+	c.baseURL = server.URL
+
+	found := RepoRef{"owner", "repo"}
+	missing := RepoRef{"owner", "missing"}
+	got, err := c.FetchBatch(context.Background(), []RepoRef{found, missing, found}, true)
+	if !errors.Is(err, integrations.ErrNotFound) {
+		t.Errorf("expected not-found error for missing repo, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected a single request, got %d", requests)
+	}
+	if _, ok := got[missing]; ok {
+		t.Error("missing repo should have no metrics")
+	}
+
+	m := got[found]
+	if m == nil {
+		t.Fatal("expected metrics for owner/repo")
+	}
+	if m.Stars != 120 || m.SizeKB != 900 || m.License != "MIT" || m.Language != "Go" {
+		t.Errorf("unexpected metrics %+v", m)
+	}
+	if m.OpenIssues != 3 || m.ClosedIssues != 27 {
+		t.Errorf("unexpected issue counts %d/%d", m.OpenIssues, m.ClosedIssues)
+	}
+	if m.IssueCloseDays != 10 {
+		t.Errorf("got median close time %.1f days, want 10", m.IssueCloseDays)
+	}
+	if want := []string{"https://github.com/sponsors/owner", "https://opencollective.com/repo"}; !slices.Equal(m.Funding, want) {
+		t.Errorf("got funding %v, want %v", m.Funding, want)
+	}
+	if m.LastReleaseAt == nil || m.LastReleaseAt.Format("2006-01-02") != "2024-02-01" {
+		t.Errorf("unexpected last release %v", m.LastReleaseAt)
+	}
+	if len(m.Contributors) != 2 || m.Contributors[0].Login != "ann" || m.Contributors[0].Contributions != 2 {
+		t.Errorf("unexpected contributors %+v", m.Contributors)
+	}
+}
+
+func TestClient_FetchBatch_RESTFallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/graphql":
+			t.Error("GraphQL requires a token and must not be used without one")
+		case "/repos/owner/repo":
+			json.NewEncoder(w).Encode(repoResponse{Stars: 5})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, _ := NewClient("", time.Hour)
+	c.HTTP = server.Client()
+	c.Cache, _ = httputil.NewCache(t.TempDir(), time.Hour)
+	c.baseURL = server.URL
+
+	ref := RepoRef{"owner", "repo"}
+	got, err := c.FetchBatch(context.Background(), []RepoRef{ref}, true)
+	if err != nil {
+		t.Fatalf("FetchBatch failed: %v", err)
+	}
+	if got[ref] == nil || got[ref].Stars != 5 {
+		t.Errorf("unexpected metrics %+v", got[ref])
+	}
+}
+
+func TestClient_FetchBatch_RateLimitFallsBackToREST(t *testing.T) {
+	var graphQL, rest int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/graphql":
+			graphQL++
+			w.Write([]byte(`{"errors": [{"type": "RATE_LIMITED", "message": "API rate limit exceeded"}]}`))
+		case strings.HasPrefix(r.URL.Path, "/repos/owner/") && strings.Count(r.URL.Path, "/") == 3:
+			rest++
+			json.NewEncoder(w).Encode(repoResponse{Stars: 5})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, _ := NewClient("token", time.Hour)
+	c.HTTP = server.Client()
+	c.Cache, _ = httputil.NewCache(t.TempDir(), time.Hour)
+	c.baseURL = server.URL
+
+	var refs []RepoRef
+	for i := range batchSize + 5 {
+		refs = append(refs, RepoRef{"owner", fmt.Sprintf("repo%d", i)})
+	}
+	got, err := c.FetchBatch(context.Background(), refs, true)
+	if err != nil {
+		t.Fatalf("FetchBatch failed: %v", err)
+	}
+	if graphQL != 1 {
+		t.Errorf("expected GraphQL to be abandoned after the rate limit, got %d requests", graphQL)
+	}
+	if rest != len(refs) || len(got) != len(refs) {
+		t.Errorf("expected every repo through REST, got %d requests and %d results", rest, len(got))
+	}
+}
+
+func TestClient_FetchBatch_RateLimitFallbackUsesRESTCache(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graphql" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"errors": [{"type": "RATE_LIMITED", "message": "API rate limit exceeded"}]}`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient("token", time.Hour)
+	c.HTTP = server.Client()
+	c.Cache, _ = httputil.NewCache(t.TempDir(), time.Hour)
+	c.baseURL = server.URL
+
+	ref := RepoRef{"owner", "repo"}
+	c.Cache.Set(ref.cacheKey(), integrations.RepoMetrics{Stars: 1})
+
+	got, err := c.FetchBatch(context.Background(), []RepoRef{ref}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[ref] == nil || got[ref].Stars != 1 {
+		t.Errorf("rate-limited batch should fall back to the cached REST entry, got %+v", got[ref])
+	}
+}
+
+func TestClient_FetchBatch_SeparateCacheKeys(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s", r.URL.Path)
+	}))
+	defer server.Close()
+
+	c, _ := NewClient("token", time.Hour)
+	c.HTTP = server.Client()
+	c.Cache, _ = httputil.NewCache(t.TempDir(), time.Hour)
+	c.baseURL = server.URL
+
+	ref := RepoRef{"owner", "repo"}
+	c.Cache.Set(ref.cacheKey(), integrations.RepoMetrics{Stars: 1})
+	c.Cache.Set(ref.graphQLCacheKey(), integrations.RepoMetrics{Stars: 2})
+
+	got, err := c.FetchBatch(context.Background(), []RepoRef{ref}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[ref].Stars != 2 {
+		t.Errorf("token-backed batch should read the GraphQL entry, got %+v", got[ref])
+	}
+}
+
+func TestBatchQuery(t *testing.T) {
+	refs := make([]RepoRef, 3)
+	query, vars := batchQuery(refs)
+	if len(vars) != 6 {
+		t.Errorf("expected 6 variables, got %d", len(vars))
+	}
+	if !strings.HasPrefix(query, "query($o0: String!, $n0: String!, $o1: String!") {
+		t.Errorf("unexpected query header:\n%s", query)
+	}
+	if !strings.Contains(query, "fragment repo on Repository") {
+		t.Error("query lacks repo fragment")
+	}
+}
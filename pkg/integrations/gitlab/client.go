@@ -16,17 +16,15 @@ type Client struct {
 }
 
 func NewClient(token string, cacheTTL time.Duration) (*Client, error) {
-	cache, err := integrations.NewCache(cacheTTL)
+	base, err := integrations.NewBaseClient(cacheTTL)
 	if err != nil {
 		return nil, err
 	}
+	base.Scope = integrations.ScopeOf("https://gitlab.com/api/v4", token)
 	return &Client{
-		BaseClient: integrations.BaseClient{
-			HTTP:  integrations.NewHTTPClient(),
-			Cache: cache,
-		},
-		token:   token,
-		baseURL: "https://gitlab.com/api/v4",
+		BaseClient: base,
+		token:      token,
+		baseURL:    "https://gitlab.com/api/v4",
 	}, nil
 }
 
@@ -21,24 +21,26 @@ type PackageInfo struct {
 	Description  string
 	License      string
 	Author       string
+	Funding      []string
+	Deprecated   string            // the deprecation message, when the version is deprecated
+	Releases     map[string]string // version to release day
 }
 
 type Client struct {
 	integrations.BaseClient
-	baseURL string
+	baseURL      string
+	downloadsURL string
 }
 
 func NewClient(cacheTTL time.Duration) (*Client, error) {
-	cache, err := integrations.NewCache(cacheTTL)
+	base, err := integrations.NewBaseClient(cacheTTL)
 	if err != nil {
 		return nil, err
 	}
 	return &Client{
-		BaseClient: integrations.BaseClient{
-			HTTP:  integrations.NewHTTPClient(),
-			Cache: cache,
-		},
-		baseURL: "https://registry.npmjs.org",
+		BaseClient:   base,
+		baseURL:      "https://registry.npmjs.org",
+		downloadsURL: "https://api.npmjs.org/downloads/point/last-month",
 	}, nil
 }
 
@@ -47,8 +49,8 @@ func (c *Client) FetchPackage(ctx context.Context, pkg string, refresh bool) (*P
 	cacheKey := "npm:" + pkg
 
 	var info PackageInfo
-	err := c.FetchWithCache(ctx, cacheKey, refresh, func() error {
-		return c.fetchPackage(ctx, pkg, &info)
+	err := c.FetchWithCache(ctx, cacheKey, refresh, func(ctx context.Context, v any) error {
+		return c.fetchPackage(ctx, pkg, v.(*PackageInfo))
 	}, &info)
 	if err != nil {
 		return nil, err
@@ -80,10 +82,56 @@ func (c *Client) fetchPackage(ctx context.Context, pkg string, info *PackageInfo
 		Repository:   normalizeRepoURL(extractString(vd.Repository, "url")),
 		HomePage:     vd.HomePage,
 		Dependencies: slices.Collect(maps.Keys(vd.Dependencies)),
+		Funding:      extractFunding(vd.Funding),
+		Deprecated:   extractDeprecated(vd.Deprecated),
+		Releases:     releaseDays(data.Time, data.Versions),
 	}
 	return nil
 }
 
+// releaseDays keeps the publish times of versions still in the registry;
+// the time map also holds "created", "modified" and unpublished versions.
+func releaseDays(times map[string]string, versions map[string]versionDetails) map[string]string {
+	days := make(map[string]string, len(versions))
+	for v := range versions {
+		if day := integrations.ReleaseDay(times[v]); day != "" {
+			days[v] = day
+		}
+	}
+	return days
+}
+
+// extractFunding reads package.json's funding field, which may be a URL,
+// a {type, url} object, or a list of either. Only http(s) links are kept.
+func extractFunding(v any) []string {
+	var urls []string
+	switch val := v.(type) {
+	case string, map[string]any:
+		if u := extractString(val, "url"); integrations.IsWebURL(u) {
+			urls = append(urls, u)
+		}
+	case []any:
+		for _, e := range val {
+			urls = append(urls, extractFunding(e)...)
+		}
+	}
+	return urls
+}
+
+// extractDeprecated reads a version's deprecated field. npm stores the
+// message as a string; some mirrors publish a bare true instead.
+func extractDeprecated(v any) string {
+	switch val := v.(type) {
+	case string:
+		return strings.TrimSpace(val)
+	case bool:
+		if val {
+			return "deprecated"
+		}
+	}
+	return ""
+}
+
 func extractString(v any, field string) string {
 	switch val := v.(type) {
 	case string:
@@ -115,6 +163,7 @@ type registryResponse struct {
 	Name     string                    `json:"name"`
 	DistTags distTags                  `json:"dist-tags"`
 	Versions map[string]versionDetails `json:"versions"`
+	Time     map[string]string         `json:"time"`
 }
 
 type distTags struct {
@@ -128,4 +177,6 @@ type versionDetails struct {
 	Repository   any               `json:"repository"`
 	HomePage     string            `json:"homepage"`
 	Dependencies map[string]string `json:"dependencies"`
+	Funding      any               `json:"funding"`
+	Deprecated   any               `json:"deprecated"`
 }
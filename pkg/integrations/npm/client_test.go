@@ -25,6 +25,11 @@ func TestClient_FetchPackage(t *testing.T) {
 		DistTags: distTags{
 			Latest: "4.18.0",
 		},
+		Time: map[string]string{
+			"created":  "2010-12-29T19:38:25.450Z",
+			"4.18.0":   "2022-04-25T14:59:09.720Z",
+			"0.0.1-rc": "2010-12-29T19:38:25.450Z", // unpublished
+		},
 		Versions: map[string]versionDetails{
 			"4.18.0": {
 				Description: "Fast, unopinionated web framework",
@@ -35,6 +40,11 @@ func TestClient_FetchPackage(t *testing.T) {
 					"url":  "git+https://github.com/expressjs/express.git",
 				},
 				HomePage: "https://expressjs.com",
+				Funding: []any{
+					map[string]any{"type": "opencollective", "url": "https://opencollective.com/express"},
+					"https://github.com/sponsors/wesleytodd",
+					map[string]any{"type": "custom", "url": "javascript:alert(document.domain)"},
+				},
 				Dependencies: map[string]string{
 					"body-parser": "1.20.0",
 					"cookie":      "0.5.0",
@@ -75,6 +85,12 @@ func TestClient_FetchPackage(t *testing.T) {
 	if info.Repository != "https://github.com/expressjs/express" {
 		t.Errorf("expected normalized repo URL, got %s", info.Repository)
 	}
+	if len(info.Funding) != 2 || info.Funding[0] != "https://opencollective.com/express" {
+		t.Errorf("unexpected funding %v", info.Funding)
+	}
+	if len(info.Releases) != 1 || info.Releases["4.18.0"] != "2022-04-25" {
+		t.Errorf("unexpected releases %v", info.Releases)
+	}
 }
 
 func TestClient_FetchPackage_NotFound(t *testing.T) {
@@ -155,3 +171,24 @@ func TestExtractString(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractDeprecated(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    any
+		expected string
+	}{
+		{"message", " use request-promise instead ", "use request-promise instead"},
+		{"true", true, "deprecated"},
+		{"false", false, ""},
+		{"nil", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractDeprecated(tt.input); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
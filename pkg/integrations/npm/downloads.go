@@ -0,0 +1,37 @@
+package npm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/matzehuels/stacktower/pkg/integrations"
+)
+
+// FetchDownloads returns the package's downloads over the last 30 days.
+func (c *Client) FetchDownloads(ctx context.Context, pkg string, refresh bool) (int, error) {
+	pkg = normalizeName(pkg)
+	cacheKey := "npm-downloads:" + pkg
+
+	var downloads int
+	err := c.FetchWithCache(ctx, cacheKey, refresh, func(ctx context.Context, v any) error {
+		var data downloadsResponse
+		if err := c.DoRequest(ctx, c.downloadsURL+"/"+pkg, nil, &data); err != nil {
+			if errors.Is(err, integrations.ErrNotFound) {
+				return fmt.Errorf("%w: npm downloads for %s", err, pkg)
+			}
+			return err
+		}
+		*v.(*int) = data.Downloads
+		return nil
+	}, &downloads)
+	if err != nil {
+		return 0, err
+	}
+	return downloads, nil
+}
+
+type downloadsResponse struct {
+	Downloads int    `json:"downloads"`
+	Package   string `json:"package"`
+}
@@ -0,0 +1,33 @@
+package npm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_FetchDownloads(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/@babel/core" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"downloads": 123456, "package": "@babel/core"}`))
+	}))
+	defer server.Close()
+
+	c, err := NewClient(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.downloadsURL = server.URL
+
+	n, err := c.FetchDownloads(context.Background(), "@babel/core", true)
+	if err != nil {
+		t.Fatalf("FetchDownloads failed: %v", err)
+	}
+	if n != 123456 {
+		t.Errorf("expected 123456 downloads, got %d", n)
+	}
+}
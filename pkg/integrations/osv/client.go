@@ -0,0 +1,85 @@
+package osv
+
+import (
+	"context"
+	"time"
+
+	"github.com/matzehuels/stacktower/pkg/integrations"
+)
+
+type Advisory struct {
+	ID       string   `json:"id"`
+	Summary  string   `json:"summary,omitempty"`
+	Severity string   `json:"severity,omitempty"`
+	Fixed    []string `json:"fixed,omitempty"`
+}
+
+type Client struct {
+	integrations.BaseClient
+	baseURL string
+}
+
+func NewClient(cacheTTL time.Duration) (*Client, error) {
+	base, err := integrations.NewBaseClient(cacheTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		BaseClient: base,
+		baseURL:    "https://api.osv.dev/v1",
+	}, nil
+}
+
+func (c *Client) Query(ctx context.Context, ecosystem, name, version string, refresh bool) ([]Advisory, error) {
+	cacheKey := "osv:" + ecosystem + ":" + name + "@" + version
+
+	var advisories []Advisory
+	err := c.FetchWithCache(ctx, cacheKey, refresh, func(ctx context.Context, v any) error {
+		return c.query(ctx, ecosystem, name, version, v.(*[]Advisory))
+	}, &advisories)
+	if err != nil {
+		return nil, err
+	}
+	return advisories, nil
+}
+
+func (c *Client) query(ctx context.Context, ecosystem, name, version string, advisories *[]Advisory) error {
+	req := queryRequest{Version: version}
+	req.Package.Name = name
+	req.Package.Ecosystem = ecosystem
+
+	// OSV pages long advisory lists; keep asking until no token comes back.
+	result := []Advisory{}
+	for {
+		var data queryResponse
+		if err := c.PostJSON(ctx, c.baseURL+"/query", nil, req, &data); err != nil {
+			return err
+		}
+		for _, v := range data.Vulns {
+			if v.Withdrawn != "" {
+				continue
+			}
+			result = append(result, v.advisory(ecosystem, name))
+		}
+		if data.NextPageToken == "" {
+			break
+		}
+		req.PageToken = data.NextPageToken
+	}
+	*advisories = result
+	return nil
+}
+
+type queryRequest struct {
+	Package struct {
+		Name      string `json:"name"`
+		Ecosystem string `json:"ecosystem"`
+	} `json:"package"`
+	Version   string `json:"version"`
+	PageToken string `json:"page_token,omitempty"`
+}
+
+type queryResponse struct {
+	Vulns         []record `json:"vulns"`
+	NextPageToken string   `json:"next_page_token"`
+}
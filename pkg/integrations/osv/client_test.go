@@ -0,0 +1,90 @@
+package osv
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_Query(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/query" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		var req queryRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		if req.Package.Name != "jinja2" || req.Package.Ecosystem != "PyPI" || req.Version != "2.10" {
+			t.Errorf("unexpected query %+v", req)
+		}
+		w.Write([]byte(`{"vulns": [
+			{"id": "GHSA-1", "summary": "sandbox escape", "database_specific": {"severity": "MODERATE"},
+			 "affected": [{"package": {"ecosystem": "PyPI", "name": "jinja2"},
+			   "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "2.10.1"}]}]}]},
+			{"id": "GHSA-2", "withdrawn": "2024-01-01T00:00:00Z"}
+		]}`))
+	}))
+	defer server.Close()
+
+	c, err := NewClient(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.baseURL = server.URL
+
+	advisories, err := c.Query(context.Background(), "PyPI", "jinja2", "2.10", true)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(advisories) != 1 {
+		t.Fatalf("expected 1 advisory, got %d", len(advisories))
+	}
+	a := advisories[0]
+	if a.ID != "GHSA-1" || a.Severity != "MEDIUM" {
+		t.Errorf("unexpected advisory %+v", a)
+	}
+	if len(a.Fixed) != 1 || a.Fixed[0] != "2.10.1" {
+		t.Errorf("expected fixed [2.10.1], got %v", a.Fixed)
+	}
+}
+
+func TestClient_QueryFollowsPages(t *testing.T) {
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req queryRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		pages = append(pages, req.PageToken)
+		switch req.PageToken {
+		case "":
+			w.Write([]byte(`{"vulns": [{"id": "GHSA-1"}], "next_page_token": "p2"}`))
+		case "p2":
+			w.Write([]byte(`{"vulns": [{"id": "GHSA-2"}]}`))
+		default:
+			t.Errorf("unexpected page token %q", req.PageToken)
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewClient(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.baseURL = server.URL
+
+	advisories, err := c.Query(context.Background(), "npm", "lodash", "4.17.0", true)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(advisories) != 2 || advisories[1].ID != "GHSA-2" {
+		t.Errorf("expected advisories from both pages, got %+v", advisories)
+	}
+	if len(pages) != 2 {
+		t.Errorf("expected 2 requests, got %v", pages)
+	}
+}
@@ -0,0 +1,112 @@
+package osv
+
+import (
+	"math"
+	"strings"
+)
+
+var cvss3Weights = map[string]map[string]float64{
+	"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+	"AC": {"L": 0.77, "H": 0.44},
+	"UI": {"N": 0.85, "R": 0.62},
+	"C":  {"H": 0.56, "L": 0.22, "N": 0},
+	"I":  {"H": 0.56, "L": 0.22, "N": 0},
+	"A":  {"H": 0.56, "L": 0.22, "N": 0},
+}
+
+// cvss3Score computes the CVSS v3.x base score from a vector string such as
+// "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H".
+func cvss3Score(vector string) (float64, bool) {
+	if !strings.HasPrefix(vector, "CVSS:3") {
+		return 0, false
+	}
+	metrics := make(map[string]string)
+	for _, part := range strings.Split(vector, "/")[1:] {
+		if k, v, ok := strings.Cut(part, ":"); ok {
+			metrics[k] = v
+		}
+	}
+
+	w := make(map[string]float64, len(cvss3Weights))
+	for k, vals := range cvss3Weights {
+		v, ok := vals[metrics[k]]
+		if !ok {
+			return 0, false
+		}
+		w[k] = v
+	}
+
+	changed := metrics["S"] == "C"
+	var pr float64
+	switch metrics["PR"] {
+	case "N":
+		pr = 0.85
+	case "L":
+		pr = 0.62
+		if changed {
+			pr = 0.68
+		}
+	case "H":
+		pr = 0.27
+		if changed {
+			pr = 0.5
+		}
+	default:
+		return 0, false
+	}
+
+	iss := 1 - (1-w["C"])*(1-w["I"])*(1-w["A"])
+	impact := 6.42 * iss
+	if changed {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	if impact <= 0 {
+		return 0, true
+	}
+
+	exploitability := 8.22 * w["AV"] * w["AC"] * pr * w["UI"]
+	if changed {
+		return roundUp(min(1.08*(impact+exploitability), 10)), true
+	}
+	return roundUp(min(impact+exploitability, 10)), true
+}
+
+func roundUp(x float64) float64 {
+	i := int(math.Round(x * 100000))
+	if i%10000 == 0 {
+		return float64(i) / 100000
+	}
+	return (math.Floor(float64(i)/10000) + 1) / 10
+}
+
+func ratingForScore(score float64) string {
+	switch {
+	case score == 0:
+		return "NONE"
+	case score < 4:
+		return "LOW"
+	case score < 7:
+		return "MEDIUM"
+	case score < 9:
+		return "HIGH"
+	default:
+		return "CRITICAL"
+	}
+}
+
+// SeverityRank orders the severity labels advisories carry, from LOW up to
+// CRITICAL; unknown labels rank lowest.
+func SeverityRank(s string) int {
+	switch s {
+	case "LOW":
+		return 1
+	case "MEDIUM":
+		return 2
+	case "HIGH":
+		return 3
+	case "CRITICAL":
+		return 4
+	default:
+		return 0
+	}
+}
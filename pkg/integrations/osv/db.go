@@ -0,0 +1,120 @@
+package osv
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DB is an in-memory index over an OSV data dump, either a directory of
+// record files or a zip archive such as https://osv-vulnerabilities.storage.googleapis.com/PyPI/all.zip.
+type DB struct {
+	records map[string][]*record
+}
+
+func LoadDB(path string) (*DB, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	db := &DB{records: make(map[string][]*record)}
+	if info.IsDir() {
+		err = db.loadDir(path)
+	} else {
+		err = db.loadZip(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load osv db %s: %w", path, err)
+	}
+	return db, nil
+}
+
+func (db *DB) loadDir(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".json" {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return db.add(path, f)
+	})
+}
+
+func (db *DB) loadZip(path string) error {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	for _, zf := range zr.File {
+		if zf.FileInfo().IsDir() || filepath.Ext(zf.Name) != ".json" {
+			continue
+		}
+		rc, err := zf.Open()
+		if err != nil {
+			return err
+		}
+		err = db.add(zf.Name, rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (db *DB) add(name string, r io.Reader) error {
+	var rec record
+	if err := json.NewDecoder(r).Decode(&rec); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if rec.Withdrawn != "" {
+		return nil
+	}
+	seen := make(map[string]bool)
+	for _, af := range rec.Affected {
+		key := dbKey(af.Package.Ecosystem, af.Package.Name)
+		if !seen[key] {
+			seen[key] = true
+			db.records[key] = append(db.records[key], &rec)
+		}
+	}
+	return nil
+}
+
+func (db *DB) Len() int {
+	ids := make(map[string]struct{})
+	for _, recs := range db.records {
+		for _, r := range recs {
+			ids[r.ID] = struct{}{}
+		}
+	}
+	return len(ids)
+}
+
+func (db *DB) Query(ecosystem, name, version string) []Advisory {
+	var result []Advisory
+	for _, rec := range db.records[dbKey(ecosystem, name)] {
+		for _, af := range rec.Affected {
+			if af.matches(ecosystem, name) && af.affects(version) {
+				result = append(result, rec.advisory(ecosystem, name))
+				break
+			}
+		}
+	}
+	return result
+}
+
+func dbKey(ecosystem, name string) string {
+	return ecosystem + "/" + normalizeName(ecosystem, strings.TrimSpace(name))
+}
@@ -0,0 +1,130 @@
+package osv
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const requestsRecord = `{
+  "id": "GHSA-j8r2-6x86-q33q",
+  "summary": "Unintended leak of Proxy-Authorization header",
+  "severity": [{"type": "CVSS_V3", "score": "CVSS:3.1/AV:N/AC:H/PR:N/UI:R/S:U/C:H/I:N/A:N"}],
+  "affected": [{
+    "package": {"ecosystem": "PyPI", "name": "requests"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "2.3.0"}, {"fixed": "2.31.0"}]}]
+  }]
+}`
+
+const serdeRecord = `{
+  "id": "RUSTSEC-0000-0001",
+  "summary": "listed versions only",
+  "affected": [{
+    "package": {"ecosystem": "crates.io", "name": "serde"},
+    "versions": ["1.0.0"]
+  }]
+}`
+
+func writeDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, data := range map[string]string{"requests.json": requestsRecord, "nested/serde.json": serdeRecord} {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadDB_Dir(t *testing.T) {
+	db, err := LoadDB(writeDir(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if db.Len() != 2 {
+		t.Errorf("expected 2 records, got %d", db.Len())
+	}
+
+	tests := []struct {
+		eco, name, version string
+		want               int
+	}{
+		{"PyPI", "requests", "2.28.1", 1},
+		{"PyPI", "Requests", "2.3.0", 1},
+		{"PyPI", "requests", "2.31.0", 0},
+		{"PyPI", "requests", "2.2.1", 0},
+		{"crates.io", "serde", "1.0.0", 1},
+		{"crates.io", "serde", "1.0.1", 0},
+		{"npm", "requests", "2.28.1", 0},
+	}
+	for _, tt := range tests {
+		if got := db.Query(tt.eco, tt.name, tt.version); len(got) != tt.want {
+			t.Errorf("Query(%s, %s, %s) = %d advisories, want %d", tt.eco, tt.name, tt.version, len(got), tt.want)
+		}
+	}
+
+	a := db.Query("PyPI", "requests", "2.28.1")[0]
+	if a.Severity != "MEDIUM" {
+		t.Errorf("expected CVSS-derived severity MEDIUM, got %q", a.Severity)
+	}
+}
+
+func TestLoadDB_Zip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "all.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	w, _ := zw.Create("GHSA-j8r2-6x86-q33q.json")
+	w.Write([]byte(requestsRecord))
+	zw.Close()
+	f.Close()
+
+	db, err := LoadDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := db.Query("PyPI", "requests", "2.30.0"); len(got) != 1 {
+		t.Errorf("expected 1 advisory, got %d", len(got))
+	}
+}
+
+func TestLoadDB_Missing(t *testing.T) {
+	if _, err := LoadDB(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected error for missing path")
+	}
+}
+
+func TestRange_LastAffected(t *testing.T) {
+	rg := affectedRange{Type: "SEMVER", Events: []event{{Introduced: "1.0.0"}, {LastAffected: "1.2.0"}}}
+	for v, want := range map[string]bool{"0.9.0": false, "1.0.0": true, "1.2.0": true, "1.2.1": false} {
+		if got := rg.affects(v); got != want {
+			t.Errorf("affects(%s) = %v, want %v", v, got, want)
+		}
+	}
+}
+
+func TestCVSS3Score(t *testing.T) {
+	tests := []struct {
+		vector string
+		want   float64
+	}{
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", 9.8},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N", 6.1},
+		{"CVSS:3.1/AV:N/AC:H/PR:N/UI:R/S:U/C:H/I:N/A:N", 5.3},
+		{"CVSS:3.0/AV:L/AC:L/PR:L/UI:N/S:U/C:N/I:N/A:N", 0},
+	}
+	for _, tt := range tests {
+		got, ok := cvss3Score(tt.vector)
+		if !ok || got != tt.want {
+			t.Errorf("cvss3Score(%s) = %v, %v; want %v", tt.vector, got, ok, tt.want)
+		}
+	}
+	if _, ok := cvss3Score("CVSS:4.0/AV:N"); ok {
+		t.Error("expected v4 vector to be rejected")
+	}
+}
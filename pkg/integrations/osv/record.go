@@ -0,0 +1,152 @@
+package osv
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/matzehuels/stacktower/pkg/integrations"
+)
+
+type record struct {
+	ID               string         `json:"id"`
+	Summary          string         `json:"summary"`
+	Withdrawn        string         `json:"withdrawn"`
+	Severity         []severity     `json:"severity"`
+	Affected         []affected     `json:"affected"`
+	DatabaseSpecific map[string]any `json:"database_specific"`
+}
+
+type severity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+type affected struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+	Ranges           []affectedRange `json:"ranges"`
+	Versions         []string        `json:"versions"`
+	DatabaseSpecific map[string]any  `json:"database_specific"`
+}
+
+type affectedRange struct {
+	Type   string  `json:"type"`
+	Events []event `json:"events"`
+}
+
+type event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+}
+
+func (r *record) advisory(ecosystem, name string) Advisory {
+	a := Advisory{ID: r.ID, Summary: r.Summary, Severity: r.severity()}
+	for _, af := range r.Affected {
+		if !af.matches(ecosystem, name) {
+			continue
+		}
+		for _, rg := range af.Ranges {
+			for _, e := range rg.Events {
+				if e.Fixed != "" && rg.Type != "GIT" && !slices.Contains(a.Fixed, e.Fixed) {
+					a.Fixed = append(a.Fixed, e.Fixed)
+				}
+			}
+		}
+	}
+	return a
+}
+
+// severity prefers the database's own rating (as GitHub advisories carry)
+// and falls back to scoring the CVSS v3 vector.
+func (r *record) severity() string {
+	if s := databaseSeverity(r.DatabaseSpecific); s != "" {
+		return s
+	}
+	for _, af := range r.Affected {
+		if s := databaseSeverity(af.DatabaseSpecific); s != "" {
+			return s
+		}
+	}
+	for _, s := range r.Severity {
+		if s.Type == "CVSS_V3" {
+			if score, ok := cvss3Score(s.Score); ok {
+				return ratingForScore(score)
+			}
+		}
+	}
+	return ""
+}
+
+func databaseSeverity(m map[string]any) string {
+	s, _ := m["severity"].(string)
+	switch s = strings.ToUpper(s); s {
+	case "MODERATE":
+		return "MEDIUM"
+	case "LOW", "MEDIUM", "HIGH", "CRITICAL":
+		return s
+	}
+	return ""
+}
+
+func (af *affected) matches(ecosystem, name string) bool {
+	return af.Package.Ecosystem == ecosystem &&
+		normalizeName(ecosystem, af.Package.Name) == normalizeName(ecosystem, name)
+}
+
+// affects reports whether version falls inside any enumerated version or
+// SEMVER/ECOSYSTEM range of this entry. GIT ranges are commit-based and ignored.
+func (af *affected) affects(version string) bool {
+	if slices.Contains(af.Versions, version) {
+		return true
+	}
+	for _, rg := range af.Ranges {
+		if rg.Type != "SEMVER" && rg.Type != "ECOSYSTEM" {
+			continue
+		}
+		if rg.affects(version) {
+			return true
+		}
+	}
+	return false
+}
+
+func (rg affectedRange) affects(version string) bool {
+	events := slices.Clone(rg.Events)
+	slices.SortStableFunc(events, func(a, b event) int {
+		return integrations.CompareVersions(a.version(), b.version())
+	})
+
+	hit := false
+	for _, e := range events {
+		switch {
+		case e.Introduced != "":
+			if integrations.CompareVersions(version, e.Introduced) >= 0 {
+				hit = true
+			}
+		case e.Fixed != "":
+			if integrations.CompareVersions(version, e.Fixed) >= 0 {
+				hit = false
+			}
+		case e.LastAffected != "":
+			if integrations.CompareVersions(version, e.LastAffected) > 0 {
+				hit = false
+			}
+		}
+	}
+	return hit
+}
+
+func (e event) version() string {
+	return e.Introduced + e.Fixed + e.LastAffected
+}
+
+func normalizeName(ecosystem, name string) string {
+	name = strings.ToLower(name)
+	if ecosystem == "PyPI" {
+		name = strings.NewReplacer("_", "-", ".", "-").Replace(name)
+	}
+	return name
+}
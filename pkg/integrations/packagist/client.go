@@ -22,6 +22,9 @@ type PackageInfo struct {
 	Description  string
 	License      string
 	Author       string
+	Abandoned    bool
+	ReplacedBy   string            // suggested replacement package, if any
+	Releases     map[string]string // version to release day
 }
 
 type Client struct {
@@ -30,17 +33,14 @@ type Client struct {
 }
 
 func NewClient(cacheTTL time.Duration) (*Client, error) {
-	cache, err := integrations.NewCache(cacheTTL)
+	base, err := integrations.NewBaseClient(cacheTTL)
 	if err != nil {
 		return nil, err
 	}
 
 	return &Client{
-		BaseClient: integrations.BaseClient{
-			HTTP:  integrations.NewHTTPClient(),
-			Cache: cache,
-		},
-		baseURL: "https://repo.packagist.org",
+		BaseClient: base,
+		baseURL:    "https://repo.packagist.org",
 	}, nil
 }
 
@@ -49,8 +49,8 @@ func (c *Client) FetchPackage(ctx context.Context, pkg string, refresh bool) (*P
 	cacheKey := "packagist:" + pkg
 
 	var info PackageInfo
-	err := c.FetchWithCache(ctx, cacheKey, refresh, func() error {
-		return c.fetchPackage(ctx, pkg, &info)
+	err := c.FetchWithCache(ctx, cacheKey, refresh, func(ctx context.Context, v any) error {
+		return c.fetchPackage(ctx, pkg, v.(*PackageInfo))
 	}, &info)
 	if err != nil {
 		return nil, err
@@ -78,10 +78,8 @@ func (c *Client) fetchPackage(ctx context.Context, pkg string, info *PackageInfo
 	v := chooseLatestStable(versions)
 	deps := filterComposerDeps(v.Require)
 
-	license := ""
-	if len(v.License) > 0 {
-		license = v.License[0]
-	}
+	// Composer treats multiple licenses as a choice between them.
+	license := strings.Join(v.License, " OR ")
 
 	author := ""
 	if len(v.Authors) > 0 {
@@ -97,11 +95,29 @@ func (c *Client) fetchPackage(ctx context.Context, pkg string, info *PackageInfo
 		Repository:   normalizeRepoURL(v.Source.URL),
 		HomePage:     v.Homepage,
 		Dependencies: slices.Collect(maps.Keys(deps)),
+		Abandoned:    v.Abandoned,
+		ReplacedBy:   v.Replacement,
+		Releases:     releaseDays(versions),
 	}
 
 	return nil
 }
 
+// releaseDays dates every tagged release; development branches have no
+// release and are skipped.
+func releaseDays(versions []p2Version) map[string]string {
+	days := make(map[string]string, len(versions))
+	for _, v := range versions {
+		if strings.Contains(strings.ToLower(v.Version), "dev") {
+			continue
+		}
+		if day := integrations.ReleaseDay(v.Time); day != "" {
+			days[v.Version] = day
+		}
+	}
+	return days
+}
+
 func filterComposerDeps(require map[string]string) map[string]string {
 	if require == nil {
 		return map[string]string{}
@@ -179,6 +195,9 @@ type p2Version struct {
 	Authors []struct {
 		Name string `json:"name"`
 	} `json:"authors"`
+	Time        string `json:"time"`
+	Abandoned   bool   `json:"-"`
+	Replacement string `json:"-"`
 }
 
 func (v *p2Version) UnmarshalJSON(b []byte) error {
@@ -199,6 +218,8 @@ func (v *p2Version) UnmarshalJSON(b []byte) error {
 		Authors []struct {
 			Name string `json:"name"`
 		} `json:"authors"`
+		Time      string          `json:"time"`
+		Abandoned json.RawMessage `json:"abandoned"`
 	}
 
 	var rv rawVersion
@@ -231,6 +252,18 @@ func (v *p2Version) UnmarshalJSON(b []byte) error {
 		}
 	}
 
+	// abandoned is either true or the name of the suggested replacement.
+	var abandoned bool
+	var replacement string
+	if len(rv.Abandoned) > 0 {
+		if err := json.Unmarshal(rv.Abandoned, &abandoned); err != nil {
+			if err := json.Unmarshal(rv.Abandoned, &replacement); err == nil {
+				replacement = strings.TrimSpace(replacement)
+				abandoned = true
+			}
+		}
+	}
+
 	v.Name = rv.Name
 	v.Version = rv.Version
 	v.Description = rv.Description
@@ -241,6 +274,9 @@ func (v *p2Version) UnmarshalJSON(b []byte) error {
 	v.Source = rv.Source
 	v.Dist = rv.Dist
 	v.Authors = rv.Authors
+	v.Time = rv.Time
+	v.Abandoned = abandoned
+	v.Replacement = replacement
 
 	return nil
 }
@@ -42,6 +42,7 @@ func TestFetchPackage_Success(t *testing.T) {
 		Authors: []struct {
 			Name string `json:"name"`
 		}{{Name: "  Jane Doe  "}},
+		Time: "2024-03-01T10:00:00+00:00",
 	}
 	vDev := p2Version{Name: "vendor/package", Version: "1.3.0-dev"}
 	payload := p2Response{Packages: map[string][]p2Version{
@@ -91,6 +92,9 @@ func TestFetchPackage_Success(t *testing.T) {
 	if len(info.Dependencies) != 1 || info.Dependencies[0] != "vendor/dep" {
 		t.Errorf("unexpected dependencies: %#v", info.Dependencies)
 	}
+	if len(info.Releases) != 1 || info.Releases["1.2.3"] != "2024-03-01" {
+		t.Errorf("expected only the tagged release to be dated, got %v", info.Releases)
+	}
 }
 
 func TestFetchPackage_NotFound(t *testing.T) {
@@ -208,3 +212,25 @@ func TestP2Version_UnmarshalJSON(t *testing.T) {
 		t.Errorf("unexpected require: %#v", v.Require)
 	}
 }
+
+func TestP2Version_UnmarshalJSON_Abandoned(t *testing.T) {
+	tests := []struct {
+		raw         string
+		abandoned   bool
+		replacement string
+	}{
+		{`{"name": "a/b", "abandoned": true}`, true, ""},
+		{`{"name": "a/b", "abandoned": "c/d"}`, true, "c/d"},
+		{`{"name": "a/b", "abandoned": false}`, false, ""},
+		{`{"name": "a/b"}`, false, ""},
+	}
+	for _, tt := range tests {
+		var v p2Version
+		if err := json.Unmarshal([]byte(tt.raw), &v); err != nil {
+			t.Fatalf("unmarshal %s: %v", tt.raw, err)
+		}
+		if v.Abandoned != tt.abandoned || v.Replacement != tt.replacement {
+			t.Errorf("%s: got abandoned=%v replacement=%q", tt.raw, v.Abandoned, v.Replacement)
+		}
+	}
+}
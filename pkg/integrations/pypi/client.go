@@ -26,24 +26,26 @@ type PackageInfo struct {
 	Summary      string
 	License      string
 	Author       string
+	Yanked       bool
+	YankedReason string
+	Releases     map[string]string // version to release day
 }
 
 type Client struct {
 	integrations.BaseClient
-	baseURL string
+	baseURL  string
+	statsURL string
 }
 
 func NewClient(cacheTTL time.Duration) (*Client, error) {
-	cache, err := integrations.NewCache(cacheTTL)
+	base, err := integrations.NewBaseClient(cacheTTL)
 	if err != nil {
 		return nil, err
 	}
 	return &Client{
-		BaseClient: integrations.BaseClient{
-			HTTP:  integrations.NewHTTPClient(),
-			Cache: cache,
-		},
-		baseURL: "https://pypi.org/pypi",
+		BaseClient: base,
+		baseURL:    "https://pypi.org/pypi",
+		statsURL:   "https://pypistats.org/api/packages",
 	}, nil
 }
 
@@ -52,8 +54,8 @@ func (c *Client) FetchPackage(ctx context.Context, pkg string, refresh bool) (*P
 	cacheKey := "pypi:" + pkg
 
 	var info PackageInfo
-	err := c.FetchWithCache(ctx, cacheKey, refresh, func() error {
-		return c.fetchPackage(ctx, pkg, &info)
+	err := c.FetchWithCache(ctx, cacheKey, refresh, func(ctx context.Context, v any) error {
+		return c.fetchPackage(ctx, pkg, v.(*PackageInfo))
 	}, &info)
 	if err != nil {
 		return nil, err
@@ -88,10 +90,28 @@ func (c *Client) fetchPackage(ctx context.Context, pkg string, info *PackageInfo
 		ProjectURLs:  urls,
 		HomePage:     data.Info.HomePage,
 		Author:       data.Info.Author,
+		Yanked:       data.Info.Yanked,
+		YankedReason: strings.TrimSpace(data.Info.YankedReason),
+		Releases:     releaseDays(data.Releases),
 	}
 	return nil
 }
 
+// releaseDays dates each release by its first upload, skipping releases
+// without files or whose files were all yanked.
+func releaseDays(releases map[string][]releaseFile) map[string]string {
+	days := make(map[string]string, len(releases))
+	for v, files := range releases {
+		for _, f := range files {
+			day := integrations.ReleaseDay(f.UploadTime)
+			if day != "" && !f.Yanked && (days[v] == "" || day < days[v]) {
+				days[v] = day
+			}
+		}
+	}
+	return days
+}
+
 func extractDeps(requiresDist []string) []string {
 	seen := make(map[string]bool)
 	var deps []string
@@ -116,7 +136,13 @@ func normalizeName(name string) string {
 }
 
 type apiResponse struct {
-	Info apiInfo `json:"info"`
+	Info     apiInfo                  `json:"info"`
+	Releases map[string][]releaseFile `json:"releases"`
+}
+
+type releaseFile struct {
+	UploadTime string `json:"upload_time_iso_8601"`
+	Yanked     bool   `json:"yanked"`
 }
 
 type apiInfo struct {
@@ -128,4 +154,6 @@ type apiInfo struct {
 	ProjectURLs  map[string]any `json:"project_urls"`
 	HomePage     string         `json:"home_page"`
 	Author       string         `json:"author"`
+	Yanked       bool           `json:"yanked"`
+	YankedReason string         `json:"yanked_reason"`
 }
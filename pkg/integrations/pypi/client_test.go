@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/matzehuels/stacktower/pkg/httputil"
 	"github.com/matzehuels/stacktower/pkg/integrations"
 )
 
@@ -55,6 +56,37 @@ func TestClient_FetchPackage(t *testing.T) {
 	}
 }
 
+func TestClient_FetchPackage_Yanked(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(apiResponse{Info: apiInfo{
+			Name:         "oldpkg",
+			Version:      "1.0.1",
+			Yanked:       true,
+			YankedReason: "broken wheel ",
+		}, Releases: map[string][]releaseFile{
+			"1.0.0": {{UploadTime: "2023-02-01T10:00:00.000000Z"}, {UploadTime: "2023-01-31T09:00:00.000000Z"}},
+			"1.0.1": {{UploadTime: "2023-03-01T10:00:00.000000Z", Yanked: true}},
+			"0.9.0": {},
+		}})
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(time.Hour)
+	c.Cache, _ = httputil.NewCache(t.TempDir(), time.Hour)
+	c.baseURL = server.URL
+
+	info, err := c.FetchPackage(context.Background(), "oldpkg", false)
+	if err != nil {
+		t.Fatalf("FetchPackage failed: %v", err)
+	}
+	if !info.Yanked || info.YankedReason != "broken wheel" {
+		t.Errorf("expected yanked with reason, got %v %q", info.Yanked, info.YankedReason)
+	}
+	if len(info.Releases) != 1 || info.Releases["1.0.0"] != "2023-01-31" {
+		t.Errorf("expected releases dated by first unyanked upload, got %v", info.Releases)
+	}
+}
+
 func TestClient_FetchPackage_NotFound(t *testing.T) {
 	server := httptest.NewServer(http.NotFoundHandler())
 	defer server.Close()
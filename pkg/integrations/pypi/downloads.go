@@ -0,0 +1,161 @@
+package pypi
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/matzehuels/stacktower/pkg/integrations"
+)
+
+// FetchDownloads returns the package's downloads over the last month as
+// reported by pypistats.org.
+func (c *Client) FetchDownloads(ctx context.Context, pkg string, refresh bool) (int, error) {
+	pkg = normalizeName(pkg)
+	cacheKey := "pypistats:" + pkg
+
+	var downloads int
+	err := c.FetchWithCache(ctx, cacheKey, refresh, func(ctx context.Context, v any) error {
+		var data statsResponse
+		if err := c.DoRequest(ctx, fmt.Sprintf("%s/%s/recent", c.statsURL, pkg), nil, &data); err != nil {
+			if errors.Is(err, integrations.ErrNotFound) {
+				return fmt.Errorf("%w: pypistats for %s", err, pkg)
+			}
+			return err
+		}
+		*v.(*int) = data.Data.LastMonth
+		return nil
+	}, &downloads)
+	if err != nil {
+		return 0, err
+	}
+	return downloads, nil
+}
+
+type statsResponse struct {
+	Data struct {
+		LastMonth int `json:"last_month"`
+	} `json:"data"`
+}
+
+var (
+	exportNameColumns  = []string{"project", "package", "name", "file_project"}
+	exportCountColumns = []string{"num_downloads", "downloads", "download_count", "count"}
+)
+
+type DownloadCounts map[string]int
+
+func (c DownloadCounts) Get(pkg string) (int, bool) {
+	n, ok := c[normalizeName(pkg)]
+	return n, ok
+}
+
+// LoadDownloadCounts reads per-project download counts exported from the
+// public PyPI BigQuery dataset, as CSV with a header row or as
+// newline-delimited JSON. Names are normalized.
+func LoadDownloadCounts(path string) (DownloadCounts, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var counts DownloadCounts
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".jsonl", ".ndjson":
+		counts, err = readNDJSONCounts(f)
+	default:
+		counts, err = readCSVCounts(f)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return counts, nil
+}
+
+func readCSVCounts(r io.Reader) (DownloadCounts, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	nameCol, countCol := findColumn(header, exportNameColumns), findColumn(header, exportCountColumns)
+	if nameCol < 0 || countCol < 0 {
+		return nil, fmt.Errorf("header needs a project and a download count column, got %v", header)
+	}
+
+	counts := make(DownloadCounts)
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return counts, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(rec[countCol]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", len(counts)+2, err)
+		}
+		counts[normalizeName(rec[nameCol])] += n
+	}
+}
+
+func readNDJSONCounts(r io.Reader) (DownloadCounts, error) {
+	counts := make(DownloadCounts)
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		if strings.TrimSpace(sc.Text()) == "" {
+			continue
+		}
+		var row map[string]any
+		if err := json.Unmarshal(sc.Bytes(), &row); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		name, n, ok := exportRow(row)
+		if !ok {
+			return nil, fmt.Errorf("line %d: needs a project and a download count field", line)
+		}
+		counts[normalizeName(name)] += n
+	}
+	return counts, sc.Err()
+}
+
+func exportRow(row map[string]any) (string, int, bool) {
+	var name string
+	for _, k := range exportNameColumns {
+		if s, ok := row[k].(string); ok {
+			name = s
+			break
+		}
+	}
+	for _, k := range exportCountColumns {
+		switch v := row[k].(type) {
+		case float64:
+			return name, int(v), name != ""
+		case string: // BigQuery exports INT64 as strings
+			if n, err := strconv.Atoi(v); err == nil {
+				return name, n, name != ""
+			}
+		}
+	}
+	return "", 0, false
+}
+
+func findColumn(header, names []string) int {
+	for i, h := range header {
+		if slices.Contains(names, strings.ToLower(strings.TrimSpace(h))) {
+			return i
+		}
+	}
+	return -1
+}
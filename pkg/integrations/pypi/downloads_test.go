@@ -0,0 +1,66 @@
+package pypi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestClient_FetchDownloads(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/typing-extensions/recent" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"data": {"last_day": 10, "last_week": 70, "last_month": 300}, "package": "typing-extensions"}`))
+	}))
+	defer server.Close()
+
+	c, err := NewClient(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.statsURL = server.URL
+
+	n, err := c.FetchDownloads(context.Background(), "typing_extensions", true)
+	if err != nil {
+		t.Fatalf("FetchDownloads failed: %v", err)
+	}
+	if n != 300 {
+		t.Errorf("expected 300 downloads, got %d", n)
+	}
+}
+
+func TestLoadDownloadCounts(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"export.csv":    "project,num_downloads\nRequests,1000\ntyping_extensions,500\nrequests,20\n",
+		"export.ndjson": `{"project":"Requests","num_downloads":"1020"}` + "\n" + `{"project":"typing-extensions","num_downloads":500}` + "\n",
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		counts, err := LoadDownloadCounts(path)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if n, ok := counts.Get("requests"); !ok || n != 1020 {
+			t.Errorf("%s: expected requests=1020, got %d (%v)", name, n, ok)
+		}
+		if n, _ := counts.Get("Typing_Extensions"); n != 500 {
+			t.Errorf("%s: expected typing-extensions=500, got %d", name, n)
+		}
+	}
+
+	bad := filepath.Join(dir, "bad.csv")
+	os.WriteFile(bad, []byte("foo,bar\n1,2\n"), 0o644)
+	if _, err := LoadDownloadCounts(bad); err == nil {
+		t.Error("expected error for missing columns")
+	}
+}
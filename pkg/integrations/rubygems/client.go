@@ -20,6 +20,7 @@ type GemInfo struct {
 	License       string
 	Downloads     int
 	Authors       string
+	Releases      map[string]string // version to release day, filled in by FetchReleases
 }
 
 type Client struct {
@@ -28,16 +29,13 @@ type Client struct {
 }
 
 func NewClient(cacheTTL time.Duration) (*Client, error) {
-	cache, err := integrations.NewCache(cacheTTL)
+	base, err := integrations.NewBaseClient(cacheTTL)
 	if err != nil {
 		return nil, err
 	}
 	return &Client{
-		BaseClient: integrations.BaseClient{
-			HTTP:  integrations.NewHTTPClient(),
-			Cache: cache,
-		},
-		baseURL: "https://rubygems.org/api/v1",
+		BaseClient: base,
+		baseURL:    "https://rubygems.org/api/v1",
 	}, nil
 }
 
@@ -46,8 +44,8 @@ func (c *Client) FetchGem(ctx context.Context, gem string, refresh bool) (*GemIn
 	cacheKey := "rubygems:" + gem
 
 	var info GemInfo
-	err := c.FetchWithCache(ctx, cacheKey, refresh, func() error {
-		return c.fetchGem(ctx, gem, &info)
+	err := c.FetchWithCache(ctx, cacheKey, refresh, func(ctx context.Context, v any) error {
+		return c.fetchGem(ctx, gem, v.(*GemInfo))
 	}, &info)
 	if err != nil {
 		return nil, err
@@ -80,6 +78,34 @@ func (c *Client) fetchGem(ctx context.Context, gem string, info *GemInfo) error
 	return nil
 }
 
+// FetchReleases reads the gem's version history, version to release day.
+// Unlike the other registries, RubyGems serves it apart from the gem, so it
+// costs a request of its own.
+func (c *Client) FetchReleases(ctx context.Context, gem string, refresh bool) (map[string]string, error) {
+	gem = normalizeName(gem)
+	cacheKey := "rubygems:versions:" + gem
+
+	var days map[string]string
+	err := c.FetchWithCache(ctx, cacheKey, refresh, func(ctx context.Context, v any) error {
+		var versions []versionResponse
+		if err := c.DoRequest(ctx, fmt.Sprintf("%s/versions/%s.json", c.baseURL, gem), nil, &versions); err != nil {
+			return err
+		}
+		released := make(map[string]string, len(versions))
+		for _, ver := range versions {
+			if day := integrations.ReleaseDay(ver.CreatedAt); day != "" {
+				released[ver.Number] = day
+			}
+		}
+		*v.(*map[string]string) = released
+		return nil
+	}, &days)
+	if err != nil {
+		return nil, err
+	}
+	return days, nil
+}
+
 func extractDeps(deps dependenciesResponse) []string {
 	seen := make(map[string]bool)
 	var result []string
@@ -127,3 +153,8 @@ type dependencyInfo struct {
 	Name         string `json:"name"`
 	Requirements string `json:"requirements"`
 }
+
+type versionResponse struct {
+	Number    string `json:"number"`
+	CreatedAt string `json:"created_at"`
+}
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/matzehuels/stacktower/pkg/httputil"
 	"github.com/matzehuels/stacktower/pkg/integrations"
 )
 
@@ -35,6 +36,11 @@ func TestClient_FetchGem(t *testing.T) {
 				},
 			}
 			json.NewEncoder(w).Encode(resp)
+		} else if r.URL.Path == "/api/v1/versions/rails.json" {
+			json.NewEncoder(w).Encode([]versionResponse{
+				{Number: "7.1.0", CreatedAt: "2023-10-05T00:00:00.000Z"},
+				{Number: "7.0.8", CreatedAt: "2023-09-09T00:00:00.000Z"},
+			})
 		} else {
 			http.NotFound(w, r)
 		}
@@ -43,6 +49,7 @@ func TestClient_FetchGem(t *testing.T) {
 
 	c, _ := NewClient(time.Hour)
 	c.HTTP = server.Client()
+	c.Cache, _ = httputil.NewCache(t.TempDir(), time.Hour)
 	c.baseURL = server.URL + "/api/v1"
 
 	info, err := c.FetchGem(context.Background(), "rails", false)
@@ -62,6 +69,17 @@ func TestClient_FetchGem(t *testing.T) {
 	if info.License != "MIT" {
 		t.Errorf("expected license MIT, got %s", info.License)
 	}
+	if info.Releases != nil {
+		t.Errorf("FetchGem should not fetch the release history, got %v", info.Releases)
+	}
+
+	releases, err := c.FetchReleases(context.Background(), "rails", false)
+	if err != nil {
+		t.Fatalf("FetchReleases failed: %v", err)
+	}
+	if len(releases) != 2 || releases["7.1.0"] != "2023-10-05" {
+		t.Errorf("unexpected releases %v", releases)
+	}
 }
 
 func TestClient_FetchGem_NotFound(t *testing.T) {
@@ -0,0 +1,83 @@
+package scorecard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/matzehuels/stacktower/pkg/integrations"
+)
+
+// Result is an OpenSSF Scorecard run as served by the API and written by
+// `scorecard --format json`. Scores run from 0 to 10; -1 marks a check
+// that could not be evaluated.
+type Result struct {
+	Date   string  `json:"date"`
+	Repo   Repo    `json:"repo"`
+	Score  float64 `json:"score"`
+	Checks []Check `json:"checks"`
+}
+
+type Repo struct {
+	Name   string `json:"name"`
+	Commit string `json:"commit,omitempty"`
+}
+
+type Check struct {
+	Name   string `json:"name"`
+	Score  int    `json:"score"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type Client struct {
+	integrations.BaseClient
+	baseURL string
+}
+
+func NewClient(cacheTTL time.Duration) (*Client, error) {
+	base, err := integrations.NewBaseClient(cacheTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		BaseClient: base,
+		baseURL:    "https://api.securityscorecards.dev",
+	}, nil
+}
+
+// Fetch returns the latest published result for a repository named as
+// Scorecard does, e.g. "github.com/owner/repo". It returns nil without
+// error for repositories Scorecard has not scanned.
+func (c *Client) Fetch(ctx context.Context, repo string, refresh bool) (*Result, error) {
+	repo = NormalizeRepo(repo)
+	cacheKey := "scorecard:" + repo
+
+	var r Result
+	err := c.FetchWithCache(ctx, cacheKey, refresh, func(ctx context.Context, v any) error {
+		err := c.DoRequest(ctx, fmt.Sprintf("%s/projects/%s", c.baseURL, repo), nil, v)
+		if errors.Is(err, integrations.ErrNotFound) {
+			*v.(*Result) = Result{}
+			return nil
+		}
+		return err
+	}, &r)
+	if err != nil {
+		return nil, err
+	}
+	if r.Repo.Name == "" {
+		return nil, nil
+	}
+	return &r, nil
+}
+
+// NormalizeRepo reduces a repository URL to Scorecard's "host/owner/repo"
+// form.
+func NormalizeRepo(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.TrimPrefix(s, "https://")
+	s = strings.TrimPrefix(s, "http://")
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "/"), ".git")
+	return s
+}
@@ -0,0 +1,65 @@
+package scorecard
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/matzehuels/stacktower/pkg/httputil"
+)
+
+func TestClient_Fetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/projects/github.com/pallets/flask":
+			w.Write([]byte(`{
+				"date": "2024-05-06",
+				"repo": {"name": "github.com/pallets/flask", "commit": "abc"},
+				"score": 6.4,
+				"checks": [
+					{"name": "Code-Review", "score": 10, "reason": "all changesets reviewed"},
+					{"name": "Fuzzing", "score": 0, "reason": "project is not fuzzed"},
+					{"name": "Packaging", "score": -1, "reason": "packaging workflow not detected"}
+				]
+			}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewClient(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.HTTP = server.Client()
+	c.Cache, _ = httputil.NewCache(t.TempDir(), time.Hour)
+	c.baseURL = server.URL
+
+	r, err := c.Fetch(context.Background(), "https://github.com/Pallets/flask.git", false)
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if r == nil || r.Score != 6.4 || len(r.Checks) != 3 || r.Checks[1].Name != "Fuzzing" {
+		t.Errorf("unexpected result %+v", r)
+	}
+
+	r, err = c.Fetch(context.Background(), "github.com/unknown/repo", false)
+	if err != nil || r != nil {
+		t.Errorf("expected no result for unscanned repo, got %+v, %v", r, err)
+	}
+}
+
+func TestNormalizeRepo(t *testing.T) {
+	for in, want := range map[string]string{
+		"https://github.com/Owner/Repo.git": "github.com/owner/repo",
+		"http://gitlab.com/group/proj/":     "gitlab.com/group/proj",
+		"github.com/a/b":                    "github.com/a/b",
+	} {
+		if got := NormalizeRepo(in); got != want {
+			t.Errorf("NormalizeRepo(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
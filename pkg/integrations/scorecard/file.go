@@ -0,0 +1,50 @@
+package scorecard
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Results indexes locally stored Scorecard runs by repository.
+type Results map[string]*Result
+
+// LoadResults reads results written by `scorecard --format json`: a single
+// object, an array of them, or one object per line as in the public
+// BigQuery export. Later entries for the same repository win.
+func LoadResults(path string) (Results, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []*Result
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(trimmed, &list)
+	} else {
+		dec := json.NewDecoder(bytes.NewReader(trimmed))
+		for dec.More() {
+			var r Result
+			if err = dec.Decode(&r); err != nil {
+				break
+			}
+			list = append(list, &r)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scorecard results %s: %w", path, err)
+	}
+
+	results := make(Results, len(list))
+	for _, r := range list {
+		if r.Repo.Name != "" {
+			results[NormalizeRepo(r.Repo.Name)] = r
+		}
+	}
+	return results, nil
+}
+
+func (rs Results) Get(repo string) *Result {
+	return rs[NormalizeRepo(repo)]
+}
@@ -0,0 +1,45 @@
+package scorecard
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadResults(t *testing.T) {
+	dir := t.TempDir()
+	single := `{"repo": {"name": "github.com/a/one"}, "score": 3.5, "checks": []}`
+	lines := single + "\n" + `{"repo": {"name": "github.com/b/two"}, "score": 8}` + "\n"
+	array := `[` + single + `, {"repo": {"name": "github.com/A/One"}, "score": 4}]`
+
+	tests := []struct {
+		name, body string
+		repos      int
+		oneScore   float64
+	}{
+		{"single", single, 1, 3.5},
+		{"ndjson", lines, 2, 3.5},
+		{"array, later wins", array, 1, 4},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.name+".json")
+		os.WriteFile(path, []byte(tt.body), 0o644)
+
+		rs, err := LoadResults(path)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(rs) != tt.repos {
+			t.Errorf("%s: got %d repos, want %d", tt.name, len(rs), tt.repos)
+		}
+		if r := rs.Get("https://github.com/a/one"); r == nil || r.Score != tt.oneScore {
+			t.Errorf("%s: unexpected result %+v", tt.name, r)
+		}
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	os.WriteFile(bad, []byte(`{"repo": `), 0o644)
+	if _, err := LoadResults(bad); err == nil {
+		t.Error("expected error for truncated file")
+	}
+}
@@ -0,0 +1,182 @@
+package integrations
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/matzehuels/stacktower/pkg/httputil"
+)
+
+const (
+	TimeoutEnv    = "STACKTOWER_HTTP_TIMEOUT"
+	UserAgentEnv  = "STACKTOWER_USER_AGENT"
+	ProxyEnv      = "STACKTOWER_PROXY"
+	CAFileEnv     = "STACKTOWER_CA_FILE"
+	ClientCertEnv = "STACKTOWER_CLIENT_CERT"
+	ClientKeyEnv  = "STACKTOWER_CLIENT_KEY"
+
+	RetryAttemptsEnv = "STACKTOWER_HTTP_RETRIES"
+	RetryDelayEnv    = "STACKTOWER_HTTP_RETRY_DELAY"
+	RetryMaxDelayEnv = "STACKTOWER_HTTP_RETRY_MAX_DELAY"
+
+	DefaultHTTPTimeout = 10 * time.Second
+	DefaultUserAgent   = "stacktower (+https://github.com/matzehuels/stacktower)"
+)
+
+// HTTPConfig is applied to every registry and metadata client. Proxy
+// overrides HTTPS_PROXY/HTTP_PROXY/NO_PROXY, which are honored otherwise;
+// CAFile is added to the system roots rather than replacing them.
+type HTTPConfig struct {
+	Timeout    time.Duration
+	UserAgent  string
+	Proxy      string
+	CAFile     string
+	ClientCert string
+	ClientKey  string
+	Retry      httputil.RetryPolicy
+}
+
+func HTTPConfigFromEnv() (HTTPConfig, error) {
+	cfg := HTTPConfig{
+		Timeout:    DefaultHTTPTimeout,
+		UserAgent:  os.Getenv(UserAgentEnv),
+		Proxy:      os.Getenv(ProxyEnv),
+		CAFile:     os.Getenv(CAFileEnv),
+		ClientCert: os.Getenv(ClientCertEnv),
+		ClientKey:  os.Getenv(ClientKeyEnv),
+		Retry:      httputil.DefaultRetryPolicy,
+	}
+	for env, d := range map[string]*time.Duration{
+		TimeoutEnv:       &cfg.Timeout,
+		RetryDelayEnv:    &cfg.Retry.BaseDelay,
+		RetryMaxDelayEnv: &cfg.Retry.MaxDelay,
+	} {
+		if v := os.Getenv(env); v != "" {
+			parsed, err := parseTimeout(v)
+			if err != nil {
+				return HTTPConfig{}, fmt.Errorf("%s: %w", env, err)
+			}
+			*d = parsed
+		}
+	}
+	if v := os.Getenv(RetryAttemptsEnv); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return HTTPConfig{}, fmt.Errorf("%s: invalid attempt count %q", RetryAttemptsEnv, v)
+		}
+		cfg.Retry.Attempts = n
+	}
+	return cfg, nil
+}
+
+func parseTimeout(v string) (time.Duration, error) {
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", v)
+	}
+	return d, nil
+}
+
+func (c HTTPConfig) NewClient() (*http.Client, error) {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+
+	if c.Proxy != "" {
+		u, err := url.Parse(c.Proxy)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", c.Proxy)
+		}
+		base.Proxy = http.ProxyURL(u)
+	}
+
+	tlsCfg, err := c.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	base.TLSClientConfig = tlsCfg
+
+	ua := &userAgentTransport{next: base, agent: DefaultUserAgent}
+	if c.UserAgent != "" {
+		ua.agent, ua.override = c.UserAgent, true
+	}
+
+	transport, err := httputil.TransportFromEnv(ua)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Timeout: c.Timeout, Transport: transport}, nil
+}
+
+func (c HTTPConfig) tlsConfig() (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("ca file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ca file %s: no certificates found", c.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if (c.ClientCert == "") != (c.ClientKey == "") {
+		return nil, errors.New("client certificate and key must be set together")
+	}
+	if c.ClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(c.ClientCert, c.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// NewBaseClient reads the HTTP configuration from the environment and
+// builds a client with it, the shared cache and the configured retry policy.
+func NewBaseClient(cacheTTL time.Duration) (BaseClient, error) {
+	cfg, err := HTTPConfigFromEnv()
+	if err != nil {
+		return BaseClient{}, err
+	}
+	httpClient, err := cfg.NewClient()
+	if err != nil {
+		return BaseClient{}, err
+	}
+	cache, err := NewCache(cacheTTL)
+	if err != nil {
+		return BaseClient{}, err
+	}
+	return BaseClient{HTTP: httpClient, Cache: cache, Retry: &cfg.Retry}, nil
+}
+
+// userAgentTransport fills in a User-Agent for clients that do not set one.
+// An explicitly configured agent overrides per-client headers as well.
+type userAgentTransport struct {
+	next     http.RoundTripper
+	agent    string
+	override bool
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.override || req.Header.Get("User-Agent") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", t.agent)
+	}
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,196 @@
+package integrations
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/matzehuels/stacktower/pkg/httputil"
+)
+
+func TestHTTPConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name        string
+		timeout     string
+		wantTimeout time.Duration
+		wantErr     bool
+	}{
+		{"default", "", DefaultHTTPTimeout, false},
+		{"seconds", "30", 30 * time.Second, false},
+		{"duration", "1m30s", 90 * time.Second, false},
+		{"invalid", "soon", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(TimeoutEnv, tt.timeout)
+			t.Setenv(UserAgentEnv, "acme-scanner/2.0")
+
+			cfg, err := HTTPConfigFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if cfg.Timeout != tt.wantTimeout {
+				t.Errorf("Timeout = %v, want %v", cfg.Timeout, tt.wantTimeout)
+			}
+			if cfg.UserAgent != "acme-scanner/2.0" {
+				t.Errorf("UserAgent = %q, want acme-scanner/2.0", cfg.UserAgent)
+			}
+		})
+	}
+}
+
+func TestHTTPConfigFromEnv_Retry(t *testing.T) {
+	t.Setenv(RetryAttemptsEnv, "5")
+	t.Setenv(RetryDelayEnv, "250ms")
+	t.Setenv(RetryMaxDelayEnv, "")
+
+	cfg, err := HTTPConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Retry.Attempts != 5 || cfg.Retry.BaseDelay != 250*time.Millisecond {
+		t.Errorf("Retry = %+v, want 5 attempts from 250ms", cfg.Retry)
+	}
+	if cfg.Retry.MaxDelay != httputil.DefaultRetryPolicy.MaxDelay {
+		t.Errorf("MaxDelay = %v, want the default", cfg.Retry.MaxDelay)
+	}
+
+	t.Setenv(RetryAttemptsEnv, "0")
+	if _, err := HTTPConfigFromEnv(); err == nil {
+		t.Error("expected error for zero attempts")
+	}
+
+	t.Setenv(RetryAttemptsEnv, "2")
+	t.Setenv("HOME", t.TempDir())
+	c, err := NewBaseClient(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Retry == nil || c.Retry.Attempts != 2 {
+		t.Errorf("NewBaseClient retry = %+v, want 2 attempts", c.Retry)
+	}
+}
+
+func TestHTTPConfig_UserAgent(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("User-Agent")
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		configured string
+		perRequest string
+		want       string
+	}{
+		{"default", "", "", DefaultUserAgent},
+		{"perClientKept", "", "crates-client/1.0", "crates-client/1.0"},
+		{"configuredOverrides", "corp/1.0", "crates-client/1.0", "corp/1.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := HTTPConfig{UserAgent: tt.configured}.NewClient()
+			if err != nil {
+				t.Fatalf("NewClient: %v", err)
+			}
+			req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+			if tt.perRequest != "" {
+				req.Header.Set("User-Agent", tt.perRequest)
+			}
+			resp, err := c.Do(req)
+			if err != nil {
+				t.Fatalf("request: %v", err)
+			}
+			resp.Body.Close()
+			if got != tt.want {
+				t.Errorf("User-Agent = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPConfig_CAFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, certPEM, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	untrusted, err := HTTPConfig{}.NewClient()
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	if _, err := untrusted.Get(server.URL); err == nil {
+		t.Error("expected TLS error without custom CA")
+	}
+
+	trusted, err := HTTPConfig{CAFile: caFile}.NewClient()
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	resp, err := trusted.Get(server.URL)
+	if err != nil {
+		t.Fatalf("request with custom CA: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestHTTPConfig_Proxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+	}))
+	defer proxy.Close()
+
+	c, err := HTTPConfig{Proxy: proxy.URL}.NewClient()
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	resp, err := c.Get("http://registry.example/pypi/flask/json")
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	resp.Body.Close()
+	if proxied != "http://registry.example/pypi/flask/json" {
+		t.Errorf("proxy saw %q", proxied)
+	}
+}
+
+func TestHTTPConfig_Errors(t *testing.T) {
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	if err := os.WriteFile(empty, []byte("not a cert"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		cfg  HTTPConfig
+	}{
+		{"badProxy", HTTPConfig{Proxy: "://nope"}},
+		{"missingCA", HTTPConfig{CAFile: "/nonexistent/ca.pem"}},
+		{"emptyCA", HTTPConfig{CAFile: empty}},
+		{"certWithoutKey", HTTPConfig{ClientCert: "client.pem"}},
+		{"missingCert", HTTPConfig{ClientCert: "/nonexistent/c.pem", ClientKey: "/nonexistent/k.pem"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.cfg.NewClient(); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package integrations
+
+import (
+	"cmp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// CompareVersions is a best-effort ordering that works across the semver,
+// PEP 440, RubyGems and Composer styles. Versions are split into numeric and
+// alphabetic tokens; pre-release tags sort before the release they precede
+// and post-release tags after it.
+func CompareVersions(a, b string) int {
+	if a == b {
+		return 0
+	}
+	if a == "0" {
+		return -1
+	}
+	if b == "0" {
+		return 1
+	}
+
+	ta, tb := tokenize(a), tokenize(b)
+	for i := 0; i < max(len(ta), len(tb)); i++ {
+		var c int
+		switch {
+		case i >= len(ta):
+			c = missingVsToken(tb[i])
+		case i >= len(tb):
+			c = -missingVsToken(ta[i])
+		default:
+			c = compareTokens(ta[i], tb[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+type token struct {
+	num   int
+	alpha string
+}
+
+func tokenize(v string) []token {
+	v = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(v)), "v")
+	if i := strings.IndexByte(v, '+'); i >= 0 {
+		v = v[:i]
+	}
+
+	var tokens []token
+	for i := 0; i < len(v); {
+		r := rune(v[i])
+		j := i
+		switch {
+		case unicode.IsDigit(r):
+			for j < len(v) && unicode.IsDigit(rune(v[j])) {
+				j++
+			}
+			n, _ := strconv.Atoi(v[i:j])
+			tokens = append(tokens, token{num: n})
+		case unicode.IsLetter(r):
+			for j < len(v) && unicode.IsLetter(rune(v[j])) {
+				j++
+			}
+			tokens = append(tokens, token{alpha: v[i:j]})
+		default:
+			j++
+		}
+		i = j
+	}
+	return tokens
+}
+
+func compareTokens(a, b token) int {
+	switch {
+	case a.alpha == "" && b.alpha == "":
+		return cmp.Compare(a.num, b.num)
+	case a.alpha == "":
+		return 1
+	case b.alpha == "":
+		return -1
+	}
+	if c := cmp.Compare(tagRank(a.alpha), tagRank(b.alpha)); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.alpha, b.alpha)
+}
+
+// missingVsToken compares an exhausted version against the remaining token
+// of the other: "1.0" equals "1.0.0", is newer than "1.0-rc1" and older
+// than "1.0.post1".
+func missingVsToken(t token) int {
+	switch {
+	case t.alpha == "":
+		return cmp.Compare(0, t.num)
+	case tagRank(t.alpha) == postRank:
+		return -1
+	default:
+		return 1
+	}
+}
+
+const (
+	preRank  = 3
+	postRank = 5
+)
+
+func tagRank(tag string) int {
+	switch tag {
+	case "dev", "snapshot":
+		return 0
+	case "a", "alpha":
+		return 1
+	case "b", "beta":
+		return 2
+	case "c", "rc", "pre", "preview":
+		return preRank
+	case "post", "p", "pl", "patch":
+		return postRank
+	default:
+		return 4
+	}
+}
+
+// IsPrerelease reports whether v carries a tag that sorts before a release,
+// such as 1.0.0-rc1, 2.0b3 or 1.1.dev0.
+func IsPrerelease(v string) bool {
+	for _, t := range tokenize(v) {
+		if t.alpha != "" && tagRank(t.alpha) <= preRank {
+			return true
+		}
+	}
+	return false
+}
+
+// ReleaseDay reduces a registry timestamp such as 2023-05-22T15:12:42Z to
+// the day it names, or returns "" if ts doesn't start with a date.
+func ReleaseDay(ts string) string {
+	if len(ts) < len(time.DateOnly) {
+		return ""
+	}
+	day := ts[:len(time.DateOnly)]
+	if _, err := time.Parse(time.DateOnly, day); err != nil {
+		return ""
+	}
+	return day
+}
@@ -0,0 +1,62 @@
+package integrations
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"1.0", "1.0.0", 0},
+		{"v1.2.3", "1.2.3", 0},
+		{"1.2.3+build5", "1.2.3", 0},
+		{"1.2.10", "1.2.9", 1},
+		{"0", "0.0.1", -1},
+		{"1.0.0-rc1", "1.0.0", -1},
+		{"1.0.0-alpha", "1.0.0-beta", -1},
+		{"1.0rc1", "1.0b2", 1},
+		{"1.0.dev1", "1.0a1", -1},
+		{"1.0.post1", "1.0", 1},
+		{"1.0.post1", "1.0.1", -1},
+		{"2.0.0.beta1", "1.9.9", 1},
+	}
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := CompareVersions(tt.b, tt.a); got != -tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+func TestIsPrerelease(t *testing.T) {
+	for v, want := range map[string]bool{
+		"1.0.0":       false,
+		"1.0.post1":   false,
+		"31.1-jre":    false,
+		"1.0.0-rc.1":  true,
+		"2.0b3":       true,
+		"1.1.dev0":    true,
+		"5.0.0-alpha": true,
+	} {
+		if got := IsPrerelease(v); got != want {
+			t.Errorf("IsPrerelease(%q) = %v, want %v", v, got, want)
+		}
+	}
+}
+
+func TestReleaseDay(t *testing.T) {
+	for ts, want := range map[string]string{
+		"2023-05-22T15:12:42.123Z":  "2023-05-22",
+		"2024-03-01T10:00:00+00:00": "2024-03-01",
+		"2024-03-01":                "2024-03-01",
+		"yesterday":                 "",
+		"":                          "",
+	} {
+		if got := ReleaseDay(ts); got != want {
+			t.Errorf("ReleaseDay(%q) = %q, want %q", ts, got, want)
+		}
+	}
+}
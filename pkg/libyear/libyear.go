@@ -0,0 +1,90 @@
+package libyear
+
+import (
+	"time"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+	"github.com/matzehuels/stacktower/pkg/integrations"
+)
+
+const daysPerYear = 365.25
+
+// Lag is how far a node's version trails the newest release. Libyears is
+// the time between the two releases; AgeYears is how long ago the node's
+// version itself was released.
+type Lag struct {
+	Version        string  `json:"version"`
+	Released       string  `json:"released"`
+	Latest         string  `json:"latest"`
+	LatestReleased string  `json:"latest_released"`
+	Libyears       float64 `json:"libyears"`
+	AgeYears       float64 `json:"age_years"`
+}
+
+// Of measures n from its version and release history. The newest release
+// is the highest version, ignoring pre-releases unless n's own version is
+// one. It reports false when the version's release date is unknown.
+func Of(n *dag.Node) (Lag, bool) {
+	return of(n, time.Now())
+}
+
+func of(n *dag.Node, now time.Time) (Lag, bool) {
+	if n == nil || n.Meta == nil {
+		return Lag{}, false
+	}
+	version, _ := n.Meta["version"].(string)
+	releases := releaseDays(n.Meta["releases"])
+	released := parseDay(releases[version])
+	if version == "" || released.IsZero() {
+		return Lag{}, false
+	}
+
+	withPre := integrations.IsPrerelease(version)
+	latest := version
+	for v, day := range releases {
+		if parseDay(day).IsZero() || (!withPre && integrations.IsPrerelease(v)) {
+			continue
+		}
+		if integrations.CompareVersions(v, latest) > 0 {
+			latest = v
+		}
+	}
+	latestReleased := parseDay(releases[latest])
+
+	return Lag{
+		Version:        version,
+		Released:       releases[version],
+		Latest:         latest,
+		LatestReleased: releases[latest],
+		Libyears:       years(latestReleased.Sub(released)),
+		AgeYears:       years(now.Sub(released)),
+	}, true
+}
+
+func years(d time.Duration) float64 {
+	return max(0, d.Hours()/24/daysPerYear)
+}
+
+func parseDay(s string) time.Time {
+	t, _ := time.Parse(time.DateOnly, s)
+	return t
+}
+
+// releaseDays accepts both the parser's native form and the one decoded
+// from JSON.
+func releaseDays(v any) map[string]string {
+	switch val := v.(type) {
+	case map[string]string:
+		return val
+	case map[string]any:
+		out := make(map[string]string, len(val))
+		for k, d := range val {
+			if s, ok := d.(string); ok {
+				out[k] = s
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,88 @@
+package libyear
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+)
+
+var now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestOf(t *testing.T) {
+	releases := map[string]string{
+		"1.0.0":     "2022-01-01",
+		"1.9.0":     "2023-01-01",
+		"2.0.0":     "2024-01-01",
+		"1.9.1":     "2024-06-01", // backport, released after 2.0.0
+		"3.0.0-rc1": "2024-12-01",
+	}
+
+	cases := []struct {
+		name      string
+		version   string
+		latest    string
+		libyears  float64
+		ageYears  float64
+		wantKnown bool
+	}{
+		{"behind", "1.0.0", "2.0.0", 2.0, 3.0, true},
+		{"newest", "2.0.0", "2.0.0", 0, 1.0, true},
+		{"backport is not newer", "1.9.1", "2.0.0", 0, 0.59, true},
+		{"prerelease counts for prereleases", "3.0.0-rc1", "3.0.0-rc1", 0, 0.08, true},
+		{"undated version", "0.1.0", "", 0, 0, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			n := &dag.Node{ID: "pkg", Meta: dag.Metadata{"version": tc.version, "releases": releases}}
+			lag, ok := of(n, now)
+			if ok != tc.wantKnown {
+				t.Fatalf("known = %v, want %v", ok, tc.wantKnown)
+			}
+			if !ok {
+				return
+			}
+			if lag.Latest != tc.latest {
+				t.Errorf("latest = %s, want %s", lag.Latest, tc.latest)
+			}
+			if math.Abs(lag.Libyears-tc.libyears) > 0.01 {
+				t.Errorf("libyears = %.3f, want %.2f", lag.Libyears, tc.libyears)
+			}
+			if math.Abs(lag.AgeYears-tc.ageYears) > 0.01 {
+				t.Errorf("age = %.3f, want %.2f", lag.AgeYears, tc.ageYears)
+			}
+		})
+	}
+}
+
+func TestAssess(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "current", Meta: dag.Metadata{
+		"version": "2.0", "releases": map[string]string{"1.0": "2020-01-01", "2.0": "2024-01-01"},
+	}})
+	g.AddNode(dag.Node{ID: "stale", Meta: dag.Metadata{
+		"version": "1.0", "releases": map[string]string{"1.0": "2020-01-01", "2.0": "2022-01-01"},
+	}})
+	g.AddNode(dag.Node{ID: "bare", Meta: dag.Metadata{"version": "1.0"}})
+
+	// Metadata read back from a graph file decodes to map[string]any.
+	var decoded dag.Metadata
+	data, _ := json.Marshal(dag.Metadata{"version": "1.0", "releases": map[string]string{"1.0": "2021-01-01", "1.1": "2022-01-01"}})
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	g.AddNode(dag.Node{ID: "decoded", Meta: decoded})
+
+	r := Assess(g)
+	if len(r.Packages) != 3 || r.Packages[0].Package != "stale" || r.Packages[1].Package != "decoded" {
+		t.Fatalf("expected stalest first, got %+v", r.Packages)
+	}
+	if math.Abs(r.Libyears-3.0) > 0.01 {
+		t.Errorf("total libyears = %.3f, want 3", r.Libyears)
+	}
+	if len(r.Unknown) != 1 || r.Unknown[0] != "bare" {
+		t.Errorf("unknown = %v", r.Unknown)
+	}
+}
@@ -0,0 +1,48 @@
+package libyear
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+)
+
+type Finding struct {
+	Package string `json:"package"`
+	Lag
+}
+
+type Report struct {
+	Packages []Finding `json:"packages"`
+	Libyears float64   `json:"libyears"` // summed over Packages
+	Unknown  []string  `json:"unknown,omitempty"`
+}
+
+// Assess measures every package in g, stalest first.
+func Assess(g *dag.DAG) Report {
+	var r Report
+	now := time.Now()
+	for _, n := range g.Nodes() {
+		if n.IsSynthetic() {
+			continue
+		}
+		lag, ok := of(n, now)
+		if !ok {
+			r.Unknown = append(r.Unknown, n.ID)
+			continue
+		}
+		r.Packages = append(r.Packages, Finding{Package: n.ID, Lag: lag})
+		r.Libyears += lag.Libyears
+	}
+
+	slices.SortFunc(r.Packages, func(a, b Finding) int {
+		return cmp.Or(
+			cmp.Compare(b.Libyears, a.Libyears),
+			cmp.Compare(b.AgeYears, a.AgeYears),
+			cmp.Compare(a.Package, b.Package),
+		)
+	})
+	slices.Sort(r.Unknown)
+	return r
+}
@@ -0,0 +1,102 @@
+package license
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+)
+
+type Finding struct {
+	Package string   `json:"package"`
+	License string   `json:"license"`
+	Family  Family   `json:"family"`
+	Reason  string   `json:"reason,omitempty"`
+	Path    []string `json:"path,omitempty"`
+}
+
+type Report struct {
+	Packages   []Finding `json:"packages"`
+	Violations []Finding `json:"violations"`
+}
+
+// Of returns the node's declared license, preferring the registry's value
+// over the one detected on the source repository.
+func Of(n *dag.Node) string {
+	if n == nil || n.Meta == nil {
+		return ""
+	}
+	if s, _ := n.Meta["license"].(string); s != "" {
+		return s
+	}
+	s, _ := n.Meta["repo_license"].(string)
+	return s
+}
+
+// Classify returns the normalized expression and family for a node.
+func Classify(n *dag.Node) (string, Family) {
+	e, err := Parse(Of(n))
+	if err != nil {
+		return "", FamilyUnknown
+	}
+	return e.String(), e.Family()
+}
+
+// Check evaluates every package in g against the policy. Each violation
+// carries the shortest dependency path from a root that pulls it in.
+func Check(g *dag.DAG, p Policy) Report {
+	var r Report
+	for _, n := range g.Nodes() {
+		if n.IsSynthetic() {
+			continue
+		}
+
+		f := Finding{Package: n.ID, Family: FamilyUnknown}
+		raw := Of(n)
+		e, err := Parse(raw)
+		ok, reason := p.AllowUnknown, "no license declared"
+		switch {
+		case err == nil:
+			f.License, f.Family = e.String(), e.Family()
+			ok, reason = p.Evaluate(e)
+		case raw != "":
+			f.License, reason = raw, fmt.Sprintf("unrecognized license %q", raw)
+		}
+
+		r.Packages = append(r.Packages, f)
+		if !ok {
+			f.Reason = reason
+			f.Path = pathFromRoot(g, n.ID)
+			r.Violations = append(r.Violations, f)
+		}
+	}
+
+	byID := func(a, b Finding) int { return cmp.Compare(a.Package, b.Package) }
+	slices.SortFunc(r.Packages, byID)
+	slices.SortFunc(r.Violations, byID)
+	return r
+}
+
+func pathFromRoot(g *dag.DAG, id string) []string {
+	next := map[string]string{id: ""}
+	queue := []string{id}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if g.InDegree(cur) == 0 {
+			var path []string
+			for n := cur; n != ""; n = next[n] {
+				path = append(path, n)
+			}
+			return path
+		}
+		for _, parent := range g.Parents(cur) {
+			if _, seen := next[parent]; !seen {
+				next[parent] = cur
+				queue = append(queue, parent)
+			}
+		}
+	}
+	return []string{id}
+}
@@ -0,0 +1,62 @@
+package license
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+)
+
+func TestCheck(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "app", Meta: dag.Metadata{"license": "MIT"}})
+	g.AddNode(dag.Node{ID: "web", Meta: dag.Metadata{"license": "Apache 2.0"}})
+	g.AddNode(dag.Node{ID: "gpl", Meta: dag.Metadata{"license": "GPLv3"}})
+	g.AddNode(dag.Node{ID: "repo", Meta: dag.Metadata{"repo_license": "BSD-3-Clause"}})
+	g.AddNode(dag.Node{ID: "none"})
+	g.AddEdge(dag.Edge{From: "app", To: "web"})
+	g.AddEdge(dag.Edge{From: "web", To: "gpl"})
+	g.AddEdge(dag.Edge{From: "app", To: "repo"})
+	g.AddEdge(dag.Edge{From: "repo", To: "none"})
+
+	r := Check(g, Policy{Deny: []string{"copyleft"}})
+
+	if len(r.Packages) != 5 {
+		t.Fatalf("expected 5 packages, got %d", len(r.Packages))
+	}
+	if len(r.Violations) != 2 {
+		t.Fatalf("expected 2 violations, got %+v", r.Violations)
+	}
+
+	gpl := r.Violations[0]
+	if gpl.Package != "gpl" || gpl.License != "GPL-3.0-only" || gpl.Family != FamilyCopyleft {
+		t.Errorf("unexpected violation %+v", gpl)
+	}
+	if !slices.Equal(gpl.Path, []string{"app", "web", "gpl"}) {
+		t.Errorf("expected path app → web → gpl, got %v", gpl.Path)
+	}
+
+	none := r.Violations[1]
+	if none.Package != "none" || none.Reason != "no license declared" {
+		t.Errorf("unexpected violation %+v", none)
+	}
+
+	for _, p := range r.Packages {
+		if p.Package == "repo" && p.License != "BSD-3-Clause" {
+			t.Errorf("expected repo_license fallback, got %q", p.License)
+		}
+	}
+}
+
+func TestCheck_Unrecognized(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "odd", Meta: dag.Metadata{"license": "MIT AND"}})
+
+	r := Check(g, Policy{})
+	if len(r.Violations) != 1 {
+		t.Fatalf("expected 1 violation, got %+v", r.Violations)
+	}
+	if v := r.Violations[0]; v.Reason != `unrecognized license "MIT AND"` || v.License != "MIT AND" {
+		t.Errorf("unexpected violation %+v", v)
+	}
+}
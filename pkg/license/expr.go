@@ -0,0 +1,204 @@
+package license
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrEmpty = errors.New("empty license expression")
+
+// Expr is a parsed SPDX license expression. Leaves carry a license ID
+// (optionally with an exception); inner nodes combine Args with AND or OR.
+type Expr struct {
+	Op        string
+	License   string
+	Exception string
+	Args      []*Expr
+}
+
+const (
+	OpAnd = "AND"
+	OpOr  = "OR"
+)
+
+func (e *Expr) String() string {
+	if e.Op == "" {
+		if e.Exception != "" {
+			return e.License + " WITH " + e.Exception
+		}
+		return e.License
+	}
+	parts := make([]string, len(e.Args))
+	for i, a := range e.Args {
+		parts[i] = a.String()
+		if a.Op != "" && a.Op != e.Op {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	return strings.Join(parts, " "+e.Op+" ")
+}
+
+// Licenses returns every license ID in the expression, left to right.
+func (e *Expr) Licenses() []string {
+	if e.Op == "" {
+		return []string{e.License}
+	}
+	var ids []string
+	for _, a := range e.Args {
+		ids = append(ids, a.Licenses()...)
+	}
+	return ids
+}
+
+// Parse reads a license string as found in package registries and returns
+// a normalized SPDX expression. Besides proper SPDX syntax it accepts the
+// informal forms registries are full of: "MIT/Apache-2.0", "MIT, BSD",
+// "Apache License 2.0", or the full text of a license.
+func Parse(raw string) (*Expr, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, ErrEmpty
+	}
+	if id, ok := lookup(raw); ok {
+		return &Expr{License: id}, nil
+	}
+	if looksLikeText(raw) {
+		return &Expr{License: sniffText(raw)}, nil
+	}
+
+	p := &exprParser{tokens: tokenize(raw)}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("parse %q: %w", raw, err)
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("parse %q: unexpected %q", raw, p.tokens[p.pos])
+	}
+	return e, nil
+}
+
+// Normalize returns the canonical SPDX form of raw, or raw itself when it
+// cannot be parsed.
+func Normalize(raw string) string {
+	e, err := Parse(raw)
+	if err != nil {
+		return strings.TrimSpace(raw)
+	}
+	return e.String()
+}
+
+func tokenize(s string) []string {
+	var tokens []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			tokens = append(tokens, word.String())
+			word.Reset()
+		}
+	}
+	for _, r := range s {
+		switch r {
+		case '(', ')', '/', ',', ';':
+			flush()
+			tokens = append(tokens, string(r))
+		case ' ', '\t', '\n':
+			flush()
+		default:
+			word.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+type exprParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *exprParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func isOr(tok string) bool {
+	return strings.EqualFold(tok, "or") || tok == "/" || tok == "," || tok == ";"
+}
+func isAnd(tok string) bool { return strings.EqualFold(tok, "and") }
+
+func (p *exprParser) parseOr() (*Expr, error) {
+	return p.parseBinary(OpOr, isOr, p.parseAnd)
+}
+
+func (p *exprParser) parseAnd() (*Expr, error) {
+	return p.parseBinary(OpAnd, isAnd, p.parseAtom)
+}
+
+func (p *exprParser) parseBinary(op string, isOp func(string) bool, next func() (*Expr, error)) (*Expr, error) {
+	first, err := next()
+	if err != nil {
+		return nil, err
+	}
+	args := []*Expr{first}
+	for isOp(p.peek()) {
+		p.pos++
+		e, err := next()
+		if err != nil {
+			return nil, err
+		}
+		if e.Op == op {
+			args = append(args, e.Args...)
+		} else {
+			args = append(args, e)
+		}
+	}
+	if len(args) == 1 {
+		return first, nil
+	}
+	return &Expr{Op: op, Args: args}, nil
+}
+
+func (p *exprParser) parseAtom() (*Expr, error) {
+	if p.peek() == "(" {
+		p.pos++
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, errors.New("missing closing parenthesis")
+		}
+		p.pos++
+		return e, nil
+	}
+
+	name := p.words()
+	if name == "" {
+		if tok := p.peek(); tok != "" {
+			return nil, fmt.Errorf("unexpected %q", tok)
+		}
+		return nil, errors.New("unexpected end of expression")
+	}
+	e := &Expr{License: canonical(name)}
+	if strings.EqualFold(p.peek(), "with") {
+		p.pos++
+		if e.Exception = p.words(); e.Exception == "" {
+			return nil, errors.New("missing exception after WITH")
+		}
+	}
+	return e, nil
+}
+
+// words joins consecutive plain tokens so that informal names such as
+// "Apache License 2.0" form a single operand.
+func (p *exprParser) words() string {
+	var words []string
+	for tok := p.peek(); tok != "" && !isOr(tok) && !isAnd(tok) && !strings.EqualFold(tok, "with") && tok != "(" && tok != ")"; tok = p.peek() {
+		words = append(words, tok)
+		p.pos++
+	}
+	return strings.Join(words, " ")
+}
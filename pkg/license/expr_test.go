@@ -0,0 +1,64 @@
+package license
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"MIT", "MIT"},
+		{"mit", "MIT"},
+		{"MIT License", "MIT"},
+		{"Apache License, Version 2.0", "Apache-2.0"},
+		{"Apache 2.0", "Apache-2.0"},
+		{"MIT/Apache-2.0", "MIT OR Apache-2.0"},
+		{"MIT OR Apache-2.0", "MIT OR Apache-2.0"},
+		{"MIT, BSD", "MIT OR BSD-3-Clause"},
+		{"(MIT OR Apache-2.0) AND Unicode-DFS-2016", "(MIT OR Apache-2.0) AND Unicode-DFS-2016"},
+		{"MIT or Apache 2.0", "MIT OR Apache-2.0"},
+		{"GPL-2.0+", "GPL-2.0-or-later"},
+		{"GPL-2.0-or-later WITH Classpath-exception-2.0", "GPL-2.0-or-later WITH Classpath-exception-2.0"},
+		{"MIT OR (Apache-2.0 OR ISC)", "MIT OR Apache-2.0 OR ISC"},
+		{"Permission is hereby granted, free of charge, to any person obtaining a copy of this software", "MIT"},
+		{"Copyright (c) 2020 Someone\n\nAll rights reserved, no idea what this is.", "NOASSERTION"},
+		{"(MIT", "(MIT"},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.in); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, in := range []string{"", "MIT AND", "(MIT", "MIT WITH", ")"} {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) expected error", in)
+		}
+	}
+}
+
+func TestExpr_Family(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Family
+	}{
+		{"MIT", FamilyPermissive},
+		{"CC0-1.0", FamilyPublicDomain},
+		{"LGPL-2.1-only", FamilyWeakCopyleft},
+		{"GPL-3.0-only", FamilyCopyleft},
+		{"GPL-3.0-only OR MIT", FamilyPermissive},
+		{"MIT AND GPL-3.0-only", FamilyCopyleft},
+		{"Some-Custom-License", FamilyUnknown},
+		{"MIT OR Some-Custom-License", FamilyPermissive},
+	}
+	for _, tt := range tests {
+		e, err := Parse(tt.in)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.in, err)
+		}
+		if got := e.Family(); got != tt.want {
+			t.Errorf("Family(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package license
+
+import "strings"
+
+type Family string
+
+const (
+	FamilyPublicDomain Family = "public-domain"
+	FamilyPermissive   Family = "permissive"
+	FamilyWeakCopyleft Family = "weak-copyleft"
+	FamilyCopyleft     Family = "copyleft"
+	FamilyProprietary  Family = "proprietary"
+	FamilyUnknown      Family = "unknown"
+)
+
+// Families lists every family from least to most restrictive.
+var Families = []Family{
+	FamilyPublicDomain, FamilyPermissive, FamilyWeakCopyleft,
+	FamilyCopyleft, FamilyProprietary, FamilyUnknown,
+}
+
+var familyPrefixes = []struct {
+	prefix string
+	family Family
+}{
+	{"AGPL-", FamilyCopyleft},
+	{"LGPL-", FamilyWeakCopyleft},
+	{"GPL-", FamilyCopyleft},
+	{"EUPL-", FamilyCopyleft},
+	{"OSL-", FamilyCopyleft},
+	{"SSPL-", FamilyCopyleft},
+	{"CC-BY-SA-", FamilyCopyleft},
+	{"MPL-", FamilyWeakCopyleft},
+	{"EPL-", FamilyWeakCopyleft},
+	{"CDDL-", FamilyWeakCopyleft},
+	{"MS-RL", FamilyWeakCopyleft},
+	{"BSD-", FamilyPermissive},
+	{"Apache-", FamilyPermissive},
+	{"Artistic-", FamilyPermissive},
+	{"CC-BY-", FamilyPermissive},
+	{"PHP-", FamilyPermissive},
+	{"ZPL-", FamilyPermissive},
+}
+
+var familyIDs = map[string]Family{
+	"0BSD":                          FamilyPublicDomain,
+	"CC0-1.0":                       FamilyPublicDomain,
+	"Unlicense":                     FamilyPublicDomain,
+	"WTFPL":                         FamilyPublicDomain,
+	PublicDomain:                    FamilyPublicDomain,
+	"MIT":                           FamilyPermissive,
+	"MIT-0":                         FamilyPermissive,
+	"MIT-CMU":                       FamilyPermissive,
+	"ISC":                           FamilyPermissive,
+	"X11":                           FamilyPermissive,
+	"Zlib":                          FamilyPermissive,
+	"BSL-1.0":                       FamilyPermissive,
+	"PSF-2.0":                       FamilyPermissive,
+	"Python-2.0":                    FamilyPermissive,
+	"Ruby":                          FamilyPermissive,
+	"HPND":                          FamilyPermissive,
+	"NCSA":                          FamilyPermissive,
+	"OpenSSL":                       FamilyPermissive,
+	"PostgreSQL":                    FamilyPermissive,
+	"UPL-1.0":                       FamilyPermissive,
+	"W3C":                           FamilyPermissive,
+	"MS-PL":                         FamilyPermissive,
+	"AFL-3.0":                       FamilyPermissive,
+	"BlueOak-1.0.0":                 FamilyPermissive,
+	"OFL-1.1":                       FamilyPermissive,
+	"Unicode-3.0":                   FamilyPermissive,
+	"Unicode-DFS-2016":              FamilyPermissive,
+	"MPL-2.0-no-copyleft-exception": FamilyPermissive,
+	"LicenseRef-Proprietary":        FamilyProprietary,
+}
+
+func familyOf(id string) Family {
+	if f, ok := familyIDs[id]; ok {
+		return f
+	}
+	for _, p := range familyPrefixes {
+		if strings.HasPrefix(id, p.prefix) {
+			return p.family
+		}
+	}
+	return FamilyUnknown
+}
+
+func (f Family) rank() int {
+	for i, fam := range Families {
+		if fam == f {
+			return i
+		}
+	}
+	return len(Families)
+}
+
+// Family classifies an expression: a choice (OR) takes its least
+// restrictive option, a conjunction (AND) its most restrictive part.
+func (e *Expr) Family() Family {
+	if e.Op == "" {
+		return familyOf(e.License)
+	}
+	best := e.Args[0].Family()
+	for _, a := range e.Args[1:] {
+		f := a.Family()
+		if (e.Op == OpOr) == (f.rank() < best.rank()) {
+			best = f
+		}
+	}
+	return best
+}
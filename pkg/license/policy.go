@@ -0,0 +1,80 @@
+package license
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Policy decides which licenses are acceptable. Entries in Allow and Deny
+// are SPDX IDs, family names such as "copyleft", or prefixes ending in "*"
+// such as "GPL-*". Deny wins over Allow; an empty Allow permits everything
+// not denied.
+type Policy struct {
+	Allow        []string `json:"allow,omitempty"`
+	Deny         []string `json:"deny,omitempty"`
+	AllowUnknown bool     `json:"allow_unknown,omitempty"`
+}
+
+func LoadPolicy(path string) (Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Policy{}, err
+	}
+	var p Policy
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&p); err != nil {
+		return Policy{}, fmt.Errorf("policy %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Evaluate reports whether e is acceptable and, if not, why. A choice (OR)
+// passes when any option does; a conjunction (AND) needs every part to.
+func (p Policy) Evaluate(e *Expr) (bool, string) {
+	switch e.Op {
+	case OpOr:
+		var reasons []string
+		for _, a := range e.Args {
+			ok, reason := p.Evaluate(a)
+			if ok {
+				return true, ""
+			}
+			reasons = append(reasons, reason)
+		}
+		return false, strings.Join(reasons, "; ")
+	case OpAnd:
+		for _, a := range e.Args {
+			if ok, reason := p.Evaluate(a); !ok {
+				return false, reason
+			}
+		}
+		return true, ""
+	}
+
+	family := familyOf(e.License)
+	switch {
+	case p.matches(p.Deny, e.License, family):
+		return false, e.License + " is denied"
+	case family == FamilyUnknown && !p.AllowUnknown && !p.matches(p.Allow, e.License, family):
+		return false, e.License + " is not a recognized license"
+	case len(p.Allow) > 0 && !p.matches(p.Allow, e.License, family):
+		return false, e.License + " is not allowed"
+	}
+	return true, ""
+}
+
+func (p Policy) matches(entries []string, id string, family Family) bool {
+	for _, entry := range entries {
+		switch {
+		case strings.EqualFold(entry, id), strings.EqualFold(entry, string(family)):
+			return true
+		case strings.HasSuffix(entry, "*") && strings.HasPrefix(strings.ToLower(id), strings.ToLower(strings.TrimSuffix(entry, "*"))):
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package license
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPolicy_Evaluate(t *testing.T) {
+	p := Policy{Allow: []string{"permissive", "public-domain", "MPL-2.0"}, Deny: []string{"AGPL-*"}}
+
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"MIT", true},
+		{"MPL-2.0", true},
+		{"LGPL-3.0-only", false},
+		{"GPL-3.0-only OR MIT", true},
+		{"GPL-3.0-only AND MIT", false},
+		{"AGPL-3.0-only", false},
+		{"Custom-License", false},
+	}
+	for _, tt := range tests {
+		e, _ := Parse(tt.in)
+		if got, reason := p.Evaluate(e); got != tt.want {
+			t.Errorf("Evaluate(%q) = %v (%s), want %v", tt.in, got, reason, tt.want)
+		}
+	}
+
+	deny := Policy{Deny: []string{"copyleft"}}
+	e, _ := Parse("GPL-2.0-only")
+	if ok, reason := deny.Evaluate(e); ok || reason != "GPL-2.0-only is denied" {
+		t.Errorf("expected family deny, got %v %q", ok, reason)
+	}
+	e, _ = Parse("Custom-License")
+	if ok, _ := (Policy{AllowUnknown: true}).Evaluate(e); !ok {
+		t.Error("expected unknown license to pass with allow_unknown")
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "policy.json")
+	os.WriteFile(path, []byte(`{"allow": ["MIT"], "deny": ["GPL-*"], "allow_unknown": true}`), 0o644)
+
+	p, err := LoadPolicy(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Allow) != 1 || len(p.Deny) != 1 || !p.AllowUnknown {
+		t.Errorf("unexpected policy %+v", p)
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	os.WriteFile(bad, []byte(`{"allowed": ["MIT"]}`), 0o644)
+	if _, err := LoadPolicy(bad); err == nil {
+		t.Error("expected error for unknown field")
+	}
+}
@@ -0,0 +1,174 @@
+package license
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	NoAssertion  = "NOASSERTION"
+	PublicDomain = "LicenseRef-Public-Domain"
+)
+
+// knownIDs lists the SPDX identifiers common in package registries. Lookups
+// are case-insensitive and return the canonical spelling.
+var knownIDs = []string{
+	"0BSD", "AFL-3.0", "AGPL-3.0-only", "AGPL-3.0-or-later", "Apache-1.1", "Apache-2.0",
+	"Artistic-1.0", "Artistic-2.0", "BlueOak-1.0.0", "BSD-1-Clause", "BSD-2-Clause",
+	"BSD-3-Clause", "BSD-3-Clause-Clear", "BSD-4-Clause", "BSL-1.0", "CC-BY-3.0", "CC-BY-4.0",
+	"CC-BY-SA-4.0", "CC0-1.0", "CDDL-1.0", "CDDL-1.1", "EPL-1.0", "EPL-2.0", "EUPL-1.1",
+	"EUPL-1.2", "GPL-2.0-only", "GPL-2.0-or-later", "GPL-3.0-only", "GPL-3.0-or-later",
+	"HPND", "ISC", "LGPL-2.0-only", "LGPL-2.0-or-later", "LGPL-2.1-only", "LGPL-2.1-or-later",
+	"LGPL-3.0-only", "LGPL-3.0-or-later", "MIT", "MIT-0", "MIT-CMU", "MPL-1.1", "MPL-2.0",
+	"MPL-2.0-no-copyleft-exception", "MS-PL", "NCSA", "OFL-1.1", "OpenSSL", "OSL-3.0",
+	"PHP-3.0", "PHP-3.01", "PostgreSQL", "PSF-2.0", "Python-2.0", "Ruby", "SSPL-1.0",
+	"Unicode-3.0", "Unicode-DFS-2016", "Unlicense", "UPL-1.0", "W3C", "WTFPL", "X11",
+	"Zlib", "ZPL-2.1", NoAssertion, PublicDomain,
+}
+
+// aliases maps informal names (lower-cased) to SPDX identifiers, including
+// the deprecated bare GPL-family IDs.
+var aliases = map[string]string{
+	"mit license":                        "MIT",
+	"the mit license":                    "MIT",
+	"mit/x11":                            "MIT",
+	"expat":                              "MIT",
+	"apache":                             "Apache-2.0",
+	"apache 2":                           "Apache-2.0",
+	"apache 2.0":                         "Apache-2.0",
+	"apache-2":                           "Apache-2.0",
+	"apache2":                            "Apache-2.0",
+	"apache license 2.0":                 "Apache-2.0",
+	"apache license, version 2.0":        "Apache-2.0",
+	"apache license version 2.0":         "Apache-2.0",
+	"apache software license":            "Apache-2.0",
+	"apache software license 2.0":        "Apache-2.0",
+	"asl 2.0":                            "Apache-2.0",
+	"bsd":                                "BSD-3-Clause",
+	"bsd license":                        "BSD-3-Clause",
+	"new bsd":                            "BSD-3-Clause",
+	"new bsd license":                    "BSD-3-Clause",
+	"bsd-3":                              "BSD-3-Clause",
+	"3-clause bsd":                       "BSD-3-Clause",
+	"bsd 3-clause":                       "BSD-3-Clause",
+	"modified bsd":                       "BSD-3-Clause",
+	"simplified bsd":                     "BSD-2-Clause",
+	"bsd-2":                              "BSD-2-Clause",
+	"2-clause bsd":                       "BSD-2-Clause",
+	"bsd 2-clause":                       "BSD-2-Clause",
+	"isc license":                        "ISC",
+	"gpl":                                "GPL-2.0-or-later",
+	"gpl-2.0":                            "GPL-2.0-only",
+	"gpl-2.0+":                           "GPL-2.0-or-later",
+	"gplv2":                              "GPL-2.0-only",
+	"gplv2+":                             "GPL-2.0-or-later",
+	"gpl-2":                              "GPL-2.0-only",
+	"gpl v2":                             "GPL-2.0-only",
+	"gpl-3.0":                            "GPL-3.0-only",
+	"gpl-3.0+":                           "GPL-3.0-or-later",
+	"gplv3":                              "GPL-3.0-only",
+	"gplv3+":                             "GPL-3.0-or-later",
+	"gpl-3":                              "GPL-3.0-only",
+	"gpl v3":                             "GPL-3.0-only",
+	"agpl-3.0":                           "AGPL-3.0-only",
+	"agplv3":                             "AGPL-3.0-only",
+	"lgpl":                               "LGPL-2.1-or-later",
+	"lgpl-2.1":                           "LGPL-2.1-only",
+	"lgpl-2.1+":                          "LGPL-2.1-or-later",
+	"lgplv2":                             "LGPL-2.1-only",
+	"lgplv2+":                            "LGPL-2.1-or-later",
+	"lgpl-3.0":                           "LGPL-3.0-only",
+	"lgpl-3.0+":                          "LGPL-3.0-or-later",
+	"lgplv3":                             "LGPL-3.0-only",
+	"lgplv3+":                            "LGPL-3.0-or-later",
+	"mpl 2.0":                            "MPL-2.0",
+	"mpl-2":                              "MPL-2.0",
+	"mpl2":                               "MPL-2.0",
+	"mozilla public license 2.0":         "MPL-2.0",
+	"psf":                                "PSF-2.0",
+	"psfl":                               "PSF-2.0",
+	"python software foundation license": "PSF-2.0",
+	"ruby's":                             "Ruby",
+	"the unlicense":                      "Unlicense",
+	"unlicensed":                         "Unlicense",
+	"cc0":                                "CC0-1.0",
+	"zlib license":                       "Zlib",
+	"zpl 2.1":                            "ZPL-2.1",
+	"eclipse public license 2.0":         "EPL-2.0",
+	"boost":                              "BSL-1.0",
+	"boost software license 1.0":         "BSL-1.0",
+	"artistic_2":                         "Artistic-2.0",
+	"php license":                        "PHP-3.01",
+	"public domain":                      PublicDomain,
+	"public-domain":                      PublicDomain,
+	"dual license":                       NoAssertion,
+	"unknown":                            NoAssertion,
+	"other":                              NoAssertion,
+	"see license":                        NoAssertion,
+	"see license file":                   NoAssertion,
+	"proprietary":                        "LicenseRef-Proprietary",
+	"commercial":                         "LicenseRef-Proprietary",
+}
+
+var canonicalIDs = func() map[string]string {
+	m := make(map[string]string, len(knownIDs))
+	for _, id := range knownIDs {
+		m[strings.ToLower(id)] = id
+	}
+	return m
+}()
+
+func lookup(s string) (string, bool) {
+	key := strings.ToLower(strings.TrimSpace(s))
+	if id, ok := canonicalIDs[key]; ok {
+		return id, true
+	}
+	if id, ok := aliases[key]; ok {
+		return id, true
+	}
+	return "", false
+}
+
+func canonical(s string) string {
+	if id, ok := lookup(s); ok {
+		return id
+	}
+	return s
+}
+
+// looksLikeText reports whether raw is a pasted license body rather than an
+// identifier, as PyPI's free-form license field often is.
+func looksLikeText(raw string) bool {
+	return len(raw) > 80 || strings.Contains(raw, "\n")
+}
+
+var textSignatures = []struct {
+	re *regexp.Regexp
+	id string
+}{
+	{regexp.MustCompile(`(?i)apache license[\s,]+version 2`), "Apache-2.0"},
+	{regexp.MustCompile(`(?i)gnu lesser general public license[\s,]+version 3`), "LGPL-3.0-only"},
+	{regexp.MustCompile(`(?i)gnu lesser general public license`), "LGPL-2.1-only"},
+	{regexp.MustCompile(`(?i)gnu affero general public license`), "AGPL-3.0-only"},
+	{regexp.MustCompile(`(?i)gnu general public license[\s,]+version 3`), "GPL-3.0-only"},
+	{regexp.MustCompile(`(?i)gnu general public license[\s,]+version 2`), "GPL-2.0-only"},
+	{regexp.MustCompile(`(?i)mozilla public license[\s,]+v(ersion)?\.? ?2`), "MPL-2.0"},
+	{regexp.MustCompile(`(?i)permission is hereby granted, free of charge`), "MIT"},
+	{regexp.MustCompile(`(?i)permission to use, copy, modify, and(/or)? distribute this software for any purpose`), "ISC"},
+	{regexp.MustCompile(`(?i)neither the name of`), "BSD-3-Clause"},
+	{regexp.MustCompile(`(?i)redistributions in binary form must reproduce`), "BSD-2-Clause"},
+	{regexp.MustCompile(`(?i)this is free and unencumbered software released into the public domain`), "Unlicense"},
+}
+
+func sniffText(raw string) string {
+	first, _, _ := strings.Cut(raw, "\n")
+	if id, ok := lookup(strings.Trim(first, " \t:")); ok {
+		return id
+	}
+	for _, sig := range textSignatures {
+		if sig.re.MatchString(raw) {
+			return sig.id
+		}
+	}
+	return NoAssertion
+}
@@ -15,6 +15,9 @@ import (
 	"github.com/matzehuels/stacktower/pkg/dag"
 )
 
+// backEdgeColor matches the tower's; see styles.BackEdgeColor.
+const backEdgeColor = "#e67e22"
+
 type Options struct {
 	Detailed bool
 }
@@ -37,7 +40,15 @@ func ToDOT(g *dag.DAG, opts Options) string {
 
 	buf.WriteString("\n")
 	for _, e := range g.Edges() {
-		fmt.Fprintf(&buf, "  %q -> %q;\n", e.From, e.To)
+		if attrs := fmtEdgeAttrs(e); len(attrs) > 0 {
+			fmt.Fprintf(&buf, "  %q -> %q [%s];\n", e.From, e.To, strings.Join(attrs, ", "))
+		} else {
+			fmt.Fprintf(&buf, "  %q -> %q;\n", e.From, e.To)
+		}
+	}
+
+	for _, e := range g.BackEdges() {
+		fmt.Fprintf(&buf, "  %q -> %q [style=dashed, color=%q, constraint=false];\n", e.From, e.To, backEdgeColor)
 	}
 
 	buf.WriteString("}\n")
@@ -51,6 +62,9 @@ func fmtLabel(n dag.Node, detailed bool) string {
 
 	parts := []string{fmt.Sprintf("row: %d", n.Row)}
 	for _, k := range slices.Sorted(maps.Keys(n.Meta)) {
+		if strings.HasPrefix(k, "_") {
+			continue // provenance bookkeeping
+		}
 		parts = append(parts, fmt.Sprintf("%s: %v", k, n.Meta[k]))
 	}
 
@@ -62,9 +76,29 @@ func fmtAttrs(n dag.Node, label string) []string {
 	if n.IsSubdivider() {
 		attrs = append(attrs, "style=\"rounded,filled,dashed\"", "fillcolor=lightgrey", "fontcolor=black")
 	}
+	switch dag.ChangeOf(n.Meta) {
+	case dag.Added:
+		attrs = append(attrs, `fillcolor="#74c69d"`, "penwidth=2")
+	case dag.Changed:
+		attrs = append(attrs, `fillcolor="#ffd166"`)
+	case dag.Removed:
+		attrs = append(attrs, "style=\"rounded,dashed\"", "color=grey", "fontcolor=grey")
+	}
 	return attrs
 }
 
+// fmtEdgeAttrs styles the edges of a dag.Overlay graph.
+func fmtEdgeAttrs(e dag.Edge) []string {
+	switch dag.ChangeOf(e.Meta) {
+	case dag.Added:
+		return []string{`color="#1a9850"`, "penwidth=2"}
+	case dag.Removed:
+		return []string{"style=dashed", "color=grey"}
+	default:
+		return nil
+	}
+}
+
 func RenderSVG(dot string) ([]byte, error) {
 	ctx := context.Background()
 	gv, err := graphviz.New(ctx)
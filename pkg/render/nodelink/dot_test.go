@@ -66,6 +66,38 @@ func TestToDOT(t *testing.T) {
 			opts:     Options{},
 			contains: []string{`"aux"`},
 		},
+		{
+			name: "BackEdges",
+			setup: func() *dag.DAG {
+				g := dag.New(nil)
+				_ = g.AddNode(dag.Node{ID: "a"})
+				_ = g.AddNode(dag.Node{ID: "b"})
+				_ = g.AddEdge(dag.Edge{From: "a", To: "b"})
+				g.Meta()[dag.BackEdgesKey] = []dag.Edge{{From: "b", To: "a"}}
+				return g
+			},
+			opts:     Options{},
+			contains: []string{`"b" -> "a" [style=dashed, color="#e67e22", constraint=false];`},
+		},
+		{
+			name: "DiffOverlay",
+			setup: func() *dag.DAG {
+				g := dag.New(nil)
+				_ = g.AddNode(dag.Node{ID: "new", Meta: dag.Metadata{dag.DiffKey: "added"}})
+				_ = g.AddNode(dag.Node{ID: "old", Meta: dag.Metadata{dag.DiffKey: "removed"}})
+				_ = g.AddNode(dag.Node{ID: "app"})
+				_ = g.AddEdge(dag.Edge{From: "app", To: "new", Meta: dag.Metadata{dag.DiffKey: "added"}})
+				_ = g.AddEdge(dag.Edge{From: "app", To: "old", Meta: dag.Metadata{dag.DiffKey: "removed"}})
+				return g
+			},
+			opts: Options{},
+			contains: []string{
+				`"new" [label="new", fillcolor="#74c69d"`,
+				`"old" [label="old", style="rounded,dashed", color=grey`,
+				`"app" -> "new" [color="#1a9850", penwidth=2];`,
+				`"app" -> "old" [style=dashed, color=grey];`,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -20,6 +20,9 @@ func IsBrittle(n *dag.Node) bool {
 	if archived, _ := n.Meta["repo_archived"].(bool); archived {
 		return true
 	}
+	if IsDeprecated(n) {
+		return true
+	}
 
 	lastCommit := parseDate(n.Meta["repo_last_commit"])
 	if lastCommit.IsZero() {
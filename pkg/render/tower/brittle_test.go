@@ -27,6 +27,21 @@ func TestIsBrittle(t *testing.T) {
 			&dag.Node{ID: "pkg", Meta: dag.Metadata{"repo_archived": true}},
 			true,
 		},
+		{
+			"deprecated on npm",
+			&dag.Node{ID: "pkg", Meta: dag.Metadata{"deprecated": "use other-pkg"}},
+			true,
+		},
+		{
+			"yanked release, active repo",
+			&dag.Node{ID: "pkg", Meta: dag.Metadata{
+				"yanked":           true,
+				"repo_last_commit": oneMonthAgo,
+				"repo_stars":       5000,
+				"repo_maintainers": []string{"a", "b", "c", "d", "e"},
+			}},
+			true,
+		},
 		{
 			"abandoned (3 years stale)",
 			&dag.Node{ID: "pkg", Meta: dag.Metadata{
@@ -0,0 +1,52 @@
+package tower
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+	"github.com/matzehuels/stacktower/pkg/render/tower/styles"
+)
+
+// ColorScale fills blocks by some property of their node. Nodes for which
+// Color reports false keep the style's default fill.
+type ColorScale interface {
+	Color(n *dag.Node) (string, bool)
+	Legend() []styles.LegendEntry
+}
+
+// unknownColor fills blocks whose package lacks the data a scale colors by.
+const unknownColor = "#d9d9d9"
+
+func WithColorScale(s ColorScale) RenderOption {
+	return func(r *renderer) { r.colors = s }
+}
+
+const (
+	legendHeight     = 44.0
+	legendSwatchSize = 16.0
+	legendEntryGap   = 24.0
+	legendCharWidth  = 8.0
+)
+
+func renderLegend(buf *bytes.Buffer, frameWidth, y float64, entries []styles.LegendEntry) {
+	widths := make([]float64, len(entries))
+	total := 0.0
+	for i, e := range entries {
+		widths[i] = legendSwatchSize + 6 + float64(len(e.Label))*legendCharWidth
+		total += widths[i]
+	}
+	total += legendEntryGap * float64(len(entries)-1)
+
+	x := max((frameWidth-total)/2, 10)
+	cy := y + legendHeight/2
+	buf.WriteString(`  <g class="legend">` + "\n")
+	for i, e := range entries {
+		fmt.Fprintf(buf, `    <rect x="%.1f" y="%.1f" width="%.0f" height="%.0f" fill="%s" stroke="#333" stroke-width="1"/>`+"\n",
+			x, cy-legendSwatchSize/2, legendSwatchSize, legendSwatchSize, e.Color)
+		fmt.Fprintf(buf, `    <text x="%.1f" y="%.1f" dominant-baseline="middle" font-family="%s" font-size="14" fill="#333">%s</text>`+"\n",
+			x+legendSwatchSize+6, cy, fontFamily, styles.EscapeXML(e.Label))
+		x += widths[i] + legendEntryGap
+	}
+	buf.WriteString("  </g>\n")
+}
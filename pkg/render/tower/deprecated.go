@@ -0,0 +1,36 @@
+package tower
+
+import (
+	"strings"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+)
+
+func IsDeprecated(n *dag.Node) bool {
+	return Deprecation(n) != ""
+}
+
+// Deprecation describes why the registry discourages the resolved version:
+// an npm deprecation, a PyPI or crates.io yank, or a Packagist abandonment.
+// It is empty for packages in good standing.
+func Deprecation(n *dag.Node) string {
+	if n == nil || n.Meta == nil {
+		return ""
+	}
+	if msg, _ := n.Meta["deprecated"].(string); msg != "" {
+		return "deprecated: " + strings.Join(strings.Fields(msg), " ")
+	}
+	if yanked, _ := n.Meta["yanked"].(bool); yanked {
+		if reason, _ := n.Meta["yanked_reason"].(string); reason != "" {
+			return "yanked: " + reason
+		}
+		return "yanked"
+	}
+	if abandoned, _ := n.Meta["abandoned"].(bool); abandoned {
+		if repl, _ := n.Meta["replaced_by"].(string); repl != "" {
+			return "abandoned, use " + repl
+		}
+		return "abandoned"
+	}
+	return ""
+}
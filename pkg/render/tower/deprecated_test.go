@@ -0,0 +1,36 @@
+package tower
+
+import (
+	"testing"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+)
+
+func TestDeprecation(t *testing.T) {
+	cases := []struct {
+		name string
+		meta dag.Metadata
+		want string
+	}{
+		{"good standing", dag.Metadata{"version": "1.0.0"}, ""},
+		{"npm", dag.Metadata{"deprecated": "no longer\n  maintained"}, "deprecated: no longer maintained"},
+		{"yanked", dag.Metadata{"yanked": true}, "yanked"},
+		{"yanked with reason", dag.Metadata{"yanked": true, "yanked_reason": "broken wheel"}, "yanked: broken wheel"},
+		{"abandoned", dag.Metadata{"abandoned": true}, "abandoned"},
+		{"abandoned with replacement", dag.Metadata{"abandoned": true, "replaced_by": "guzzlehttp/guzzle"}, "abandoned, use guzzlehttp/guzzle"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			n := &dag.Node{ID: "pkg", Meta: tc.meta}
+			if got := Deprecation(n); got != tc.want {
+				t.Errorf("Deprecation() = %q, want %q", got, tc.want)
+			}
+			if IsDeprecated(n) != (tc.want != "") {
+				t.Errorf("IsDeprecated() = %v", IsDeprecated(n))
+			}
+		})
+	}
+	if IsDeprecated(nil) {
+		t.Error("expected nil node to be in good standing")
+	}
+}
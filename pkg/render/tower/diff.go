@@ -0,0 +1,43 @@
+package tower
+
+import (
+	"github.com/matzehuels/stacktower/pkg/dag"
+	"github.com/matzehuels/stacktower/pkg/render/tower/styles"
+)
+
+var diffColors = map[dag.Change]string{
+	dag.Added:   "#74c69d",
+	dag.Changed: "#ffd166",
+	dag.Removed: "#f2f2f2",
+}
+
+// DiffColors colors the blocks of a dag.Overlay graph by how they changed
+// between the two snapshots. Unchanged blocks keep the style's fill.
+type DiffColors struct{}
+
+func (DiffColors) Color(n *dag.Node) (string, bool) {
+	if n.IsSynthetic() {
+		return "", false
+	}
+	color, ok := diffColors[dag.ChangeOf(n.Meta)]
+	return color, ok
+}
+
+func (DiffColors) Legend() []styles.LegendEntry {
+	return []styles.LegendEntry{
+		{Label: "added", Color: diffColors[dag.Added]},
+		{Label: "changed", Color: diffColors[dag.Changed]},
+		{Label: "removed", Color: diffColors[dag.Removed]},
+	}
+}
+
+// IsRemoved reports whether n, or the node it subdivides, only exists in
+// the older snapshot of a dag.Overlay graph.
+func IsRemoved(g *dag.DAG, n *dag.Node) bool {
+	if n.MasterID != "" {
+		if master, ok := g.Node(n.MasterID); ok {
+			n = master
+		}
+	}
+	return dag.ChangeOf(n.Meta) == dag.Removed
+}
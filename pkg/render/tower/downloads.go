@@ -0,0 +1,70 @@
+package tower
+
+import (
+	"math"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+	"github.com/matzehuels/stacktower/pkg/render/tower/styles"
+)
+
+// Downloads returns the node's download count, preferring the monthly
+// figure over an all-time total.
+func Downloads(n *dag.Node) (int, bool) {
+	if n == nil || n.Meta == nil {
+		return 0, false
+	}
+	for _, key := range []string{"downloads_monthly", "downloads"} {
+		if v, ok := n.Meta[key]; ok {
+			return asInt(v), true
+		}
+	}
+	return 0, false
+}
+
+// DownloadWeight scales width logarithmically with downloads, so a package
+// with a million downloads is about twice as wide as one with a thousand.
+// Nodes without counts get the weight of a package with none.
+func DownloadWeight(n *dag.Node) float64 {
+	d, _ := Downloads(n)
+	return 1 + math.Log10(1+float64(max(d, 0)))
+}
+
+var downloadBuckets = []struct {
+	min   int
+	label string
+	color string
+}{
+	{10_000_000, "10M+", "#08306b"},
+	{1_000_000, "1M+", "#2171b5"},
+	{100_000, "100k+", "#6baed6"},
+	{10_000, "10k+", "#9ecae1"},
+	{1_000, "1k+", "#c6dbef"},
+	{0, "<1k", "#eff3ff"},
+}
+
+// DownloadColors shades blocks by order of magnitude of downloads.
+type DownloadColors struct{}
+
+func (DownloadColors) Color(n *dag.Node) (string, bool) {
+	if n.IsSynthetic() {
+		return "", false
+	}
+	d, ok := Downloads(n)
+	if !ok {
+		return unknownColor, true
+	}
+	for _, b := range downloadBuckets {
+		if d >= b.min {
+			return b.color, true
+		}
+	}
+	return unknownColor, true
+}
+
+func (DownloadColors) Legend() []styles.LegendEntry {
+	entries := make([]styles.LegendEntry, 0, len(downloadBuckets)+1)
+	for i := len(downloadBuckets) - 1; i >= 0; i-- {
+		entries = append(entries, styles.LegendEntry{Label: downloadBuckets[i].label, Color: downloadBuckets[i].color})
+	}
+	return append(entries, styles.LegendEntry{Label: "unknown", Color: unknownColor})
+}
@@ -0,0 +1,90 @@
+package tower
+
+import (
+	"math"
+	"testing"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+)
+
+func TestDownloads(t *testing.T) {
+	cases := []struct {
+		name string
+		meta dag.Metadata
+		want int
+		ok   bool
+	}{
+		{"none", nil, 0, false},
+		{"total", dag.Metadata{"downloads": 500}, 500, true},
+		{"monthly preferred", dag.Metadata{"downloads": 500, "downloads_monthly": 40}, 40, true},
+		{"from json", dag.Metadata{"downloads_monthly": float64(7)}, 7, true},
+	}
+	for _, c := range cases {
+		got, ok := Downloads(&dag.Node{ID: "pkg", Meta: c.meta})
+		if got != c.want || ok != c.ok {
+			t.Errorf("%s: Downloads = %d, %v; want %d, %v", c.name, got, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestDownloadColors(t *testing.T) {
+	scale := DownloadColors{}
+	big, _ := scale.Color(&dag.Node{ID: "a", Meta: dag.Metadata{"downloads": 20_000_000}})
+	small, _ := scale.Color(&dag.Node{ID: "b", Meta: dag.Metadata{"downloads": 10}})
+	unknown, _ := scale.Color(&dag.Node{ID: "c"})
+
+	if big != "#08306b" || small != "#eff3ff" || unknown != unknownColor {
+		t.Errorf("unexpected colors %s %s %s", big, small, unknown)
+	}
+	if _, ok := scale.Color(&dag.Node{ID: "s", Kind: dag.NodeKindSubdivider}); ok {
+		t.Error("subdividers should keep the default fill")
+	}
+	if got := scale.Legend(); len(got) != 7 || got[0].Label != "<1k" {
+		t.Errorf("unexpected legend %+v", got)
+	}
+}
+
+func TestBuild_WidthWeights(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "app", Row: 0})
+	g.AddNode(dag.Node{ID: "popular", Row: 1, Meta: dag.Metadata{"downloads": 999_999}})
+	g.AddNode(dag.Node{ID: "niche", Row: 1, Meta: dag.Metadata{"downloads": 9}})
+	g.AddEdge(dag.Edge{From: "app", To: "popular"})
+	g.AddEdge(dag.Edge{From: "app", To: "niche"})
+
+	l := Build(g, 100, 100, WithMarginRatio(0), WithWidthWeights(DownloadWeight))
+
+	popular, niche := l.Blocks["popular"].Width(), l.Blocks["niche"].Width()
+	if math.Abs(popular/niche-3.5) > 1e-6 {
+		t.Errorf("expected width ratio 7:2, got %.2f / %.2f", popular, niche)
+	}
+	if math.Abs(popular+niche-100) > 1e-6 {
+		t.Errorf("weighted row should still fill the frame, got %.2f", popular+niche)
+	}
+}
+
+func TestBuild_WidthWeightsEveryRow(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "app", Row: 0})
+	g.AddNode(dag.Node{ID: "popular", Row: 1, Meta: dag.Metadata{"downloads": 999_999}})
+	g.AddNode(dag.Node{ID: "niche", Row: 1, Meta: dag.Metadata{"downloads": 9}})
+	g.AddNode(dag.Node{ID: "leaf", Row: 2})
+	g.AddEdge(dag.Edge{From: "app", To: "popular"})
+	g.AddEdge(dag.Edge{From: "app", To: "niche"})
+	g.AddEdge(dag.Edge{From: "popular", To: "leaf"})
+	g.AddEdge(dag.Edge{From: "niche", To: "leaf"})
+
+	// A single root and a single leaf: only the middle row can differ, in
+	// either flow direction.
+	for _, opts := range [][]Option{{}, {WithTopDownWidths()}} {
+		opts = append(opts, WithMarginRatio(0), WithWidthWeights(DownloadWeight))
+		l := Build(g, 100, 100, opts...)
+		popular, niche := l.Blocks["popular"].Width(), l.Blocks["niche"].Width()
+		if math.Abs(popular/niche-3.5) > 1e-6 {
+			t.Errorf("expected width ratio 7:2, got %.2f / %.2f", popular, niche)
+		}
+		if math.Abs(l.Blocks["app"].Width()-100) > 1e-6 {
+			t.Errorf("lone root should fill the frame, got %.2f", l.Blocks["app"].Width())
+		}
+	}
+}
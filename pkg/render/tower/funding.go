@@ -0,0 +1,56 @@
+package tower
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+)
+
+// Funding returns the node's funding links: the repository's Sponsors page
+// and FUNDING.yml entries ahead of those declared in the registry. Links
+// that are not http(s) are skipped, since graphs may come from anywhere and
+// the links become hrefs.
+func Funding(n *dag.Node) []string {
+	if n == nil || n.Meta == nil {
+		return nil
+	}
+	var urls []string
+	for _, key := range []string{"repo_funding", "funding"} {
+		for _, u := range getStringSlice(n.Meta[key]) {
+			if isWebURL(u) && !slices.Contains(urls, u) {
+				urls = append(urls, u)
+			}
+		}
+	}
+	return urls
+}
+
+func isWebURL(u string) bool {
+	parsed, err := url.Parse(u)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// sortFunding puts the maintainer's own Sponsors page first and orders the
+// remaining links alphabetically.
+func sortFunding(urls []string, maintainer string) {
+	own := "github.com/sponsors/" + strings.ToLower(maintainer)
+	isOwn := func(u string) bool { return strings.HasSuffix(strings.ToLower(u), own) }
+	slices.SortFunc(urls, func(a, b string) int {
+		switch {
+		case isOwn(a) && !isOwn(b):
+			return -1
+		case isOwn(b) && !isOwn(a):
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+}
+
+// displayURL shortens a link for display by dropping the scheme and any
+// trailing slash.
+func displayURL(u string) string {
+	u = strings.TrimPrefix(strings.TrimPrefix(u, "https://"), "http://")
+	return strings.TrimSuffix(strings.TrimPrefix(u, "www."), "/")
+}
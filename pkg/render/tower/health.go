@@ -0,0 +1,44 @@
+package tower
+
+import (
+	"github.com/matzehuels/stacktower/pkg/dag"
+	"github.com/matzehuels/stacktower/pkg/health"
+	"github.com/matzehuels/stacktower/pkg/render/tower/styles"
+)
+
+var gradeColors = map[health.Grade]string{
+	health.GradeA: "#1a9850",
+	health.GradeB: "#91cf60",
+	health.GradeC: "#fee08b",
+	health.GradeD: "#fc8d59",
+	health.GradeF: "#d73027",
+}
+
+// HealthColors colors blocks by maintenance-health grade. The zero value
+// uses health.DefaultPolicy.
+type HealthColors struct {
+	Policy health.Policy
+}
+
+func (h HealthColors) Color(n *dag.Node) (string, bool) {
+	if n.IsSynthetic() {
+		return "", false
+	}
+	p := h.Policy
+	if p == (health.Policy{}) {
+		p = health.DefaultPolicy()
+	}
+	r, ok := p.Score(n)
+	if !ok {
+		return unknownColor, true
+	}
+	return gradeColors[r.Grade], true
+}
+
+func (HealthColors) Legend() []styles.LegendEntry {
+	entries := make([]styles.LegendEntry, 0, len(health.Grades)+1)
+	for _, g := range health.Grades {
+		entries = append(entries, styles.LegendEntry{Label: string(g), Color: gradeColors[g]})
+	}
+	return append(entries, styles.LegendEntry{Label: "unknown", Color: unknownColor})
+}
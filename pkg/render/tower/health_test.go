@@ -0,0 +1,25 @@
+package tower
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+)
+
+func TestHealthColors(t *testing.T) {
+	scale := HealthColors{}
+	fresh, _ := scale.Color(&dag.Node{ID: "a", Meta: dag.Metadata{"repo_last_commit": time.Now().Format("2006-01-02")}})
+	archived, _ := scale.Color(&dag.Node{ID: "b", Meta: dag.Metadata{"repo_archived": true}})
+	unknown, _ := scale.Color(&dag.Node{ID: "c"})
+
+	if fresh != "#1a9850" || archived != "#d73027" || unknown != unknownColor {
+		t.Errorf("unexpected colors %s %s %s", fresh, archived, unknown)
+	}
+	if _, ok := scale.Color(&dag.Node{ID: "s", Kind: dag.NodeKindSubdivider}); ok {
+		t.Error("subdividers should keep the default fill")
+	}
+	if got := scale.Legend(); len(got) != 6 || got[0].Label != "A" {
+		t.Errorf("unexpected legend %+v", got)
+	}
+}
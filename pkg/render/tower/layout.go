@@ -4,6 +4,7 @@ import (
 	"slices"
 
 	"github.com/matzehuels/stacktower/pkg/dag"
+	"github.com/matzehuels/stacktower/pkg/dag/transform"
 	"github.com/matzehuels/stacktower/pkg/render/tower/ordering"
 )
 
@@ -22,10 +23,19 @@ type Layout struct {
 type Option func(*config)
 
 type config struct {
+	layerer     transform.Layerer
 	orderer     ordering.Orderer
 	auxRatio    float64
 	marginRatio float64
 	topDownFlow bool
+	widthWeight WidthWeight
+}
+
+// WithLayering has Build normalize the graph first, assigning rows with l.
+// The graph is changed in place, as by transform.NormalizeWith, so pass one
+// that is not normalized yet.
+func WithLayering(l transform.Layerer) Option {
+	return func(c *config) { c.layerer = l }
 }
 
 func WithOrderer(o ordering.Orderer) Option {
@@ -44,6 +54,10 @@ func WithTopDownWidths() Option {
 	return func(c *config) { c.topDownFlow = true }
 }
 
+func WithWidthWeights(w WidthWeight) Option {
+	return func(c *config) { c.widthWeight = w }
+}
+
 func Build(g *dag.DAG, width, height float64, opts ...Option) Layout {
 	cfg := config{
 		orderer:     ordering.Barycentric{},
@@ -53,6 +67,9 @@ func Build(g *dag.DAG, width, height float64, opts ...Option) Layout {
 	for _, opt := range opts {
 		opt(&cfg)
 	}
+	if cfg.layerer != nil {
+		transform.NormalizeWith(g, cfg.layerer)
+	}
 
 	marginX := width * cfg.marginRatio
 	marginY := height * cfg.marginRatio
@@ -60,9 +77,9 @@ func Build(g *dag.DAG, width, height float64, opts ...Option) Layout {
 	orders := cfg.orderer.OrderRows(g)
 	var widths map[string]float64
 	if cfg.topDownFlow {
-		widths = ComputeWidths(g, orders, width-2*marginX)
+		widths = computeWidths(g, orders, width-2*marginX, cfg.widthWeight)
 	} else {
-		widths = ComputeWidthsBottomUp(g, orders, width-2*marginX)
+		widths = computeWidthsBottomUp(g, orders, width-2*marginX, cfg.widthWeight)
 	}
 	heights := computeRowHeights(g, height-2*marginY, cfg.auxRatio)
 	bottoms := computeRowBottoms(heights)
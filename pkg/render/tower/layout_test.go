@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/matzehuels/stacktower/pkg/dag"
+	"github.com/matzehuels/stacktower/pkg/dag/transform"
 )
 
 func TestBlock(t *testing.T) {
@@ -360,6 +361,37 @@ func TestBuild_WithOptions(t *testing.T) {
 	}
 }
 
+func TestBuild_WithLayering(t *testing.T) {
+	build := func(l transform.Layerer) (*dag.DAG, Layout) {
+		g := dag.New(nil)
+		for _, id := range []string{"A", "B", "C", "X"} {
+			_ = g.AddNode(dag.Node{ID: id})
+		}
+		_ = g.AddEdge(dag.Edge{From: "A", To: "B"})
+		_ = g.AddEdge(dag.Edge{From: "B", To: "C"})
+		_ = g.AddEdge(dag.Edge{From: "X", To: "C"})
+		return g, Build(g, 100, 100, WithLayering(l))
+	}
+
+	// Longest path puts X on the top row, two rows above C, so its edge
+	// needs a subdivider; laying out from the sinks puts X right above C.
+	g, layout := build(transform.LongestPath{})
+	if n, _ := g.Node("X"); n.Row != 0 {
+		t.Errorf("longest path: X in row %d, want 0", n.Row)
+	}
+	if len(layout.Blocks) != 5 {
+		t.Errorf("longest path: want 5 blocks, got %d", len(layout.Blocks))
+	}
+
+	g, layout = build(transform.LongestPathFromSinks{})
+	if n, _ := g.Node("X"); n.Row != 1 {
+		t.Errorf("from sinks: X in row %d, want 1", n.Row)
+	}
+	if len(layout.Blocks) != 4 {
+		t.Errorf("from sinks: want 4 blocks, got %d", len(layout.Blocks))
+	}
+}
+
 func TestBuild_WithMargins(t *testing.T) {
 	g := dag.New(nil)
 	_ = g.AddNode(dag.Node{ID: "A", Row: 0})
@@ -0,0 +1,69 @@
+package tower
+
+import (
+	"math"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+	"github.com/matzehuels/stacktower/pkg/libyear"
+	"github.com/matzehuels/stacktower/pkg/render/tower/styles"
+)
+
+type yearBucket struct {
+	max   float64
+	label string
+	color string
+}
+
+var ageBuckets = []yearBucket{
+	{0, "up to date", "#1a9850"},
+	{1, "< 1 year", "#a6d96a"},
+	{2, "1–2 years", "#fee08b"},
+	{4, "2–4 years", "#fc8d59"},
+	{math.Inf(1), "4+ years", "#d73027"},
+}
+
+// LibyearColors colors blocks by how many libyears their version trails the
+// newest release, so the stalest parts of the stack stand out. With Age set
+// it colors by how long ago the version was released instead, which is what
+// differs on graphs parsed straight from a registry: those resolve each
+// package's newest release and so never trail.
+type LibyearColors struct {
+	Age bool
+}
+
+func (c LibyearColors) Color(n *dag.Node) (string, bool) {
+	if n.IsSynthetic() {
+		return "", false
+	}
+	lag, ok := libyear.Of(n)
+	if !ok {
+		return unknownColor, true
+	}
+	years := lag.Libyears
+	if c.Age {
+		years = lag.AgeYears
+	}
+	for _, b := range c.buckets() {
+		if years <= b.max {
+			return b.color, true
+		}
+	}
+	return unknownColor, true
+}
+
+func (c LibyearColors) Legend() []styles.LegendEntry {
+	buckets := c.buckets()
+	entries := make([]styles.LegendEntry, 0, len(buckets)+1)
+	for _, b := range buckets {
+		entries = append(entries, styles.LegendEntry{Label: b.label, Color: b.color})
+	}
+	return append(entries, styles.LegendEntry{Label: "unknown", Color: unknownColor})
+}
+
+// buckets drops "up to date" when coloring by age, where it would never apply.
+func (c LibyearColors) buckets() []yearBucket {
+	if c.Age {
+		return ageBuckets[1:]
+	}
+	return ageBuckets
+}
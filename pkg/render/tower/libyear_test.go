@@ -0,0 +1,43 @@
+package tower
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+)
+
+func TestLibyearColors(t *testing.T) {
+	day := func(years int) string { return time.Now().AddDate(-years, 0, 0).Format(time.DateOnly) }
+	node := func(version string) *dag.Node {
+		return &dag.Node{ID: "pkg", Meta: dag.Metadata{
+			"version":  version,
+			"releases": map[string]string{"1.0": day(6), "2.0": day(3), "3.0": day(0)},
+		}}
+	}
+
+	cases := []struct {
+		name  string
+		scale LibyearColors
+		node  *dag.Node
+		want  string
+	}{
+		{"current", LibyearColors{}, node("3.0"), "#1a9850"},
+		{"three libyears", LibyearColors{}, node("2.0"), "#fc8d59"},
+		{"six libyears", LibyearColors{}, node("1.0"), "#d73027"},
+		{"age of newest", LibyearColors{Age: true}, node("3.0"), "#a6d96a"},
+		{"age of old release", LibyearColors{Age: true}, node("2.0"), "#fc8d59"},
+		{"no history", LibyearColors{}, &dag.Node{ID: "pkg", Meta: dag.Metadata{"version": "1.0"}}, unknownColor},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got, _ := tc.scale.Color(tc.node); got != tc.want {
+				t.Errorf("Color() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+
+	if got := len(LibyearColors{Age: true}.Legend()); got != len(LibyearColors{}.Legend())-1 {
+		t.Errorf("age legend should drop the up-to-date entry, got %d entries", got)
+	}
+}
@@ -0,0 +1,35 @@
+package tower
+
+import (
+	"github.com/matzehuels/stacktower/pkg/dag"
+	"github.com/matzehuels/stacktower/pkg/license"
+	"github.com/matzehuels/stacktower/pkg/render/tower/styles"
+)
+
+var licenseFamilyColors = map[license.Family]string{
+	license.FamilyPublicDomain: "#b7e4c7",
+	license.FamilyPermissive:   "#74c69d",
+	license.FamilyWeakCopyleft: "#ffd166",
+	license.FamilyCopyleft:     "#ef8354",
+	license.FamilyProprietary:  "#b48ead",
+	license.FamilyUnknown:      unknownColor,
+}
+
+// LicenseColors colors blocks by the family of their declared license.
+type LicenseColors struct{}
+
+func (LicenseColors) Color(n *dag.Node) (string, bool) {
+	if n.IsSynthetic() {
+		return "", false
+	}
+	_, family := license.Classify(n)
+	return licenseFamilyColors[family], true
+}
+
+func (LicenseColors) Legend() []styles.LegendEntry {
+	entries := make([]styles.LegendEntry, len(license.Families))
+	for i, f := range license.Families {
+		entries[i] = styles.LegendEntry{Label: string(f), Color: licenseFamilyColors[f]}
+	}
+	return entries
+}
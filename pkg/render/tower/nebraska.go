@@ -25,6 +25,7 @@ type NebraskaRanking struct {
 	Maintainer string
 	Score      float64
 	Packages   []PackageRole
+	Funding    []string // links to fund this maintainer's packages, their own Sponsors page first
 }
 
 const (
@@ -37,6 +38,7 @@ func RankNebraska(g *dag.DAG, topN int) []NebraskaRanking {
 	scores := make(map[string]float64)
 	packages := make(map[string][]PackageRole)
 	bestRole := make(map[string]Role)
+	funding := make(map[string][]string)
 	minRow := findMinRow(g)
 
 	for _, n := range g.Nodes() {
@@ -64,6 +66,12 @@ func RankNebraska(g *dag.DAG, topN int) []NebraskaRanking {
 				})
 			}
 
+			for _, u := range Funding(n) {
+				if !slices.Contains(funding[maintainer], u) {
+					funding[maintainer] = append(funding[maintainer], u)
+				}
+			}
+
 			if roleRank(role) < roleRank(bestRole[maintainer]) {
 				bestRole[maintainer] = role
 			}
@@ -76,10 +84,12 @@ func RankNebraska(g *dag.DAG, topN int) []NebraskaRanking {
 		slices.SortFunc(pkgs, func(a, b PackageRole) int {
 			return cmp.Compare(a.Package, b.Package)
 		})
+		sortFunding(funding[m], m)
 		rankings = append(rankings, NebraskaRanking{
 			Maintainer: m,
 			Score:      score,
 			Packages:   pkgs,
+			Funding:    funding[m],
 		})
 	}
 
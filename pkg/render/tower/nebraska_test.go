@@ -1,6 +1,7 @@
 package tower
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/matzehuels/stacktower/pkg/dag"
@@ -195,3 +196,28 @@ func TestRankNebraska_NoMetadata(t *testing.T) {
 		t.Errorf("expected empty rankings, got %d", len(rankings))
 	}
 }
+
+func TestRankNebraska_Funding(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "root", Row: 0})
+	_ = g.AddNode(dag.Node{ID: "a", Row: 1, Meta: dag.Metadata{
+		"repo_maintainers": []string{"alice"},
+		"repo_funding":     []string{"https://opencollective.com/a"},
+		"funding":          []any{"https://github.com/sponsors/Alice", "https://opencollective.com/a", "javascript:alert(1)"},
+	}})
+	_ = g.AddNode(dag.Node{ID: "b", Row: 1, Meta: dag.Metadata{
+		"repo_maintainers": []string{"alice"},
+		"repo_funding":     []string{"https://github.com/sponsors/b-org"},
+	}})
+	_ = g.AddEdge(dag.Edge{From: "root", To: "a"})
+	_ = g.AddEdge(dag.Edge{From: "root", To: "b"})
+
+	rankings := RankNebraska(g, 5)
+	if len(rankings) != 1 {
+		t.Fatalf("expected 1 ranking, got %d", len(rankings))
+	}
+	want := []string{"https://github.com/sponsors/Alice", "https://github.com/sponsors/b-org", "https://opencollective.com/a"}
+	if got := rankings[0].Funding; !slices.Equal(got, want) {
+		t.Errorf("got funding %v, want %v", got, want)
+	}
+}
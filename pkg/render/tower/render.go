@@ -20,6 +20,7 @@ type renderer struct {
 	merged    bool
 	nebraska  []NebraskaRanking
 	popups    bool
+	colors    ColorScale
 }
 
 func WithGraph(g *dag.DAG) RenderOption     { return func(r *renderer) { r.graph = g } }
@@ -50,7 +51,7 @@ func RenderSVG(layout Layout, opts ...RenderOption) []byte {
 		opt(&r)
 	}
 
-	blocks := buildBlocks(layout, r.graph, r.popups)
+	blocks := buildBlocks(layout, r.graph, r.popups, r.colors)
 	slices.SortFunc(blocks, func(a, b styles.Block) int {
 		return cmp.Compare(a.ID, b.ID)
 	})
@@ -59,8 +60,18 @@ func RenderSVG(layout Layout, opts ...RenderOption) []byte {
 	if r.showEdges {
 		edges = buildEdges(layout, r.graph, r.merged)
 	}
+	edges = append(edges, buildBackEdges(layout, r.graph)...)
 
-	totalHeight := layout.FrameHeight
+	var legend []styles.LegendEntry
+	if r.colors != nil && r.graph != nil {
+		legend = r.colors.Legend()
+	}
+
+	panelTop := layout.FrameHeight
+	if len(legend) > 0 {
+		panelTop += legendHeight
+	}
+	totalHeight := panelTop
 	if len(r.nebraska) > 0 {
 		totalHeight += calcNebraskaPanelHeight(layout.FrameWidth, layout.FrameHeight)
 	}
@@ -86,8 +97,12 @@ func RenderSVG(layout Layout, opts ...RenderOption) []byte {
 		r.style.RenderText(&buf, b)
 	}
 
+	if len(legend) > 0 {
+		renderLegend(&buf, layout.FrameWidth, layout.FrameHeight, legend)
+	}
+
 	if len(r.nebraska) > 0 {
-		renderNebraskaPanel(&buf, layout.FrameWidth, layout.FrameHeight, r.nebraska)
+		renderNebraskaPanel(&buf, layout.FrameWidth, layout.FrameHeight, panelTop, r.nebraska)
 		renderNebraskaScript(&buf)
 	}
 
@@ -110,8 +125,8 @@ const (
 	nebraskaEntryHeight  = 120.0
 )
 
-func renderNebraskaPanel(buf *bytes.Buffer, frameWidth, frameHeight float64, rankings []NebraskaRanking) {
-	panelY := frameHeight + nebraskaPanelPadding
+func renderNebraskaPanel(buf *bytes.Buffer, frameWidth, frameHeight, top float64, rankings []NebraskaRanking) {
+	panelY := top + nebraskaPanelPadding
 	centerX := frameWidth / 2
 
 	fmt.Fprintf(buf, `  <text x="%.1f" y="%.1f" text-anchor="middle" font-family="%s" font-size="30" fill="#333" font-weight="bold">Nebraska Guy Ranking</text>`+"\n",
@@ -162,6 +177,10 @@ func renderNebraskaEntry(buf *bytes.Buffer, r NebraskaRanking, idx int, x, y, wi
 	fmt.Fprintf(buf, `    <div xmlns="http://www.w3.org/1999/xhtml" class="nebraska-entry">`+"\n")
 	fmt.Fprintf(buf, `      <a href="https://github.com/%s" target="_blank" class="maintainer-name" data-packages="%s">#%d @%s</a>`+"\n",
 		r.Maintainer, styles.EscapeXML(strings.Join(pkgIDs, ",")), idx+1, styles.EscapeXML(r.Maintainer))
+	if len(r.Funding) > 0 {
+		fmt.Fprintf(buf, `      <a href="%s" target="_blank" class="fund" title="%s">♥ %s</a>`+"\n",
+			styles.EscapeXML(r.Funding[0]), styles.EscapeXML(strings.Join(r.Funding, " ")), styles.EscapeXML(displayURL(r.Funding[0])))
+	}
 	buf.WriteString(`      <div class="packages">` + "\n")
 	for j, p := range r.Packages {
 		if j >= 3 {
@@ -194,6 +213,17 @@ const nebraskaCSS = `
       margin-bottom: 8px;
     }
     .nebraska-entry .maintainer-name:hover { text-decoration: underline; }
+    .nebraska-entry .fund {
+      display: block;
+      font-size: 15px;
+      color: #c0392b;
+      text-decoration: none;
+      white-space: nowrap;
+      overflow: hidden;
+      text-overflow: ellipsis;
+      margin: -4px 0 4px;
+    }
+    .nebraska-entry .fund:hover { text-decoration: underline; }
     .nebraska-entry .packages {
       font-size: 16px;
       color: #888;
@@ -263,7 +293,7 @@ func renderPopupScript(buf *bytes.Buffer) {
 	fmt.Fprintf(buf, "  <script type=\"text/javascript\"><![CDATA[%s\n  ]]></script>\n", popupJS)
 }
 
-func buildBlocks(l Layout, g *dag.DAG, withPopups bool) []styles.Block {
+func buildBlocks(l Layout, g *dag.DAG, withPopups bool, colors ColorScale) []styles.Block {
 	blocks := make([]styles.Block, 0, len(l.Blocks))
 	for id, b := range l.Blocks {
 		blk := styles.Block{
@@ -272,12 +302,22 @@ func buildBlocks(l Layout, g *dag.DAG, withPopups bool) []styles.Block {
 			W: b.Width(), H: b.Height(),
 			CX: b.CenterX(), CY: b.CenterY(),
 		}
+		if g != nil && colors != nil {
+			if n, ok := g.Node(id); ok {
+				blk.Fill, _ = colors.Color(n)
+			}
+		}
 		if g != nil {
-			if n, ok := g.Node(id); ok && n.Meta != nil {
-				blk.URL, _ = n.Meta["repo_url"].(string)
-				blk.Brittle = IsBrittle(n)
-				if withPopups {
-					blk.Popup = extractPopupData(n)
+			if n, ok := g.Node(id); ok {
+				blk.Ghost = IsRemoved(g, n)
+				if n.Meta != nil {
+					blk.URL, _ = n.Meta["repo_url"].(string)
+					blk.Brittle = IsBrittle(n)
+					blk.Vulnerable = IsVulnerable(n)
+					blk.Deprecated = IsDeprecated(n)
+					if withPopups {
+						blk.Popup = extractPopupData(n)
+					}
 				}
 			}
 		}
@@ -294,10 +334,17 @@ func extractPopupData(n *dag.Node) *styles.PopupData {
 		Stars:       asInt(n.Meta["repo_stars"]),
 		Maintainers: countMaintainers(n.Meta["repo_maintainers"]),
 		Brittle:     IsBrittle(n),
+		Advisories:  Advisories(n),
 	}
 	p.LastCommit, _ = n.Meta["repo_last_commit"].(string)
 	p.LastRelease, _ = n.Meta["repo_last_release"].(string)
 	p.Archived, _ = n.Meta["repo_archived"].(bool)
+	p.Scorecard, _ = Scorecard(n)
+	p.Funding = Funding(n)
+	p.Deprecation = Deprecation(n)
+	if _, ok := n.Meta[dag.MembersKey]; ok {
+		p.Members = dag.Members(n)
+	}
 
 	if desc, ok := n.Meta["description"].(string); ok && desc != "" {
 		p.Description = desc
@@ -328,6 +375,29 @@ func buildEdges(l Layout, g *dag.DAG, merged bool) []styles.Edge {
 	return buildSimpleEdges(l, g)
 }
 
+// buildBackEdges draws the edges removed to break cycles, which the stacking
+// can't show, whether or not the other edges are drawn.
+func buildBackEdges(l Layout, g *dag.DAG) []styles.Edge {
+	if g == nil {
+		return nil
+	}
+	var edges []styles.Edge
+	for _, e := range g.BackEdges() {
+		src, okS := l.Blocks[e.From]
+		dst, okD := l.Blocks[e.To]
+		if !okS || !okD || e.From == e.To {
+			continue
+		}
+		edges = append(edges, styles.Edge{
+			FromID: e.From, ToID: e.To,
+			X1: src.CenterX(), Y1: src.CenterY(),
+			X2: dst.CenterX(), Y2: dst.CenterY(),
+			Back: true,
+		})
+	}
+	return edges
+}
+
 func buildSimpleEdges(l Layout, g *dag.DAG) []styles.Edge {
 	edges := make([]styles.Edge, 0, len(g.Edges()))
 	for _, e := range g.Edges() {
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/matzehuels/stacktower/pkg/dag"
+	"github.com/matzehuels/stacktower/pkg/render/tower/styles/handdrawn"
 )
 
 func TestRenderSVG_Simple(t *testing.T) {
@@ -168,3 +169,122 @@ func TestRenderSVG_MergedDeduplicatesEdgesToSameMaster(t *testing.T) {
 		t.Errorf("Expected 3 edges (A→C, B→C, C→D), got %d", lineCount)
 	}
 }
+
+func TestRenderSVG_HighlightsVulnerableBlocks(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "A", Row: 0})
+	g.AddNode(dag.Node{ID: "B", Row: 1, Meta: dag.Metadata{
+		"vulnerabilities": []map[string]any{{"id": "GHSA-xxxx", "severity": "HIGH", "fixed": []string{"1.2.3"}}},
+	}})
+	g.AddEdge(dag.Edge{From: "A", To: "B"})
+
+	layout := Build(g, 100, 100)
+	svg := string(RenderSVG(layout, WithGraph(g), WithStyle(handdrawn.New(1)), WithPopups()))
+
+	if !strings.Contains(svg, `id="block-B" class="block vulnerable"`) {
+		t.Error("vulnerable block should carry the vulnerable class")
+	}
+	if strings.Contains(svg, `id="block-A" class="block vulnerable"`) {
+		t.Error("safe block should not be marked vulnerable")
+	}
+	if !strings.Contains(svg, "GHSA-xxxx (high) fixed in 1.2.3") {
+		t.Error("popup should list the advisory")
+	}
+}
+
+func TestRenderSVG_StrikesDeprecatedBlocks(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "A", Row: 0})
+	g.AddNode(dag.Node{ID: "B", Row: 1, Meta: dag.Metadata{"deprecated": "use C instead"}})
+	g.AddEdge(dag.Edge{From: "A", To: "B"})
+
+	layout := Build(g, 100, 100)
+	svg := string(RenderSVG(layout, WithGraph(g), WithStyle(handdrawn.New(1)), WithPopups()))
+
+	if !strings.Contains(svg, `id="block-B" class="block brittle deprecated"`) {
+		t.Error("deprecated block should be brittle and carry the deprecated class")
+	}
+	if strings.Count(svg, `class="strike"`) != 1 {
+		t.Error("only the deprecated block's label should be struck through")
+	}
+	if !strings.Contains(svg, "⊘ deprecated: use C instead") {
+		t.Error("popup should show the deprecation message")
+	}
+}
+
+func TestRenderSVG_ListsGroupMembers(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "A", Row: 0})
+	g.AddNode(dag.Node{ID: "@babel/*", Row: 1, Meta: dag.Metadata{dag.MembersKey: []any{"@babel/cli", "@babel/core"}}})
+	g.AddEdge(dag.Edge{From: "A", To: "@babel/*"})
+
+	layout := Build(g, 100, 100)
+	svg := string(RenderSVG(layout, WithGraph(g), WithStyle(handdrawn.New(1)), WithPopups()))
+
+	if !strings.Contains(svg, "2 packages: @babel/cli, @babel/core") {
+		t.Error("popup should list the group's members")
+	}
+}
+
+func TestRenderSVG_GhostsRemovedBlocks(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "A", Row: 0})
+	g.AddNode(dag.Node{ID: "B", Row: 1, Meta: dag.Metadata{dag.DiffKey: "added"}})
+	g.AddNode(dag.Node{ID: "C", Row: 1, Meta: dag.Metadata{dag.DiffKey: "removed"}})
+	g.AddEdge(dag.Edge{From: "A", To: "B"})
+	g.AddEdge(dag.Edge{From: "A", To: "C"})
+
+	layout := Build(g, 100, 100)
+	svg := string(RenderSVG(layout, WithGraph(g), WithColorScale(DiffColors{})))
+
+	if !strings.Contains(svg, `id="block-C" class="block ghost"`) || strings.Count(svg, `opacity="0.4"`) != 2 {
+		t.Error("removed block and its label should be ghosted")
+	}
+	if strings.Contains(svg, `id="block-B" class="block ghost"`) {
+		t.Error("added block should not be ghosted")
+	}
+	if !strings.Contains(svg, `fill="`+diffColors[dag.Added]+`"`) {
+		t.Error("added block should be highlighted")
+	}
+}
+
+func TestRenderSVG_DrawsBackEdges(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "A", Row: 0})
+	g.AddNode(dag.Node{ID: "B", Row: 1})
+	g.AddEdge(dag.Edge{From: "A", To: "B"})
+	g.Meta()[dag.BackEdgesKey] = []dag.Edge{{From: "B", To: "A"}}
+
+	layout := Build(g, 100, 100)
+	svg := string(RenderSVG(layout, WithGraph(g)))
+
+	if strings.Count(svg, `class="back-edge"`) != 1 {
+		t.Error("back edges should be drawn even without WithEdges")
+	}
+	if strings.Contains(svg, `stroke-dasharray="6,4"`) {
+		t.Error("regular edges should still be hidden")
+	}
+}
+
+func TestRenderSVG_ColorScaleAddsLegend(t *testing.T) {
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "A", Row: 0, Meta: dag.Metadata{"license": "MIT"}})
+	g.AddNode(dag.Node{ID: "B", Row: 1, Meta: dag.Metadata{"license": "GPL-3.0-only"}})
+	g.AddEdge(dag.Edge{From: "A", To: "B"})
+
+	layout := Build(g, 100, 100)
+	svg := string(RenderSVG(layout, WithGraph(g), WithColorScale(LicenseColors{})))
+
+	if !strings.Contains(svg, `id="block-A" class="block" x=`) || !strings.Contains(svg, licenseFamilyColors["permissive"]) {
+		t.Error("permissive block should use the permissive color")
+	}
+	if !strings.Contains(svg, licenseFamilyColors["copyleft"]) {
+		t.Error("copyleft block should use the copyleft color")
+	}
+	if !strings.Contains(svg, `class="legend"`) || !strings.Contains(svg, ">weak-copyleft</text>") {
+		t.Error("color scale should render a legend")
+	}
+	if !strings.Contains(svg, `viewBox="0 0 100.0 144.0"`) {
+		t.Error("legend should extend the frame height")
+	}
+}
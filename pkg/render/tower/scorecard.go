@@ -0,0 +1,99 @@
+package tower
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+	"github.com/matzehuels/stacktower/pkg/render/tower/styles"
+)
+
+// weakCheckScore is the Scorecard check score below which a practice is
+// reported as weak.
+const weakCheckScore = 5
+
+// Scorecard returns the node's OpenSSF Scorecard summary: the aggregate
+// score and its weak checks, weakest first.
+func Scorecard(n *dag.Node) (*styles.Scorecard, bool) {
+	if n == nil || n.Meta == nil {
+		return nil, false
+	}
+	score, ok := n.Meta["scorecard_score"].(float64)
+	if !ok {
+		if i, isInt := n.Meta["scorecard_score"].(int); isInt {
+			score, ok = float64(i), true
+		}
+	}
+	if !ok {
+		return nil, false
+	}
+
+	type check struct {
+		name  string
+		score int
+	}
+	var weak []check
+	add := func(name string, v any) {
+		if s := asInt(v); s < weakCheckScore {
+			weak = append(weak, check{name, s})
+		}
+	}
+	switch checks := n.Meta["scorecard_checks"].(type) {
+	case map[string]int:
+		for name, s := range checks {
+			add(name, s)
+		}
+	case map[string]any:
+		for name, s := range checks {
+			add(name, s)
+		}
+	}
+	slices.SortFunc(weak, func(a, b check) int {
+		return cmp.Or(cmp.Compare(a.score, b.score), cmp.Compare(a.name, b.name))
+	})
+
+	sc := &styles.Scorecard{Score: score}
+	for _, c := range weak {
+		sc.Weak = append(sc.Weak, c.name)
+	}
+	return sc, true
+}
+
+var scorecardBuckets = []struct {
+	min   float64
+	label string
+	color string
+}{
+	{8, "8–10", "#1a9850"},
+	{6, "6–8", "#a6d96a"},
+	{4, "4–6", "#fdae61"},
+	{0, "<4", "#d73027"},
+}
+
+// ScorecardColors colors blocks by OpenSSF Scorecard score, so that
+// foundational packages with weak security practices stand out.
+type ScorecardColors struct{}
+
+func (ScorecardColors) Color(n *dag.Node) (string, bool) {
+	if n.IsSynthetic() {
+		return "", false
+	}
+	sc, ok := Scorecard(n)
+	if !ok {
+		return unknownColor, true
+	}
+	for _, b := range scorecardBuckets {
+		if sc.Score >= b.min {
+			return b.color, true
+		}
+	}
+	return unknownColor, true
+}
+
+func (ScorecardColors) Legend() []styles.LegendEntry {
+	entries := make([]styles.LegendEntry, 0, len(scorecardBuckets)+1)
+	for i := len(scorecardBuckets) - 1; i >= 0; i-- {
+		entries = append(entries, styles.LegendEntry{Label: scorecardBuckets[i].label, Color: scorecardBuckets[i].color})
+	}
+	return append(entries, styles.LegendEntry{Label: "unknown", Color: unknownColor})
+}
@@ -0,0 +1,48 @@
+package tower
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+)
+
+func TestScorecard(t *testing.T) {
+	native := &dag.Node{ID: "a", Meta: dag.Metadata{
+		"scorecard_score":  3.2,
+		"scorecard_checks": map[string]int{"Code-Review": 2, "Fuzzing": 0, "License": 10, "SAST": 2},
+	}}
+	sc, ok := Scorecard(native)
+	if !ok || sc.Score != 3.2 {
+		t.Fatalf("unexpected scorecard %+v, %v", sc, ok)
+	}
+	if want := []string{"Fuzzing", "Code-Review", "SAST"}; !slices.Equal(sc.Weak, want) {
+		t.Errorf("got weak checks %v, want %v", sc.Weak, want)
+	}
+
+	decoded := &dag.Node{ID: "b", Meta: dag.Metadata{
+		"scorecard_score":  float64(9),
+		"scorecard_checks": map[string]any{"Maintained": float64(10)},
+	}}
+	if sc, ok := Scorecard(decoded); !ok || sc.Score != 9 || len(sc.Weak) != 0 {
+		t.Errorf("unexpected scorecard from JSON %+v", sc)
+	}
+
+	if _, ok := Scorecard(&dag.Node{ID: "c"}); ok {
+		t.Error("expected no scorecard without metadata")
+	}
+}
+
+func TestScorecardColors(t *testing.T) {
+	scale := ScorecardColors{}
+	strong, _ := scale.Color(&dag.Node{ID: "a", Meta: dag.Metadata{"scorecard_score": 8.5}})
+	weak, _ := scale.Color(&dag.Node{ID: "b", Meta: dag.Metadata{"scorecard_score": 2.0}})
+	unknown, _ := scale.Color(&dag.Node{ID: "c"})
+
+	if strong != "#1a9850" || weak != "#d73027" || unknown != unknownColor {
+		t.Errorf("unexpected colors %s %s %s", strong, weak, unknown)
+	}
+	if got := scale.Legend(); len(got) != 5 || got[0].Label != "<4" {
+		t.Errorf("unexpected legend %+v", got)
+	}
+}
@@ -22,6 +22,9 @@ const (
 	warnSymbolShift = 8.0
 	textWidthRatio  = 0.45
 	textHeightRatio = 1.0
+	maxAdvisories   = 4
+	maxMemberLines  = 4
+	vulnColor       = "#c0392b"
 
 	// Font stack: Patrick Hand (Google Fonts), then common casual/handwriting fonts
 	fontFamily = `'Patrick Hand', 'Comic Sans MS', 'Bradley Hand', 'Segoe Script', sans-serif`
@@ -41,22 +44,35 @@ func (h *HandDrawn) RenderDefs(buf *bytes.Buffer) {
 	buf.WriteString(getBrittleTextureDataURI())
 	buf.WriteString(`" x="0" y="0" width="200" height="200" preserveAspectRatio="xMidYMid slice" opacity="0.6"/>
     </pattern>
+    <pattern id="vulnHatch" patternUnits="userSpaceOnUse" width="12" height="12" patternTransform="rotate(45)">
+      <line x1="0" y1="0" x2="0" y2="12" stroke="#c0392b" stroke-width="4" opacity="0.35"/>
+    </pattern>
   </defs>
 `)
 }
 
 func (h *HandDrawn) RenderBlock(buf *bytes.Buffer, b styles.Block) {
-	grey := greyForID(b.ID)
+	grey := fillFor(b)
 	rot := rotationFor(b.ID, b.W, b.H)
 	path := wobbledRect(b.X, b.Y, b.W, b.H, h.seed, b.ID)
 
 	styles.WrapURL(buf, b.URL, func() {
 		class := "block"
 		if b.Brittle {
-			class = "block brittle"
+			class += " brittle"
+		}
+		if b.Vulnerable {
+			class += " vulnerable"
+		}
+		if b.Deprecated {
+			class += " deprecated"
+		}
+		dash := ""
+		if b.Ghost {
+			class, dash = class+" ghost", ` stroke-dasharray="8,5"`
 		}
-		fmt.Fprintf(buf, `<path id="block-%s" class="%s" d="%s" fill="%s" stroke="#333" stroke-width="2" stroke-linejoin="round" transform="rotate(%.3f %.2f %.2f)"/>`,
-			styles.EscapeXML(b.ID), class, path, grey, rot, b.CX, b.CY)
+		fmt.Fprintf(buf, `<path id="block-%s" class="%s" d="%s" fill="%s" stroke="#333" stroke-width="2" stroke-linejoin="round"%s%s transform="rotate(%.3f %.2f %.2f)"/>`,
+			styles.EscapeXML(b.ID), class, path, grey, dash, styles.Faded(b), rot, b.CX, b.CY)
 	})
 	buf.WriteByte('\n')
 
@@ -64,9 +80,18 @@ func (h *HandDrawn) RenderBlock(buf *bytes.Buffer, b styles.Block) {
 		fmt.Fprintf(buf, `  <path class="block-texture" d="%s" fill="url(#brittleTexture)" style="pointer-events: none;" transform="rotate(%.3f %.2f %.2f)"/>`+"\n",
 			path, rot, b.CX, b.CY)
 	}
+	if b.Vulnerable {
+		fmt.Fprintf(buf, `  <path class="block-vuln" d="%s" fill="url(#vulnHatch)" stroke="%s" stroke-width="3" stroke-linejoin="round" style="pointer-events: none;" transform="rotate(%.3f %.2f %.2f)"/>`+"\n",
+			path, vulnColor, rot, b.CX, b.CY)
+	}
 }
 
 func (h *HandDrawn) RenderEdge(buf *bytes.Buffer, e styles.Edge) {
+	if e.Back {
+		fmt.Fprintf(buf, `  <path class="back-edge" d="%s" fill="none" stroke="%s" stroke-width="2.5" stroke-dasharray="3,6" stroke-linecap="round"/>`+"\n",
+			styles.BackEdgePath(e), styles.BackEdgeColor)
+		return
+	}
 	path := curvedEdge(e.X1, e.Y1, e.X2, e.Y2)
 	fmt.Fprintf(buf, `  <path class="edge" d="%s" fill="none" stroke="#333" stroke-width="2.5" stroke-dasharray="8,5" stroke-linecap="round"/>`+"\n", path)
 }
@@ -77,14 +102,14 @@ func (h *HandDrawn) RenderText(buf *bytes.Buffer, b styles.Block) {
 	if rotate {
 		size = styles.FontSizeRotated(b)
 	}
-	grey := greyForID(b.ID)
+	grey := fillFor(b)
 
 	textW, textH := float64(len(b.ID))*size*textWidthRatio, size*textHeightRatio
 	if rotate {
 		textW, textH = textH, textW
 	}
 
-	fmt.Fprintf(buf, `  <g class="block-text" data-block="%s">`+"\n", styles.EscapeXML(b.ID))
+	fmt.Fprintf(buf, `  <g class="block-text" data-block="%s"%s>`+"\n", styles.EscapeXML(b.ID), styles.Faded(b))
 	styles.WrapURL(buf, b.URL, func() {
 		fmt.Fprintf(buf, `    <rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="%s"/>`+"\n",
 			b.CX-textW/2, b.CY-textH/2, textW, textH, grey)
@@ -96,6 +121,9 @@ func (h *HandDrawn) RenderText(buf *bytes.Buffer, b styles.Block) {
 			fmt.Fprintf(buf, `    <text x="%.2f" y="%.2f" text-anchor="middle" dominant-baseline="middle" font-family="%s" font-size="%.1f" fill="#333">%s</text>`+"\n",
 				b.CX, b.CY, fontFamily, size, styles.EscapeXML(b.ID))
 		}
+		if b.Deprecated {
+			styles.Strike(buf, b, textW, textH, rotate)
+		}
 	})
 	buf.WriteString("  </g>\n")
 }
@@ -106,8 +134,12 @@ func (h *HandDrawn) RenderPopup(buf *bytes.Buffer, b styles.Block) {
 		return
 	}
 
+	memLines := memberLines(p.Members)
 	descLines := wrapText(p.Description, charsPerLine)
 	numDescLines := max(1, len(descLines))
+	if p.Description == "" && len(memLines) > 0 {
+		descLines, numDescLines = nil, 0
+	}
 
 	hasStats := p.Stars > 0 || p.LastCommit != "" || p.LastRelease != ""
 	hasWarning := p.Archived || p.Brittle
@@ -120,7 +152,18 @@ func (h *HandDrawn) RenderPopup(buf *bytes.Buffer, b styles.Block) {
 		}
 	}
 
-	height := float64(numDescLines+statsRows)*popupLineHeight + popupPadding
+	advLines := advisoryLines(p.Advisories)
+	scoreLine := scorecardLine(p.Scorecard)
+	fundLine := fundingLine(p.Funding)
+	deprLine := deprecationLine(p.Deprecation)
+	extraRows := 0
+	for _, line := range []string{deprLine, scoreLine, fundLine} {
+		if line != "" {
+			extraRows++
+		}
+	}
+
+	height := float64(numDescLines+len(memLines)+statsRows+extraRows+len(advLines))*popupLineHeight + popupPadding
 	path := wobbledRect(0, 0, popupWidth, height, h.seed, b.ID+"_popup")
 
 	fmt.Fprintf(buf, `  <g class="popup" data-for="%s" visibility="hidden">`+"\n", styles.EscapeXML(b.ID))
@@ -133,6 +176,12 @@ func (h *HandDrawn) RenderPopup(buf *bytes.Buffer, b styles.Block) {
 		textY += popupLineHeight
 	}
 
+	for _, line := range memLines {
+		fmt.Fprintf(buf, `    <text x="%.1f" y="%.1f" font-family="%s" font-size="%.0f" fill="#444">%s</text>`+"\n",
+			popupTextX, textY, fontFamily, popupTextSize, styles.EscapeXML(line))
+		textY += popupLineHeight
+	}
+
 	if hasStats {
 		statsStartY := textY
 		rightY := statsStartY
@@ -161,11 +210,123 @@ func (h *HandDrawn) RenderPopup(buf *bytes.Buffer, b styles.Block) {
 			fmt.Fprintf(buf, `    <text x="%.1f" y="%.1f" text-anchor="middle" dominant-baseline="middle" font-family="%s" font-size="%.0f" fill="#222" font-weight="bold">★ %s</text>`+"\n",
 				leftCenterX, starsCenterY, fontFamily, popupStarSize, formatNumber(p.Stars))
 		}
+		textY += popupLineHeight * float64(statsRows)
+	}
+
+	if deprLine != "" {
+		fmt.Fprintf(buf, `    <text x="%.1f" y="%.1f" font-family="%s" font-size="%.0f" fill="%s">%s</text>`+"\n",
+			popupTextX, textY, fontFamily, popupTextSize, styles.DeprecatedColor, styles.EscapeXML(deprLine))
+		textY += popupLineHeight
+	}
+
+	if scoreLine != "" {
+		color := "#444"
+		if p.Scorecard.Score < 5 {
+			color = vulnColor
+		}
+		fmt.Fprintf(buf, `    <text x="%.1f" y="%.1f" font-family="%s" font-size="%.0f" fill="%s">%s</text>`+"\n",
+			popupTextX, textY, fontFamily, popupTextSize, color, styles.EscapeXML(scoreLine))
+		textY += popupLineHeight
+	}
+
+	if fundLine != "" {
+		fmt.Fprintf(buf, `    <text x="%.1f" y="%.1f" font-family="%s" font-size="%.0f" fill="#444">%s</text>`+"\n",
+			popupTextX, textY, fontFamily, popupTextSize, styles.EscapeXML(fundLine))
+		textY += popupLineHeight
+	}
+
+	for _, line := range advLines {
+		fmt.Fprintf(buf, `    <text x="%.1f" y="%.1f" font-family="%s" font-size="%.0f" fill="%s">%s</text>`+"\n",
+			popupTextX, textY, fontFamily, popupTextSize, vulnColor, styles.EscapeXML(line))
+		textY += popupLineHeight
 	}
 
 	buf.WriteString("  </g>\n")
 }
 
+func advisoryLines(advisories []styles.Advisory) []string {
+	var lines []string
+	for i, a := range advisories {
+		if i == maxAdvisories {
+			lines = append(lines, fmt.Sprintf("+%d more advisories", len(advisories)-maxAdvisories))
+			break
+		}
+		line := "⚠ " + a.ID
+		if a.Severity != "" {
+			line += " (" + strings.ToLower(a.Severity) + ")"
+		}
+		if len(a.Fixed) > 0 {
+			line += " fixed in " + strings.Join(a.Fixed, ", ")
+		}
+		if r := []rune(line); len(r) > charsPerLine {
+			line = string(r[:charsPerLine-1]) + "…"
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// memberLines lists a composite block's packages, cut short after
+// maxMemberLines.
+func memberLines(members []string) []string {
+	if len(members) == 0 {
+		return nil
+	}
+	lines := wrapText(fmt.Sprintf("▦ %d packages: %s", len(members), strings.Join(members, ", ")), charsPerLine)
+	if len(lines) > maxMemberLines {
+		lines = lines[:maxMemberLines]
+		lines[maxMemberLines-1] += " …"
+	}
+	return lines
+}
+
+func scorecardLine(sc *styles.Scorecard) string {
+	if sc == nil {
+		return ""
+	}
+	line := fmt.Sprintf("scorecard %.1f/10", sc.Score)
+	if len(sc.Weak) > 0 {
+		line += " · weak: " + strings.Join(sc.Weak, ", ")
+	}
+	if r := []rune(line); len(r) > charsPerLine {
+		line = string(r[:charsPerLine-1]) + "…"
+	}
+	return line
+}
+
+func deprecationLine(reason string) string {
+	if reason == "" {
+		return ""
+	}
+	line := "⊘ " + reason
+	if r := []rune(line); len(r) > charsPerLine {
+		line = string(r[:charsPerLine-1]) + "…"
+	}
+	return line
+}
+
+func fundingLine(urls []string) string {
+	if len(urls) == 0 {
+		return ""
+	}
+	short := make([]string, len(urls))
+	for i, u := range urls {
+		short[i] = strings.TrimPrefix(strings.TrimPrefix(u, "https://"), "http://")
+	}
+	line := "♥ fund: " + strings.Join(short, ", ")
+	if r := []rune(line); len(r) > charsPerLine {
+		line = string(r[:charsPerLine-1]) + "…"
+	}
+	return line
+}
+
+func fillFor(b styles.Block) string {
+	if b.Fill != "" {
+		return b.Fill
+	}
+	return greyForID(b.ID)
+}
+
 func formatNumber(n int) string {
 	switch {
 	case n >= 1_000_000:
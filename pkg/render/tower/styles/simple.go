@@ -10,6 +10,7 @@ const (
 	cornerRatioDivisor = 3.0
 	textWidthRatio     = 0.6
 	textHeightRatio    = 1.2
+	vulnerableColor    = "#c0392b"
 )
 
 type Simple struct{}
@@ -18,14 +19,30 @@ func (Simple) RenderDefs(*bytes.Buffer) {}
 
 func (Simple) RenderBlock(buf *bytes.Buffer, b Block) {
 	radius := min(maxCornerRadius, b.W/cornerRatioDivisor, b.H/cornerRatioDivisor)
+	class, stroke, width := "block", "#333", 1
+	if b.Vulnerable {
+		class, stroke, width = "block vulnerable", vulnerableColor, 3
+	}
+	if b.Deprecated {
+		class += " deprecated"
+	}
+	dash := ""
+	if b.Ghost {
+		class, dash = class+" ghost", ` stroke-dasharray="6,4"`
+	}
 	WrapURL(buf, b.URL, func() {
-		fmt.Fprintf(buf, `<rect id="block-%s" class="block" x="%.2f" y="%.2f" width="%.2f" height="%.2f" rx="%.1f" ry="%.1f" fill="white" stroke="#333" stroke-width="1"/>`,
-			EscapeXML(b.ID), b.X, b.Y, b.W, b.H, radius, radius)
+		fmt.Fprintf(buf, `<rect id="block-%s" class="%s" x="%.2f" y="%.2f" width="%.2f" height="%.2f" rx="%.1f" ry="%.1f" fill="%s" stroke="%s" stroke-width="%d"%s%s/>`,
+			EscapeXML(b.ID), class, b.X, b.Y, b.W, b.H, radius, radius, simpleFill(b), stroke, width, dash, Faded(b))
 	})
 	buf.WriteByte('\n')
 }
 
 func (Simple) RenderEdge(buf *bytes.Buffer, e Edge) {
+	if e.Back {
+		fmt.Fprintf(buf, `  <path class="back-edge" d="%s" fill="none" stroke="%s" stroke-width="2" stroke-dasharray="2,4"/>`+"\n",
+			BackEdgePath(e), BackEdgeColor)
+		return
+	}
 	fmt.Fprintf(buf, `  <line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="#333" stroke-width="1.5" stroke-dasharray="6,4"/>`+"\n",
 		e.X1, e.Y1, e.X2, e.Y2)
 }
@@ -42,10 +59,10 @@ func (Simple) RenderText(buf *bytes.Buffer, b Block) {
 		textW, textH = textH, textW
 	}
 
-	fmt.Fprintf(buf, `  <g class="block-text" data-block="%s">`+"\n", EscapeXML(b.ID))
+	fmt.Fprintf(buf, `  <g class="block-text" data-block="%s"%s>`+"\n", EscapeXML(b.ID), Faded(b))
 	WrapURL(buf, b.URL, func() {
-		fmt.Fprintf(buf, `    <rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="white"/>`+"\n",
-			b.CX-textW/2, b.CY-textH/2, textW, textH)
+		fmt.Fprintf(buf, `    <rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="%s"/>`+"\n",
+			b.CX-textW/2, b.CY-textH/2, textW, textH, simpleFill(b))
 
 		if rotate {
 			fmt.Fprintf(buf, `    <text x="%.2f" y="%.2f" text-anchor="middle" dominant-baseline="middle" font-family="Times,serif" font-size="%.1f" fill="#333" transform="rotate(-90 %.2f %.2f)">%s</text>`+"\n",
@@ -54,8 +71,18 @@ func (Simple) RenderText(buf *bytes.Buffer, b Block) {
 			fmt.Fprintf(buf, `    <text x="%.2f" y="%.2f" text-anchor="middle" dominant-baseline="middle" font-family="Times,serif" font-size="%.1f" fill="#333">%s</text>`+"\n",
 				b.CX, b.CY, size, EscapeXML(b.ID))
 		}
+		if b.Deprecated {
+			Strike(buf, b, textW, textH, rotate)
+		}
 	})
 	buf.WriteString("  </g>\n")
 }
 
 func (Simple) RenderPopup(*bytes.Buffer, Block) {}
+
+func simpleFill(b Block) string {
+	if b.Fill != "" {
+		return b.Fill
+	}
+	return "white"
+}
@@ -17,6 +17,10 @@ type Block struct {
 	URL        string
 	Popup      *PopupData
 	Brittle    bool
+	Vulnerable bool
+	Deprecated bool
+	Ghost      bool   // removed in a diff; drawn faded and dashed
+	Fill       string // overrides the style's own fill when set
 }
 
 type PopupData struct {
@@ -27,9 +31,31 @@ type PopupData struct {
 	Maintainers int
 	Archived    bool
 	Brittle     bool
+	Advisories  []Advisory
+	Scorecard   *Scorecard
+	Funding     []string
+	Deprecation string
+	Members     []string // packages a grouped block stands for
+}
+
+type Advisory struct {
+	ID       string
+	Severity string
+	Fixed    []string
+}
+
+type Scorecard struct {
+	Score float64
+	Weak  []string // checks scoring below 5, weakest first
+}
+
+type LegendEntry struct {
+	Label string
+	Color string
 }
 
 type Edge struct {
 	FromID, ToID   string
 	X1, Y1, X2, Y2 float64
+	Back           bool // removed to break a cycle
 }
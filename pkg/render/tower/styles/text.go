@@ -13,6 +13,10 @@ const (
 	fontSizeMin      = 8.0
 	fontSizeMax      = 24.0
 	rotateSizeDampen = 0.75
+
+	DeprecatedColor = "#7d3c98"
+	BackEdgeColor   = "#e67e22"
+	ghostOpacity    = 0.4
 )
 
 func FontSize(b Block) float64        { return fontSizeFor(b.W, b.H, len(b.ID)) }
@@ -49,3 +53,32 @@ func WrapURL(buf *bytes.Buffer, url string, fn func()) {
 		buf.WriteString("</a>")
 	}
 }
+
+// Strike draws a line through a block's label of the given extent, marking
+// a package its registry has deprecated, yanked or abandoned.
+func Strike(buf *bytes.Buffer, b Block, textW, textH float64, rotate bool) {
+	x1, y1, x2, y2 := b.CX-textW/2, b.CY, b.CX+textW/2, b.CY
+	if rotate {
+		x1, y1, x2, y2 = b.CX, b.CY-textH/2, b.CX, b.CY+textH/2
+	}
+	fmt.Fprintf(buf, `    <line class="strike" x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="%s" stroke-width="2.5" stroke-linecap="round"/>`+"\n",
+		x1, y1, x2, y2, DeprecatedColor)
+}
+
+// Faded returns the attribute that ghosts the shapes of a block removed in
+// a diff, or nothing for other blocks.
+func Faded(b Block) string {
+	if !b.Ghost {
+		return ""
+	}
+	return fmt.Sprintf(` opacity="%.1f"`, ghostOpacity)
+}
+
+// BackEdgePath bows an edge that closes a dependency cycle to one side, so
+// it stands apart from the straight edges of the stack.
+func BackEdgePath(e Edge) string {
+	mx, my := (e.X1+e.X2)/2, (e.Y1+e.Y2)/2
+	dx, dy := e.X2-e.X1, e.Y2-e.Y1
+	cx, cy := mx-dy*0.3, my+dx*0.3
+	return fmt.Sprintf("M %.2f %.2f Q %.2f %.2f %.2f %.2f", e.X1, e.Y1, cx, cy, e.X2, e.Y2)
+}
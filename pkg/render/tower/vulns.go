@@ -0,0 +1,66 @@
+package tower
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+	"github.com/matzehuels/stacktower/pkg/integrations/osv"
+	"github.com/matzehuels/stacktower/pkg/render/tower/styles"
+)
+
+func IsVulnerable(n *dag.Node) bool {
+	return len(Advisories(n)) > 0
+}
+
+// Advisories returns the node's known advisories, most severe first. It
+// accepts both the provider's native form and the one decoded from JSON.
+func Advisories(n *dag.Node) []styles.Advisory {
+	if n == nil || n.Meta == nil {
+		return nil
+	}
+
+	var entries []map[string]any
+	switch v := n.Meta["vulnerabilities"].(type) {
+	case []map[string]any:
+		entries = v
+	case []any:
+		for _, e := range v {
+			if m, ok := e.(map[string]any); ok {
+				entries = append(entries, m)
+			}
+		}
+	}
+
+	advisories := make([]styles.Advisory, 0, len(entries))
+	for _, e := range entries {
+		a := styles.Advisory{}
+		a.ID, _ = e["id"].(string)
+		a.Severity, _ = e["severity"].(string)
+		a.Fixed = asStrings(e["fixed"])
+		if a.ID != "" {
+			advisories = append(advisories, a)
+		}
+	}
+	slices.SortStableFunc(advisories, func(a, b styles.Advisory) int {
+		return cmp.Compare(osv.SeverityRank(b.Severity), osv.SeverityRank(a.Severity))
+	})
+	return advisories
+}
+
+func asStrings(v any) []string {
+	switch val := v.(type) {
+	case []string:
+		return val
+	case []any:
+		out := make([]string, 0, len(val))
+		for _, s := range val {
+			if str, ok := s.(string); ok {
+				out = append(out, str)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
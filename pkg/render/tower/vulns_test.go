@@ -0,0 +1,46 @@
+package tower
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+)
+
+func TestAdvisories(t *testing.T) {
+	native := &dag.Node{ID: "pkg", Meta: dag.Metadata{
+		"vulnerabilities": []map[string]any{
+			{"id": "GHSA-low", "severity": "LOW"},
+			{"id": "GHSA-crit", "severity": "CRITICAL", "fixed": []string{"2.0.0"}},
+		},
+	}}
+
+	var decoded dag.Metadata
+	data, _ := json.Marshal(native.Meta)
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, n := range map[string]*dag.Node{"native": native, "json": {ID: "pkg", Meta: decoded}} {
+		got := Advisories(n)
+		if len(got) != 2 {
+			t.Fatalf("%s: expected 2 advisories, got %d", name, len(got))
+		}
+		if got[0].ID != "GHSA-crit" {
+			t.Errorf("%s: expected most severe first, got %s", name, got[0].ID)
+		}
+		if len(got[0].Fixed) != 1 || got[0].Fixed[0] != "2.0.0" {
+			t.Errorf("%s: expected fixed [2.0.0], got %v", name, got[0].Fixed)
+		}
+		if !IsVulnerable(n) {
+			t.Errorf("%s: expected vulnerable", name)
+		}
+	}
+
+	if IsVulnerable(&dag.Node{ID: "pkg", Meta: dag.Metadata{"repo_stars": 10}}) {
+		t.Error("expected node without advisories to be safe")
+	}
+	if IsVulnerable(nil) {
+		t.Error("expected nil node to be safe")
+	}
+}
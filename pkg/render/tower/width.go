@@ -8,20 +8,28 @@ import (
 
 const eps = 1e-9
 
+// WidthWeight scales every block by its package's weight, relative to the
+// others in its row. Widths still flow between rows as usual; the weight is
+// applied to the flowed share, once per block, so it does not compound down
+// a chain of dependencies.
+type WidthWeight func(n *dag.Node) float64
+
 func ComputeWidths(g *dag.DAG, orders map[int][]string, frameWidth float64) map[string]float64 {
+	return computeWidths(g, orders, frameWidth, nil)
+}
+
+func ComputeWidthsBottomUp(g *dag.DAG, orders map[int][]string, frameWidth float64) map[string]float64 {
+	return computeWidthsBottomUp(g, orders, frameWidth, nil)
+}
+
+func computeWidths(g *dag.DAG, orders map[int][]string, frameWidth float64, weight WidthWeight) map[string]float64 {
 	rows := g.RowIDs()
 	if len(rows) == 0 {
 		return nil
 	}
 
 	widths := make(map[string]float64, g.NodeCount())
-
-	if topRow := orders[0]; len(topRow) > 0 {
-		unit := frameWidth / float64(len(topRow))
-		for _, id := range topRow {
-			widths[id] = unit
-		}
-	}
+	seedRow(orders[0], frameWidth, widths)
 
 	maxRow := rows[len(rows)-1]
 	for r := 0; r < maxRow; r++ {
@@ -56,10 +64,10 @@ func ComputeWidths(g *dag.DAG, orders map[int][]string, frameWidth float64) map[
 			}
 		}
 	}
-	return widths
+	return weighRows(g, orders, frameWidth, weight, widths)
 }
 
-func ComputeWidthsBottomUp(g *dag.DAG, orders map[int][]string, frameWidth float64) map[string]float64 {
+func computeWidthsBottomUp(g *dag.DAG, orders map[int][]string, frameWidth float64, weight WidthWeight) map[string]float64 {
 	rows := g.RowIDs()
 	if len(rows) == 0 {
 		return nil
@@ -69,12 +77,7 @@ func ComputeWidthsBottomUp(g *dag.DAG, orders map[int][]string, frameWidth float
 	maxRow := rows[len(rows)-1]
 
 	// Start from bottom: sinks get equal width
-	if bottomRow := orders[maxRow]; len(bottomRow) > 0 {
-		unit := frameWidth / float64(len(bottomRow))
-		for _, id := range bottomRow {
-			widths[id] = unit
-		}
-	}
+	seedRow(orders[maxRow], frameWidth, widths)
 
 	// Propagate upward: parent width = sum of children's contributions
 	for r := maxRow - 1; r >= 0; r-- {
@@ -117,5 +120,49 @@ func ComputeWidthsBottomUp(g *dag.DAG, orders map[int][]string, frameWidth float
 		}
 	}
 
+	return weighRows(g, orders, frameWidth, weight, widths)
+}
+
+func seedRow(ids []string, frameWidth float64, widths map[string]float64) {
+	for _, id := range ids {
+		widths[id] = frameWidth / float64(len(ids))
+	}
+}
+
+// weighRows scales each flowed width by its block's weight and refills
+// the row, leaving the flow itself untouched.
+func weighRows(g *dag.DAG, orders map[int][]string, frameWidth float64, weight WidthWeight, flow map[string]float64) map[string]float64 {
+	if weight == nil {
+		return flow
+	}
+	widths := make(map[string]float64, len(flow))
+	for _, row := range orders {
+		var sum float64
+		for _, id := range row {
+			widths[id] = flow[id] * max(weight(weightNode(g, id)), eps)
+			sum += widths[id]
+		}
+		if sum <= eps {
+			continue
+		}
+		for _, id := range row {
+			widths[id] *= frameWidth / sum
+		}
+	}
 	return widths
 }
+
+// weightNode resolves subdividers to the node they stand in for, since
+// only real packages carry metadata.
+func weightNode(g *dag.DAG, id string) *dag.Node {
+	n, ok := g.Node(id)
+	if !ok {
+		return &dag.Node{ID: id}
+	}
+	if n.MasterID != "" {
+		if m, ok := g.Node(n.MasterID); ok {
+			return m
+		}
+	}
+	return n
+}
@@ -52,6 +52,15 @@ func (pi *packageInfo) ToMetadata() map[string]any {
 	if pi.Author != "" {
 		m["author"] = pi.Author
 	}
+	if len(pi.Funding) > 0 {
+		m["funding"] = pi.Funding
+	}
+	if pi.Deprecated != "" {
+		m["deprecated"] = pi.Deprecated
+	}
+	if len(pi.Releases) > 0 {
+		m["releases"] = pi.Releases
+	}
 	return m
 }
 
@@ -69,5 +78,6 @@ func (pi *packageInfo) ToRepoInfo() *source.RepoInfo {
 		ProjectURLs:  urls,
 		HomePage:     pi.HomePage,
 		ManifestFile: "package.json",
+		Ecosystem:    "npm",
 	}
 }
@@ -0,0 +1,186 @@
+package source
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Provenance keys, added to node metadata when metadata providers are
+// configured.
+const (
+	RegistrySource = "registry"    // the package registry's own metadata
+	SourcesKey     = "_sources"    // metadata key to the provider whose value was kept
+	OverriddenKey  = "_overridden" // metadata key to providers whose differing value lost
+	FailedKey      = "_failed"     // provider to the error it returned
+)
+
+// Precedence ranks providers per metadata key, highest first. The "*" entry
+// applies to keys without a rule of their own. A name without a host also
+// matches host-qualified providers, so "gitea" covers "gitea:codeberg.org".
+// Providers a rule leaves out rank below those it names, in the order they
+// were configured, with the registry ahead of every provider.
+type Precedence map[string][]string
+
+func (p Precedence) rule(key string) []string {
+	if r, ok := p[key]; ok {
+		return r
+	}
+	return p["*"]
+}
+
+// Merger combines metadata from several sources according to a Precedence
+// and records where each value came from.
+type Merger struct {
+	rules      Precedence
+	order      []string
+	meta       map[string]any
+	sources    map[string]string
+	overridden map[string][]string
+	failed     map[string]string
+}
+
+// NewMerger returns a Merger that ranks sources the rules leave out by
+// their position in order.
+func NewMerger(rules Precedence, order ...string) *Merger {
+	return &Merger{
+		rules:      rules,
+		order:      order,
+		meta:       make(map[string]any),
+		sources:    make(map[string]string),
+		overridden: make(map[string][]string),
+		failed:     make(map[string]string),
+	}
+}
+
+// Add merges the values supplied by src. Provenance recorded by a nested
+// Merger, such as a composite provider's, is carried over.
+func (m *Merger) Add(src string, values map[string]any) {
+	nested := stringMap(values[SourcesKey])
+	for k, v := range values {
+		if isProvenanceKey(k) || v == nil || v == "" {
+			continue
+		}
+		from := src
+		if s, ok := nested[k]; ok {
+			from = s
+		}
+		m.set(k, v, from)
+	}
+	for k, losers := range stringLists(values[OverriddenKey]) {
+		m.overridden[k] = appendUnique(m.overridden[k], losers...)
+	}
+	for p, msg := range stringMap(values[FailedKey]) {
+		m.failed[p] = msg
+	}
+}
+
+// Fail records that src could not supply metadata.
+func (m *Merger) Fail(src string, err error) {
+	m.failed[src] = err.Error()
+}
+
+func (m *Merger) set(key string, v any, src string) {
+	cur, ok := m.sources[key]
+	if !ok || cur == src {
+		m.meta[key], m.sources[key] = v, src
+		return
+	}
+
+	old, loser := m.meta[key], src
+	if m.rank(key, src) < m.rank(key, cur) {
+		m.meta[key], m.sources[key] = v, src
+		loser = cur
+	}
+	if !reflect.DeepEqual(old, v) {
+		m.overridden[key] = appendUnique(m.overridden[key], loser)
+	}
+}
+
+func (m *Merger) rank(key, src string) int {
+	rule := m.rules.rule(key)
+	if i := slices.IndexFunc(rule, func(name string) bool { return matchSource(name, src) }); i >= 0 {
+		return i
+	}
+	if i := slices.Index(m.order, src); i >= 0 {
+		return len(rule) + i
+	}
+	return len(rule) + len(m.order)
+}
+
+// Values returns the merged metadata without provenance.
+func (m *Merger) Values() map[string]any {
+	return m.meta
+}
+
+// Result returns the merged metadata with its provenance keys.
+func (m *Merger) Result() map[string]any {
+	out := make(map[string]any, len(m.meta)+3)
+	maps.Copy(out, m.meta)
+	if len(m.sources) > 0 {
+		out[SourcesKey] = m.sources
+	}
+	if len(m.overridden) > 0 {
+		out[OverriddenKey] = m.overridden
+	}
+	if len(m.failed) > 0 {
+		out[FailedKey] = m.failed
+	}
+	return out
+}
+
+func matchSource(name, src string) bool {
+	return name == src || strings.HasPrefix(src, name+":")
+}
+
+func isProvenanceKey(k string) bool {
+	return k == SourcesKey || k == OverriddenKey || k == FailedKey
+}
+
+func appendUnique(list []string, items ...string) []string {
+	for _, s := range items {
+		if !slices.Contains(list, s) {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// stringMap accepts both the native form and the one decoded from JSON.
+func stringMap(v any) map[string]string {
+	switch val := v.(type) {
+	case map[string]string:
+		return val
+	case map[string]any:
+		out := make(map[string]string, len(val))
+		for k, s := range val {
+			if str, ok := s.(string); ok {
+				out[k] = str
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+func stringLists(v any) map[string][]string {
+	switch val := v.(type) {
+	case map[string][]string:
+		return val
+	case map[string]any:
+		out := make(map[string][]string, len(val))
+		for k, list := range val {
+			items, _ := list.([]any)
+			for _, s := range items {
+				if str, ok := s.(string); ok {
+					out[k] = append(out[k], str)
+				}
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
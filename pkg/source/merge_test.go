@@ -0,0 +1,81 @@
+package source
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestMerger_DefaultOrder(t *testing.T) {
+	m := NewMerger(nil, RegistrySource, "github", "gitlab")
+	m.Add("gitlab", map[string]any{"repo_stars": 10, "repo_owner": "bob"})
+	m.Add(RegistrySource, map[string]any{"license": "MIT"})
+	m.Add("github", map[string]any{"repo_stars": 500, "license": "Apache-2.0", "repo_language": ""})
+
+	got := m.Result()
+	if got["repo_stars"] != 500 || got["repo_owner"] != "bob" {
+		t.Errorf("expected github's stars and gitlab's owner, got %v", got)
+	}
+	if got["license"] != "MIT" {
+		t.Errorf("expected the registry's license to win, got %v", got["license"])
+	}
+	if _, ok := got["repo_language"]; ok {
+		t.Error("empty values should be skipped")
+	}
+
+	sources := got[SourcesKey].(map[string]string)
+	if sources["repo_stars"] != "github" || sources["license"] != RegistrySource {
+		t.Errorf("unexpected sources %v", sources)
+	}
+	overridden := got[OverriddenKey].(map[string][]string)
+	if !slices.Equal(overridden["repo_stars"], []string{"gitlab"}) || !slices.Equal(overridden["license"], []string{"github"}) {
+		t.Errorf("unexpected overridden %v", overridden)
+	}
+}
+
+func TestMerger_Precedence(t *testing.T) {
+	rules := Precedence{
+		"repo_stars": {"gitea", "gitlab"},
+		"*":          {"gitlab"},
+	}
+	m := NewMerger(rules, RegistrySource, "github", "gitlab", "gitea:codeberg.org")
+	m.Add("github", map[string]any{"repo_stars": 1, "repo_owner": "a"})
+	m.Add("gitlab", map[string]any{"repo_stars": 2, "repo_owner": "b"})
+	m.Add("gitea:codeberg.org", map[string]any{"repo_stars": 3, "repo_owner": "c"})
+
+	got := m.Values()
+	if got["repo_stars"] != 3 {
+		t.Errorf("expected gitea to match the host-qualified provider, got %v", got["repo_stars"])
+	}
+	if got["repo_owner"] != "b" {
+		t.Errorf("expected the '*' rule to prefer gitlab, got %v", got["repo_owner"])
+	}
+}
+
+func TestMerger_SameValueIsNotAConflict(t *testing.T) {
+	m := NewMerger(nil, "github", "gitlab")
+	m.Add("github", map[string]any{"repo_topics": []string{"web"}})
+	m.Add("gitlab", map[string]any{"repo_topics": []string{"web"}})
+
+	if _, ok := m.Result()[OverriddenKey]; ok {
+		t.Error("agreeing providers should not be reported as overridden")
+	}
+}
+
+func TestMerger_NestedProvenance(t *testing.T) {
+	inner := NewMerger(nil, "github", "gitlab")
+	inner.Add("github", map[string]any{"repo_stars": 5})
+	inner.Fail("gitlab", errors.New("rate limited"))
+
+	outer := NewMerger(nil, RegistrySource, "composite")
+	outer.Add(RegistrySource, map[string]any{"version": "1.0"})
+	outer.Add("composite", inner.Result())
+
+	got := outer.Result()
+	if got[SourcesKey].(map[string]string)["repo_stars"] != "github" {
+		t.Errorf("expected nested source to be kept, got %v", got[SourcesKey])
+	}
+	if got[FailedKey].(map[string]string)["gitlab"] != "rate limited" {
+		t.Errorf("expected nested failure to be kept, got %v", got[FailedKey])
+	}
+}
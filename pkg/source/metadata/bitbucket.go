@@ -0,0 +1,36 @@
+package metadata
+
+import (
+	"context"
+	"time"
+
+	"github.com/matzehuels/stacktower/pkg/integrations/bitbucket"
+	"github.com/matzehuels/stacktower/pkg/source"
+)
+
+type Bitbucket struct {
+	client *bitbucket.Client
+}
+
+func NewBitbucket(token string, cacheTTL time.Duration) (*Bitbucket, error) {
+	c, err := bitbucket.NewClient(token, cacheTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &Bitbucket{c}, nil
+}
+
+func (b *Bitbucket) Name() string { return "bitbucket" }
+
+func (b *Bitbucket) Enrich(ctx context.Context, repo *source.RepoInfo, refresh bool) (map[string]any, error) {
+	workspace, name, ok := bitbucket.ExtractURL(repo.ProjectURLs, repo.HomePage)
+	if !ok {
+		return nil, nil
+	}
+
+	m, err := b.client.Fetch(ctx, workspace, name, refresh)
+	if err != nil {
+		return nil, err
+	}
+	return repoMeta(m), nil
+}
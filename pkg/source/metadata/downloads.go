@@ -0,0 +1,66 @@
+package metadata
+
+import (
+	"context"
+	"time"
+
+	"github.com/matzehuels/stacktower/pkg/integrations/npm"
+	"github.com/matzehuels/stacktower/pkg/integrations/pypi"
+	"github.com/matzehuels/stacktower/pkg/source"
+)
+
+// Downloads adds monthly download counts for registries that publish them
+// separately from package metadata. crates.io and RubyGems totals already
+// arrive with the package itself.
+type Downloads struct {
+	npm        *npm.Client
+	pypi       *pypi.Client
+	pypiCounts pypi.DownloadCounts
+}
+
+// NewDownloads queries the npm downloads API and pypistats.org. If
+// pypiExport names a BigQuery export file, PyPI counts are read from it
+// instead.
+func NewDownloads(cacheTTL time.Duration, pypiExport string) (*Downloads, error) {
+	n, err := npm.NewClient(cacheTTL)
+	if err != nil {
+		return nil, err
+	}
+	d := &Downloads{npm: n}
+	if pypiExport != "" {
+		if d.pypiCounts, err = pypi.LoadDownloadCounts(pypiExport); err != nil {
+			return nil, err
+		}
+		return d, nil
+	}
+	if d.pypi, err = pypi.NewClient(cacheTTL); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *Downloads) Name() string { return "downloads" }
+
+func (d *Downloads) Enrich(ctx context.Context, repo *source.RepoInfo, refresh bool) (map[string]any, error) {
+	var (
+		n   int
+		err error
+	)
+	switch {
+	case repo.Ecosystem == "npm":
+		n, err = d.npm.FetchDownloads(ctx, repo.Name, refresh)
+	case repo.Ecosystem == "PyPI" && d.pypiCounts != nil:
+		var ok bool
+		if n, ok = d.pypiCounts.Get(repo.Name); !ok {
+			return nil, nil
+		}
+	case repo.Ecosystem == "PyPI":
+		n, err = d.pypi.FetchDownloads(ctx, repo.Name, refresh)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{DownloadsMonthly: n}, nil
+}
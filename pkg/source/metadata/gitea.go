@@ -0,0 +1,39 @@
+package metadata
+
+import (
+	"context"
+	"time"
+
+	"github.com/matzehuels/stacktower/pkg/integrations/gitea"
+	"github.com/matzehuels/stacktower/pkg/source"
+)
+
+// Gitea enriches packages hosted on a single Gitea or Forgejo instance,
+// Codeberg by default. Only URLs on that host are matched, so the token is
+// never sent elsewhere.
+type Gitea struct {
+	client *gitea.Client
+}
+
+func NewGitea(baseURL, token string, cacheTTL time.Duration) (*Gitea, error) {
+	c, err := gitea.NewClient(baseURL, token, cacheTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &Gitea{c}, nil
+}
+
+func (g *Gitea) Name() string { return "gitea:" + g.client.Host() }
+
+func (g *Gitea) Enrich(ctx context.Context, repo *source.RepoInfo, refresh bool) (map[string]any, error) {
+	owner, name, ok := g.client.ExtractURL(repo.ProjectURLs, repo.HomePage)
+	if !ok {
+		return nil, nil
+	}
+
+	m, err := g.client.Fetch(ctx, owner, name, refresh)
+	if err != nil {
+		return nil, err
+	}
+	return repoMeta(m), nil
+}
@@ -2,8 +2,10 @@ package metadata
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/matzehuels/stacktower/pkg/integrations"
 	"github.com/matzehuels/stacktower/pkg/integrations/github"
 	"github.com/matzehuels/stacktower/pkg/source"
 )
@@ -23,12 +25,7 @@ func NewGitHub(token string, cacheTTL time.Duration) (*GitHub, error) {
 func (g *GitHub) Name() string { return "github" }
 
 func (g *GitHub) Enrich(ctx context.Context, repo *source.RepoInfo, refresh bool) (map[string]any, error) {
-	owner, name, ok := github.ExtractURL(repo.ProjectURLs, repo.HomePage)
-
-	if !ok && repo.ManifestFile != "" {
-		owner, name, ok = g.client.SearchPackageRepo(ctx, repo.Name, repo.ManifestFile)
-	}
-
+	owner, name, ok := g.locate(ctx, repo)
 	if !ok {
 		return nil, nil
 	}
@@ -37,7 +34,49 @@ func (g *GitHub) Enrich(ctx context.Context, repo *source.RepoInfo, refresh bool
 	if err != nil {
 		return nil, err
 	}
+	return repoMeta(m), nil
+}
+
+// EnrichBatch fetches every located repository through the client's
+// GraphQL batching, so a large graph costs a few dozen requests instead of
+// three per package.
+func (g *GitHub) EnrichBatch(ctx context.Context, repos []*source.RepoInfo, refresh bool) ([]map[string]any, error) {
+	refs := make([]github.RepoRef, len(repos))
+	var located []github.RepoRef
+	for i, repo := range repos {
+		if owner, name, ok := g.locate(ctx, repo); ok {
+			refs[i] = github.RepoRef{Owner: owner, Repo: name}
+			located = append(located, refs[i])
+		}
+	}
+
+	metrics, err := g.client.FetchBatch(ctx, located, refresh)
+	results := make([]map[string]any, len(repos))
+	for i, ref := range refs {
+		if ref.Owner == "" {
+			continue
+		}
+		if m, ok := metrics[ref]; ok {
+			results[i] = repoMeta(m)
+		} else if err != nil {
+			results[i] = map[string]any{source.FailedKey: map[string]string{
+				g.Name(): fmt.Sprintf("%s/%s not fetched", ref.Owner, ref.Repo),
+			}}
+		}
+	}
+	return results, err
+}
+
+func (g *GitHub) locate(ctx context.Context, repo *source.RepoInfo) (owner, name string, ok bool) {
+	owner, name, ok = github.ExtractURL(repo.ProjectURLs, repo.HomePage)
+	if !ok && repo.ManifestFile != "" {
+		owner, name, ok = g.client.SearchPackageRepo(ctx, repo.Name, repo.ManifestFile)
+	}
+	return owner, name, ok
+}
 
+// repoMeta maps repository metrics from any forge onto the schema keys.
+func repoMeta(m *integrations.RepoMetrics) map[string]any {
 	result := map[string]any{
 		RepoURL:      m.RepoURL,
 		RepoOwner:    m.Owner,
@@ -48,6 +87,12 @@ func (g *GitHub) Enrich(ctx context.Context, repo *source.RepoInfo, refresh bool
 	if m.Language != "" {
 		result[RepoLanguage] = m.Language
 	}
+	if m.License != "" && m.License != "NOASSERTION" {
+		result[RepoLicense] = m.License
+	}
+	if len(m.Funding) > 0 {
+		result[RepoFunding] = m.Funding
+	}
 	if len(m.Topics) > 0 {
 		result[RepoTopics] = m.Topics
 	}
@@ -59,11 +104,20 @@ func (g *GitHub) Enrich(ctx context.Context, repo *source.RepoInfo, refresh bool
 	}
 	if len(m.Contributors) > 0 {
 		maintainers := make([]string, len(m.Contributors))
+		contributions := make([]int, len(m.Contributors))
 		for i, c := range m.Contributors {
 			maintainers[i] = c.Login
+			contributions[i] = c.Contributions
 		}
 		result[RepoMaintainers] = maintainers
+		result[RepoContributions] = contributions
 	}
-
-	return result, nil
+	if m.OpenIssues+m.ClosedIssues > 0 {
+		result[RepoOpenIssues] = m.OpenIssues
+		result[RepoClosedIssues] = m.ClosedIssues
+	}
+	if m.IssueCloseDays > 0 {
+		result[RepoIssueClose] = m.IssueCloseDays
+	}
+	return result
 }
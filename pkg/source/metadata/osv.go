@@ -0,0 +1,78 @@
+package metadata
+
+import (
+	"context"
+	"time"
+
+	"github.com/matzehuels/stacktower/pkg/integrations/osv"
+	"github.com/matzehuels/stacktower/pkg/source"
+)
+
+// OSV annotates packages with known advisories, either from the OSV API
+// or from a local database dump for offline use.
+type OSV struct {
+	client *osv.Client
+	db     *osv.DB
+}
+
+func NewOSV(cacheTTL time.Duration) (*OSV, error) {
+	c, err := osv.NewClient(cacheTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &OSV{client: c}, nil
+}
+
+func NewOSVFromDB(path string) (*OSV, error) {
+	db, err := osv.LoadDB(path)
+	if err != nil {
+		return nil, err
+	}
+	return &OSV{db: db}, nil
+}
+
+func (o *OSV) Name() string { return "osv" }
+
+func (o *OSV) Enrich(ctx context.Context, repo *source.RepoInfo, refresh bool) (map[string]any, error) {
+	if repo.Ecosystem == "" || repo.Version == "" {
+		return nil, nil
+	}
+
+	var advisories []osv.Advisory
+	if o.db != nil {
+		advisories = o.db.Query(repo.Ecosystem, repo.Name, repo.Version)
+	} else {
+		var err error
+		if advisories, err = o.client.Query(ctx, repo.Ecosystem, repo.Name, repo.Version, refresh); err != nil {
+			return nil, err
+		}
+	}
+	if len(advisories) == 0 {
+		return nil, nil
+	}
+
+	vulns := make([]map[string]any, len(advisories))
+	worst := ""
+	for i, a := range advisories {
+		v := map[string]any{"id": a.ID}
+		if a.Severity != "" {
+			v["severity"] = a.Severity
+		}
+		if a.Summary != "" {
+			v["summary"] = a.Summary
+		}
+		if len(a.Fixed) > 0 {
+			v["fixed"] = a.Fixed
+		}
+		vulns[i] = v
+		if osv.SeverityRank(a.Severity) > osv.SeverityRank(worst) {
+			worst = a.Severity
+		}
+	}
+
+	result := map[string]any{Vulnerabilities: vulns}
+	if worst != "" {
+		result[VulnSeverity] = worst
+	}
+	return result, nil
+}
@@ -0,0 +1,203 @@
+package metadata
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/matzehuels/stacktower/pkg/source"
+)
+
+const (
+	PluginPrefix = "stacktower-provider-"
+
+	pluginTimeout  = 30 * time.Second
+	pluginShutdown = 5 * time.Second
+)
+
+// Plugin is a metadata provider backed by an external executable, for
+// in-house data such as owning team or service tier. The executable is
+// started once and sent one JSON request per line on stdin, and answers each
+// with one JSON line on stdout:
+//
+//	→ {"name":"requests","version":"2.31.0","ecosystem":"PyPI","project_urls":{...},"homepage":"...","refresh":false}
+//	← {"metadata":{"team":"platform","tier":1}}
+//	← {"error":"no owner recorded"}
+//
+// Requests are sent one at a time. An empty metadata object adds nothing,
+// and whatever the plugin writes to stderr is passed through.
+type Plugin struct {
+	name string
+	path string
+
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+	err    error // once the process has failed, every later call returns this
+}
+
+type pluginRequest struct {
+	Name        string            `json:"name"`
+	Version     string            `json:"version"`
+	Ecosystem   string            `json:"ecosystem"`
+	ProjectURLs map[string]string `json:"project_urls,omitempty"`
+	HomePage    string            `json:"homepage,omitempty"`
+	Refresh     bool              `json:"refresh"`
+}
+
+type pluginResponse struct {
+	Metadata map[string]any `json:"metadata"`
+	Error    string         `json:"error"`
+}
+
+// NewPlugin locates a plugin executable. A bare name is looked up on PATH
+// as stacktower-provider-<name>; anything containing a path separator is
+// used as is.
+func NewPlugin(name string) (*Plugin, error) {
+	if strings.ContainsRune(name, '/') || strings.ContainsRune(name, filepath.Separator) {
+		base := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+		return &Plugin{name: strings.TrimPrefix(base, PluginPrefix), path: name}, nil
+	}
+	path, err := exec.LookPath(PluginPrefix + name)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", name, err)
+	}
+	return &Plugin{name: name, path: path}, nil
+}
+
+func (p *Plugin) Name() string { return "plugin:" + p.name }
+
+func (p *Plugin) Enrich(ctx context.Context, repo *source.RepoInfo, refresh bool) (map[string]any, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.start(); err != nil {
+		return nil, err
+	}
+
+	line, err := json.Marshal(pluginRequest{
+		Name:        repo.Name,
+		Version:     repo.Version,
+		Ecosystem:   repo.Ecosystem,
+		ProjectURLs: repo.ProjectURLs,
+		HomePage:    repo.HomePage,
+		Refresh:     refresh,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.stdin.Write(append(line, '\n')); err != nil {
+		return nil, p.fail(err)
+	}
+
+	resp, err := p.read(ctx)
+	if err != nil {
+		return nil, p.fail(err)
+	}
+	if resp.Error != "" {
+		return nil, errors.New(resp.Error)
+	}
+	return resp.Metadata, nil
+}
+
+func (p *Plugin) start() error {
+	if p.err != nil || p.cmd != nil {
+		return p.err
+	}
+
+	cmd := exec.Command(p.path)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		p.err = fmt.Errorf("start %s: %w", p.path, err)
+		return p.err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		stdin.Close()
+		p.err = fmt.Errorf("start %s: %w", p.path, err)
+		return p.err
+	}
+	if err := cmd.Start(); err != nil {
+		p.err = fmt.Errorf("start %s: %w", p.path, err)
+		return p.err
+	}
+	p.cmd, p.stdin, p.stdout = cmd, stdin, bufio.NewReader(stdout)
+	return nil
+}
+
+func (p *Plugin) read(ctx context.Context) (*pluginResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, pluginTimeout)
+	defer cancel()
+
+	type result struct {
+		line []byte
+		err  error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		line, err := p.stdout.ReadBytes('\n')
+		ch <- result{line, err}
+	}()
+
+	select {
+	case r := <-ch:
+		if errors.Is(r.err, io.EOF) {
+			return nil, errors.New("exited without answering")
+		}
+		if r.err != nil {
+			return nil, r.err
+		}
+		var resp pluginResponse
+		if err := json.Unmarshal(r.line, &resp); err != nil {
+			return nil, fmt.Errorf("invalid response: %w", err)
+		}
+		return &resp, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// fail stops a plugin that broke the protocol, so a hung or crashed process
+// costs one timeout rather than one per package.
+func (p *Plugin) fail(err error) error {
+	p.err = err
+	_ = p.cmd.Process.Kill()
+	_ = p.cmd.Wait()
+	p.cmd = nil
+	return err
+}
+
+// Close closes the plugin's stdin and waits briefly for it to exit before
+// killing it.
+func (p *Plugin) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cmd == nil {
+		return nil
+	}
+
+	_ = p.stdin.Close()
+	done := make(chan error, 1)
+	go func() { done <- p.cmd.Wait() }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-time.After(pluginShutdown):
+		_ = p.cmd.Process.Kill()
+		err = <-done
+	}
+	p.cmd = nil
+	return err
+}
@@ -0,0 +1,119 @@
+package metadata
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/matzehuels/stacktower/pkg/source"
+)
+
+// TestMain lets the test binary double as a plugin executable.
+func TestMain(m *testing.M) {
+	if os.Getenv("STACKTOWER_FAKE_PLUGIN") == "1" {
+		fakePlugin()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func fakePlugin() {
+	scanner := bufio.NewScanner(os.Stdin)
+	enc := json.NewEncoder(os.Stdout)
+	for scanner.Scan() {
+		var req pluginRequest
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			os.Exit(2)
+		}
+		switch req.Name {
+		case "unknown":
+			enc.Encode(pluginResponse{Error: "no owner recorded"})
+		case "crash":
+			os.Exit(1)
+		case "garbage":
+			fmt.Println("not json")
+		default:
+			enc.Encode(pluginResponse{Metadata: map[string]any{
+				"team": "platform", "ecosystem_seen": req.Ecosystem,
+			}})
+		}
+	}
+}
+
+func newFakePlugin(t *testing.T) *Plugin {
+	t.Helper()
+	t.Setenv("STACKTOWER_FAKE_PLUGIN", "1")
+	p, err := NewPlugin(os.Args[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { p.Close() })
+	return p
+}
+
+func TestPlugin_Enrich(t *testing.T) {
+	p := newFakePlugin(t)
+	ctx := context.Background()
+
+	for range 3 {
+		meta, err := p.Enrich(ctx, &source.RepoInfo{Name: "requests", Ecosystem: "PyPI"}, false)
+		if err != nil {
+			t.Fatalf("Enrich: %v", err)
+		}
+		if meta["team"] != "platform" || meta["ecosystem_seen"] != "PyPI" {
+			t.Errorf("unexpected metadata %v", meta)
+		}
+	}
+
+	if _, err := p.Enrich(ctx, &source.RepoInfo{Name: "unknown"}, false); err == nil || err.Error() != "no owner recorded" {
+		t.Errorf("expected the plugin's error, got %v", err)
+	}
+	if _, err := p.Enrich(ctx, &source.RepoInfo{Name: "requests"}, false); err != nil {
+		t.Errorf("a reported error should not stop the plugin: %v", err)
+	}
+	if err := p.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+}
+
+func TestPlugin_BrokenProcess(t *testing.T) {
+	for _, name := range []string{"crash", "garbage"} {
+		t.Run(name, func(t *testing.T) {
+			p := newFakePlugin(t)
+			if _, err := p.Enrich(context.Background(), &source.RepoInfo{Name: name}, false); err == nil {
+				t.Fatal("expected an error")
+			}
+			if _, err := p.Enrich(context.Background(), &source.RepoInfo{Name: "requests"}, false); err == nil {
+				t.Error("expected a broken plugin to stay failed")
+			}
+		})
+	}
+}
+
+func TestNewPlugin(t *testing.T) {
+	dir := t.TempDir()
+	exe := dir + "/" + PluginPrefix + "owners"
+	if err := os.WriteFile(exe, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+
+	p, err := NewPlugin("owners")
+	if err != nil {
+		t.Fatalf("NewPlugin: %v", err)
+	}
+	if p.Name() != "plugin:owners" || p.path != exe {
+		t.Errorf("got name %s path %s", p.Name(), p.path)
+	}
+
+	if p, _ := NewPlugin(exe); p.Name() != "plugin:owners" {
+		t.Errorf("expected a path to be named after its executable, got %s", p.Name())
+	}
+	if _, err := NewPlugin("missing"); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("expected lookup error, got %v", err)
+	}
+}
@@ -2,27 +2,41 @@ package metadata
 
 import (
 	"context"
-	"maps"
+	"errors"
+	"fmt"
 
 	"github.com/matzehuels/stacktower/pkg/source"
 )
 
 type Composite struct {
-	providers []source.MetadataProvider
+	providers  []source.MetadataProvider
+	Precedence source.Precedence
 }
 
 func NewComposite(providers ...source.MetadataProvider) *Composite {
-	return &Composite{providers}
+	return &Composite{providers: providers}
 }
 
 func (c *Composite) Name() string { return "composite" }
 
+// Enrich merges its providers' metadata by c.Precedence, recording
+// provenance, and returns what it could gather along with every
+// provider's error.
 func (c *Composite) Enrich(ctx context.Context, repo *source.RepoInfo, refresh bool) (map[string]any, error) {
-	m := make(map[string]any)
+	names := make([]string, len(c.providers))
+	for i, p := range c.providers {
+		names[i] = p.Name()
+	}
+
+	m := source.NewMerger(c.Precedence, names...)
+	var errs []error
 	for _, p := range c.providers {
-		if meta, err := p.Enrich(ctx, repo, refresh); err == nil {
-			maps.Copy(m, meta)
+		meta, err := p.Enrich(ctx, repo, refresh)
+		if err != nil {
+			m.Fail(p.Name(), err)
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
 		}
+		m.Add(p.Name(), meta)
 	}
-	return m, nil
+	return m.Result(), errors.Join(errs...)
 }
@@ -11,4 +11,29 @@ const (
 	RepoLastCommit  = "repo_last_commit"
 	RepoLastRelease = "repo_last_release"
 	RepoLicense     = "repo_license"
+	RepoFunding     = "repo_funding" // FUNDING.yml links and the owner's GitHub Sponsors page
+	Funding         = "funding"      // links declared in the registry (npm funding, PyPI project URLs)
+
+	RepoContributions = "repo_contributions" // commit counts, aligned with RepoMaintainers
+	RepoOpenIssues    = "repo_open_issues"
+	RepoClosedIssues  = "repo_closed_issues"
+	RepoIssueClose    = "repo_issue_close_days" // median days to close recent issues
+
+	Deprecated   = "deprecated" // npm's deprecation message
+	Yanked       = "yanked"     // PyPI and crates.io
+	YankedReason = "yanked_reason"
+	Abandoned    = "abandoned" // Packagist
+	ReplacedBy   = "replaced_by"
+
+	Releases = "releases" // version to release day, from the registry
+
+	Vulnerabilities = "vulnerabilities"
+	VulnSeverity    = "vuln_severity"
+
+	ScorecardScore  = "scorecard_score"  // OpenSSF Scorecard aggregate, 0 to 10
+	ScorecardChecks = "scorecard_checks" // check name to score, 0 to 10
+	ScorecardDate   = "scorecard_date"
+
+	DownloadsTotal   = "downloads"         // all-time, as crates.io and RubyGems report it
+	DownloadsMonthly = "downloads_monthly" // last 30 days
 )
@@ -0,0 +1,80 @@
+package metadata
+
+import (
+	"context"
+	"time"
+
+	"github.com/matzehuels/stacktower/pkg/integrations/github"
+	"github.com/matzehuels/stacktower/pkg/integrations/gitlab"
+	"github.com/matzehuels/stacktower/pkg/integrations/scorecard"
+	"github.com/matzehuels/stacktower/pkg/source"
+)
+
+// Scorecard attaches OpenSSF Scorecard results, either from the public API
+// or from results stored locally. Scorecard covers GitHub and GitLab
+// repositories only.
+type Scorecard struct {
+	client  *scorecard.Client
+	results scorecard.Results
+}
+
+func NewScorecard(cacheTTL time.Duration) (*Scorecard, error) {
+	c, err := scorecard.NewClient(cacheTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &Scorecard{client: c}, nil
+}
+
+func NewScorecardFromFile(path string) (*Scorecard, error) {
+	rs, err := scorecard.LoadResults(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Scorecard{results: rs}, nil
+}
+
+func (s *Scorecard) Name() string { return "scorecard" }
+
+func (s *Scorecard) Enrich(ctx context.Context, repo *source.RepoInfo, refresh bool) (map[string]any, error) {
+	name, ok := scorecardRepo(repo)
+	if !ok {
+		return nil, nil
+	}
+
+	var r *scorecard.Result
+	if s.results != nil {
+		r = s.results.Get(name)
+	} else {
+		var err error
+		if r, err = s.client.Fetch(ctx, name, refresh); err != nil {
+			return nil, err
+		}
+	}
+	if r == nil {
+		return nil, nil
+	}
+
+	// Checks that could not be evaluated score -1 and are left out.
+	checks := make(map[string]int, len(r.Checks))
+	for _, c := range r.Checks {
+		if c.Score >= 0 {
+			checks[c.Name] = c.Score
+		}
+	}
+	result := map[string]any{ScorecardScore: r.Score, ScorecardChecks: checks}
+	if r.Date != "" {
+		result[ScorecardDate] = r.Date
+	}
+	return result, nil
+}
+
+func scorecardRepo(repo *source.RepoInfo) (string, bool) {
+	if owner, name, ok := github.ExtractURL(repo.ProjectURLs, repo.HomePage); ok {
+		return "github.com/" + owner + "/" + name, true
+	}
+	if owner, name, ok := gitlab.ExtractURL(repo.ProjectURLs, repo.HomePage); ok {
+		return "gitlab.com/" + owner + "/" + name, true
+	}
+	return "", false
+}
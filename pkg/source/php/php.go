@@ -51,6 +51,15 @@ func (pi *packageInfo) ToMetadata() map[string]any {
 	if pi.Author != "" {
 		m["author"] = pi.Author
 	}
+	if pi.Abandoned {
+		m["abandoned"] = true
+		if pi.ReplacedBy != "" {
+			m["replaced_by"] = pi.ReplacedBy
+		}
+	}
+	if len(pi.Releases) > 0 {
+		m["releases"] = pi.Releases
+	}
 	return m
 }
 
@@ -68,5 +77,6 @@ func (pi *packageInfo) ToRepoInfo() *source.RepoInfo {
 		ProjectURLs:  urls,
 		HomePage:     pi.HomePage,
 		ManifestFile: "composer.json",
+		Ecosystem:    "Packagist",
 	}
 }
@@ -0,0 +1,82 @@
+package source
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+)
+
+type ProvenanceReport struct {
+	Providers []ProviderSummary   `json:"providers"`
+	Packages  []PackageProvenance `json:"packages"`
+}
+
+type ProviderSummary struct {
+	Name     string `json:"name"`
+	Packages int    `json:"packages"` // packages it supplied at least one value for
+	Values   int    `json:"values"`
+	Failures int    `json:"failures"`
+}
+
+type PackageProvenance struct {
+	Package string            `json:"package"`
+	Fields  []Field           `json:"fields"`
+	Failed  map[string]string `json:"failed,omitempty"`
+}
+
+type Field struct {
+	Key        string   `json:"key"`
+	Value      any      `json:"value"`
+	Source     string   `json:"source"`
+	Overridden []string `json:"overridden,omitempty"`
+}
+
+// Provenance reports which provider supplied each metadata value in g and
+// which providers failed, from the keys Parse records. Packages parsed
+// without metadata providers carry no provenance and are left out.
+func Provenance(g *dag.DAG) ProvenanceReport {
+	var r ProvenanceReport
+	summaries := make(map[string]*ProviderSummary)
+	summary := func(name string) *ProviderSummary {
+		if summaries[name] == nil {
+			summaries[name] = &ProviderSummary{Name: name}
+		}
+		return summaries[name]
+	}
+
+	for _, n := range g.Nodes() {
+		if n.IsSynthetic() || n.Meta == nil {
+			continue
+		}
+		sources := stringMap(n.Meta[SourcesKey])
+		failed := stringMap(n.Meta[FailedKey])
+		if len(sources) == 0 && len(failed) == 0 {
+			continue
+		}
+
+		overridden := stringLists(n.Meta[OverriddenKey])
+		pp := PackageProvenance{Package: n.ID, Failed: failed}
+		supplied := make(map[string]bool)
+		for _, key := range slices.Sorted(maps.Keys(sources)) {
+			src := sources[key]
+			pp.Fields = append(pp.Fields, Field{Key: key, Value: n.Meta[key], Source: src, Overridden: overridden[key]})
+			summary(src).Values++
+			supplied[src] = true
+		}
+		for src := range supplied {
+			summary(src).Packages++
+		}
+		for src := range failed {
+			summary(src).Failures++
+		}
+		r.Packages = append(r.Packages, pp)
+	}
+
+	slices.SortFunc(r.Packages, func(a, b PackageProvenance) int { return cmp.Compare(a.Package, b.Package) })
+	for _, name := range slices.Sorted(maps.Keys(summaries)) {
+		r.Providers = append(r.Providers, *summaries[name])
+	}
+	return r
+}
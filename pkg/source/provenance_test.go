@@ -0,0 +1,57 @@
+package source
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+)
+
+func TestProvenance(t *testing.T) {
+	m := NewMerger(nil, RegistrySource, "github", "gitlab")
+	m.Add(RegistrySource, map[string]any{"version": "1.0", "license": "MIT"})
+	m.Add("github", map[string]any{"repo_stars": 10, "license": "BSD-3-Clause"})
+	m.Fail("gitlab", errors.New("rate limited"))
+
+	// Round-trip through JSON, as the CLI reads graphs from disk.
+	var meta dag.Metadata
+	data, _ := json.Marshal(m.Result())
+	if err := json.Unmarshal(data, &meta); err != nil {
+		t.Fatal(err)
+	}
+
+	g := dag.New(nil)
+	g.AddNode(dag.Node{ID: "pkg", Meta: meta})
+	g.AddNode(dag.Node{ID: "plain", Meta: dag.Metadata{"version": "2.0"}})
+
+	r := Provenance(g)
+	if len(r.Packages) != 1 || r.Packages[0].Package != "pkg" {
+		t.Fatalf("expected only pkg to carry provenance, got %+v", r.Packages)
+	}
+
+	p := r.Packages[0]
+	if len(p.Fields) != 3 || p.Fields[0].Key != "license" {
+		t.Fatalf("expected 3 fields sorted by key, got %+v", p.Fields)
+	}
+	if f := p.Fields[0]; f.Source != RegistrySource || f.Value != "MIT" || len(f.Overridden) != 1 || f.Overridden[0] != "github" {
+		t.Errorf("unexpected license field %+v", f)
+	}
+	if p.Failed["gitlab"] != "rate limited" {
+		t.Errorf("expected gitlab failure, got %v", p.Failed)
+	}
+
+	want := map[string]ProviderSummary{
+		"github":       {Name: "github", Packages: 1, Values: 1},
+		"gitlab":       {Name: "gitlab", Failures: 1},
+		RegistrySource: {Name: RegistrySource, Packages: 1, Values: 2},
+	}
+	if len(r.Providers) != len(want) {
+		t.Fatalf("expected %d providers, got %+v", len(want), r.Providers)
+	}
+	for _, s := range r.Providers {
+		if s != want[s.Name] {
+			t.Errorf("summary for %s = %+v, want %+v", s.Name, s, want[s.Name])
+		}
+	}
+}
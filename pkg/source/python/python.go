@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/matzehuels/stacktower/pkg/dag"
+	"github.com/matzehuels/stacktower/pkg/integrations"
 	"github.com/matzehuels/stacktower/pkg/integrations/pypi"
 	"github.com/matzehuels/stacktower/pkg/source"
 )
@@ -52,6 +53,18 @@ func (pi *packageInfo) ToMetadata() map[string]any {
 	if pi.Author != "" {
 		m["author"] = pi.Author
 	}
+	if funding := integrations.FundingURLs(pi.ProjectURLs); len(funding) > 0 {
+		m["funding"] = funding
+	}
+	if pi.Yanked {
+		m["yanked"] = true
+		if pi.YankedReason != "" {
+			m["yanked_reason"] = pi.YankedReason
+		}
+	}
+	if len(pi.Releases) > 0 {
+		m["releases"] = pi.Releases
+	}
 	return m
 }
 
@@ -62,5 +75,6 @@ func (pi *packageInfo) ToRepoInfo() *source.RepoInfo {
 		ProjectURLs:  pi.ProjectURLs,
 		HomePage:     pi.HomePage,
 		ManifestFile: "pyproject.toml",
+		Ecosystem:    "PyPI",
 	}
 }
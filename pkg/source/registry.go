@@ -3,6 +3,7 @@ package source
 import (
 	"context"
 	"maps"
+	"slices"
 	"sync"
 	"time"
 
@@ -25,20 +26,37 @@ type MetadataProvider interface {
 	Enrich(ctx context.Context, repo *RepoInfo, refresh bool) (map[string]any, error)
 }
 
+// BatchMetadataProvider is implemented by providers that can enrich many
+// packages per request. Parse defers them until the graph is complete and
+// calls EnrichBatch once instead of Enrich per node. Results align with
+// repos; nil entries add nothing, and results may be partial when err is
+// non-nil. An entry holding only FailedKey records a per-package failure.
+type BatchMetadataProvider interface {
+	MetadataProvider
+	EnrichBatch(ctx context.Context, repos []*RepoInfo, refresh bool) ([]map[string]any, error)
+}
+
 type RepoInfo struct {
 	Name         string
 	Version      string
 	ProjectURLs  map[string]string
 	HomePage     string
 	ManifestFile string
+	Ecosystem    string // OSV ecosystem name, e.g. "PyPI" or "crates.io"
 }
 
+// ReleasesKey holds a package's release history, version to release day.
+// Parse drops it unless Options.Releases is set.
+const ReleasesKey = "releases"
+
 type Options struct {
 	MaxDepth          int
 	MaxNodes          int
 	CacheTTL          time.Duration
 	Refresh           bool
 	MetadataProviders []MetadataProvider
+	Precedence        Precedence // per-key provider ranking; by default the registry, then providers in order
+	Releases          bool       // keep each package's release history under ReleasesKey
 	Logger            func(string, ...any)
 }
 
@@ -77,7 +95,8 @@ func Parse[T PackageInfo](ctx context.Context, root string, opts Options, fetch
 		fetch:   fetch,
 		g:       dag.New(nil),
 		visited: make(map[string]bool),
-		meta:    make(map[string]map[string]any),
+		meta:    make(map[string]*Merger),
+		repos:   make(map[string]*RepoInfo),
 		jobs:    make(chan job, numWorkers*2),
 		results: make(chan result[T], numWorkers*2),
 		done:    make(chan struct{}),
@@ -105,7 +124,8 @@ type parser[T PackageInfo] struct {
 
 	g       *dag.DAG
 	visited map[string]bool
-	meta    map[string]map[string]any
+	meta    map[string]*Merger
+	repos   map[string]*RepoInfo // only kept when a batch provider is configured
 
 	jobs    chan job
 	results chan result[T]
@@ -148,6 +168,7 @@ func (p *parser[T]) parse(root string) (*dag.DAG, error) {
 		return nil, rootErr
 	}
 
+	p.enrichBatch()
 	p.applyMetadata()
 	return p.g, nil
 }
@@ -217,12 +238,15 @@ func (p *parser[T]) addNode(r result[T]) {
 	p.nodeCount++
 	p.mu.Unlock()
 
-	meta := enrichMetadata(p.ctx, r.info, p.opts)
-	if len(meta) > 0 {
-		p.mu.Lock()
-		p.meta[r.name] = meta
-		p.mu.Unlock()
+	repo := r.info.ToRepoInfo()
+	meta := enrichMetadata(p.ctx, r.info, repo, p.opts)
+
+	p.mu.Lock()
+	p.meta[r.name] = meta
+	if hasBatchProvider(p.opts.MetadataProviders) {
+		p.repos[r.name] = repo
 	}
+	p.mu.Unlock()
 }
 
 func (p *parser[T]) submitDependencies(r result[T]) {
@@ -269,26 +293,84 @@ func (p *parser[T]) submitDependencies(r result[T]) {
 	}()
 }
 
+func (p *parser[T]) enrichBatch() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.repos) == 0 {
+		return
+	}
+
+	names := slices.Sorted(maps.Keys(p.repos))
+	repos := make([]*RepoInfo, len(names))
+	for i, name := range names {
+		repos[i] = p.repos[name]
+	}
+
+	for _, provider := range p.opts.MetadataProviders {
+		bp, ok := provider.(BatchMetadataProvider)
+		if !ok {
+			continue
+		}
+		results, err := bp.EnrichBatch(p.ctx, repos, p.opts.Refresh)
+		if err != nil {
+			p.opts.Logger("batch enrichment via %s: %v", provider.Name(), err)
+		}
+		for i, enriched := range results {
+			if len(enriched) > 0 {
+				p.meta[names[i]].Add(provider.Name(), enriched)
+			}
+		}
+	}
+}
+
+func hasBatchProvider(providers []MetadataProvider) bool {
+	for _, provider := range providers {
+		if _, ok := provider.(BatchMetadataProvider); ok {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *parser[T]) applyMetadata() {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 	for id, m := range p.meta {
-		if n, ok := p.g.Node(id); ok {
-			n.Meta = m
+		meta := m.Values()
+		if len(p.opts.MetadataProviders) > 0 {
+			meta = m.Result()
+		}
+		if n, ok := p.g.Node(id); ok && len(meta) > 0 {
+			n.Meta = meta
 		}
 	}
 }
 
-func enrichMetadata(ctx context.Context, info PackageInfo, opts Options) map[string]any {
-	m := info.ToMetadata()
-	repo := info.ToRepoInfo()
+func enrichMetadata(ctx context.Context, info PackageInfo, repo *RepoInfo, opts Options) *Merger {
+	order := []string{RegistrySource}
+	for _, provider := range opts.MetadataProviders {
+		order = append(order, provider.Name())
+	}
+
+	m := NewMerger(opts.Precedence, order...)
+	registry := info.ToMetadata()
+	if !opts.Releases {
+		delete(registry, ReleasesKey)
+	}
+	m.Add(RegistrySource, registry)
 	for _, provider := range opts.MetadataProviders {
+		if _, ok := provider.(BatchMetadataProvider); ok {
+			continue
+		}
 		enriched, err := provider.Enrich(ctx, repo, opts.Refresh)
 		if err != nil {
 			opts.Logger("failed to enrich %s via %s: %v", info.GetName(), provider.Name(), err)
-			continue
+			// Composites report their members' failures themselves.
+			if _, nested := enriched[FailedKey]; !nested {
+				m.Fail(provider.Name(), err)
+			}
 		}
-		maps.Copy(m, enriched)
+		m.Add(provider.Name(), enriched)
 	}
 	return m
 }
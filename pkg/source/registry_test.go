@@ -0,0 +1,36 @@
+package source
+
+import (
+	"context"
+	"testing"
+)
+
+type fakeInfo struct{ name string }
+
+func (f fakeInfo) GetName() string           { return f.name }
+func (f fakeInfo) GetVersion() string        { return "1.0" }
+func (f fakeInfo) GetDependencies() []string { return nil }
+func (f fakeInfo) ToRepoInfo() *RepoInfo     { return &RepoInfo{Name: f.name} }
+func (f fakeInfo) ToMetadata() map[string]any {
+	return map[string]any{"version": "1.0", ReleasesKey: map[string]string{"1.0": "2024-01-01"}}
+}
+
+func TestParse_ReleasesOptIn(t *testing.T) {
+	fetch := func(ctx context.Context, name string, refresh bool) (fakeInfo, error) {
+		return fakeInfo{name}, nil
+	}
+
+	for _, keep := range []bool{false, true} {
+		g, err := Parse(context.Background(), "pkg", Options{Releases: keep}, fetch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n, _ := g.Node("pkg")
+		if _, ok := n.Meta[ReleasesKey]; ok != keep {
+			t.Errorf("Releases=%v: got release history %v", keep, n.Meta[ReleasesKey])
+		}
+		if n.Meta["version"] != "1.0" {
+			t.Errorf("Releases=%v: other metadata should be kept, got %v", keep, n.Meta)
+		}
+	}
+}
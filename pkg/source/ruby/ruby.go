@@ -22,14 +22,21 @@ func NewParser(cacheTTL time.Duration) (*Parser, error) {
 }
 
 func (p *Parser) Parse(ctx context.Context, gem string, opts source.Options) (*dag.DAG, error) {
-	return source.Parse(ctx, gem, opts, p.fetch)
+	return source.Parse(ctx, gem, opts, func(ctx context.Context, name string, refresh bool) (*gemInfo, error) {
+		return p.fetch(ctx, name, refresh, opts.Releases)
+	})
 }
 
-func (p *Parser) fetch(ctx context.Context, name string, refresh bool) (*gemInfo, error) {
+// fetch reads a gem and, when asked for, its release history. The history
+// is optional, so failing to fetch it only leaves it out.
+func (p *Parser) fetch(ctx context.Context, name string, refresh, releases bool) (*gemInfo, error) {
 	info, err := p.client.FetchGem(ctx, name, refresh)
 	if err != nil {
 		return nil, err
 	}
+	if releases {
+		info.Releases, _ = p.client.FetchReleases(ctx, name, refresh)
+	}
 	return &gemInfo{info}, nil
 }
 
@@ -55,6 +62,9 @@ func (gi *gemInfo) ToMetadata() map[string]any {
 	if gi.Downloads > 0 {
 		m["downloads"] = gi.Downloads
 	}
+	if len(gi.Releases) > 0 {
+		m["releases"] = gi.Releases
+	}
 	return m
 }
 
@@ -72,5 +82,6 @@ func (gi *gemInfo) ToRepoInfo() *source.RepoInfo {
 		ProjectURLs:  urls,
 		HomePage:     gi.HomepageURI,
 		ManifestFile: "Gemfile",
+		Ecosystem:    "RubyGems",
 	}
 }
@@ -52,6 +52,15 @@ func (ci *crateInfo) ToMetadata() map[string]any {
 	if ci.Downloads > 0 {
 		m["downloads"] = ci.Downloads
 	}
+	if ci.Yanked {
+		m["yanked"] = true
+		if ci.YankMessage != "" {
+			m["yanked_reason"] = ci.YankMessage
+		}
+	}
+	if len(ci.Releases) > 0 {
+		m["releases"] = ci.Releases
+	}
 	return m
 }
 
@@ -69,5 +78,6 @@ func (ci *crateInfo) ToRepoInfo() *source.RepoInfo {
 		ProjectURLs:  urls,
 		HomePage:     ci.HomePage,
 		ManifestFile: "Cargo.toml",
+		Ecosystem:    "crates.io",
 	}
 }
@@ -0,0 +1,70 @@
+package stats
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+)
+
+// betweenness ranks the packages with nonzero betweenness centrality,
+// computed with Brandes' algorithm and normalized by the (n-1)(n-2) ordered
+// pairs of other packages.
+func betweenness(g *dag.DAG, ids []string) []Centrality {
+	n := len(ids)
+	if n < 3 {
+		return nil
+	}
+	index := dag.PosMap(ids)
+	adj := make([][]int, n)
+	for i, id := range ids {
+		for _, c := range g.Children(id) {
+			adj[i] = append(adj[i], index[c])
+		}
+	}
+
+	score := make([]float64, n)
+	sigma := make([]float64, n)
+	dist := make([]int, n)
+	delta := make([]float64, n)
+	preds := make([][]int, n)
+	order := make([]int, 0, n)
+	for s := range n {
+		for v := range n {
+			sigma[v], dist[v], delta[v] = 0, -1, 0
+			preds[v] = preds[v][:0]
+		}
+		sigma[s], dist[s] = 1, 0
+		order = append(order[:0], s)
+		for i := 0; i < len(order); i++ {
+			v := order[i]
+			for _, w := range adj[v] {
+				if dist[w] < 0 {
+					dist[w] = dist[v] + 1
+					order = append(order, w)
+				}
+				if dist[w] == dist[v]+1 {
+					sigma[w] += sigma[v]
+					preds[w] = append(preds[w], v)
+				}
+			}
+		}
+		for i := len(order) - 1; i > 0; i-- {
+			w := order[i]
+			for _, v := range preds[w] {
+				delta[v] += sigma[v] / sigma[w] * (1 + delta[w])
+			}
+			score[w] += delta[w]
+		}
+	}
+
+	var out []Centrality
+	norm := float64((n - 1) * (n - 2))
+	for i, id := range ids {
+		if score[i] > 0 {
+			out = append(out, Centrality{Package: id, Score: score[i] / norm})
+		}
+	}
+	slices.SortStableFunc(out, func(a, b Centrality) int { return cmp.Compare(b.Score, a.Score) })
+	return out
+}
@@ -0,0 +1,93 @@
+package stats
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+)
+
+// dominators ranks the packages by how many others they dominate: those
+// every path from a root runs through them to reach. In an acyclic graph a
+// node's immediate dominator is the nearest common dominator of its
+// parents, so one pass in topological order builds the dominator tree,
+// rooted at a virtual node above all roots. The roots themselves are left
+// out: each trivially dominates whatever only it pulls in.
+func dominators(g *dag.DAG, ids []string) []Dominator {
+	n := len(ids)
+	if n < 2 {
+		return nil
+	}
+	index := dag.PosMap(ids)
+	const root = -1
+	idom := make([]int, n)
+	depth := make([]int, n)
+
+	lca := func(a, b int) int {
+		for a != b {
+			if a == root || b == root {
+				return root
+			}
+			if depth[a] < depth[b] {
+				a, b = b, a
+			}
+			a = idom[a]
+		}
+		return a
+	}
+
+	order := topological(g, ids, index)
+	for _, v := range order {
+		parents := g.Parents(ids[v])
+		if len(parents) == 0 {
+			idom[v], depth[v] = root, 1
+			continue
+		}
+		d := index[parents[0]]
+		for _, p := range parents[1:] {
+			d = lca(d, index[p])
+		}
+		idom[v] = d
+		depth[v] = 1
+		if d != root {
+			depth[v] = depth[d] + 1
+		}
+	}
+
+	cut := make([]int, n)
+	for i := len(order) - 1; i >= 0; i-- {
+		v := order[i]
+		if d := idom[v]; d != root {
+			cut[d] += cut[v] + 1
+		}
+	}
+
+	var out []Dominator
+	for i, id := range ids {
+		if cut[i] > 0 && g.InDegree(id) > 0 {
+			out = append(out, Dominator{Package: id, Cut: cut[i], Share: float64(cut[i]) / float64(n-1)})
+		}
+	}
+	slices.SortStableFunc(out, func(a, b Dominator) int { return cmp.Compare(b.Cut, a.Cut) })
+	return out
+}
+
+func topological(g *dag.DAG, ids []string, index map[string]int) []int {
+	inDegree := make([]int, len(ids))
+	var queue []int
+	for i, id := range ids {
+		inDegree[i] = g.InDegree(id)
+		if inDegree[i] == 0 {
+			queue = append(queue, i)
+		}
+	}
+	for i := 0; i < len(queue); i++ {
+		for _, c := range g.Children(ids[queue[i]]) {
+			j := index[c]
+			if inDegree[j]--; inDegree[j] == 0 {
+				queue = append(queue, j)
+			}
+		}
+	}
+	return queue
+}
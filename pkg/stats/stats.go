@@ -0,0 +1,169 @@
+package stats
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+	"github.com/matzehuels/stacktower/pkg/dag/transform"
+)
+
+// Report summarizes the shape of a dependency graph. Only regular nodes are
+// counted; the rankings hold at most the top entries asked for.
+type Report struct {
+	Nodes  int   `json:"nodes"`
+	Edges  int   `json:"edges"`
+	Roots  int   `json:"roots"`
+	Leaves int   `json:"leaves"`
+	Depth  int   `json:"depth"`  // rows under longest-path layering
+	Widths []int `json:"widths"` // packages per row, top row first
+
+	FanIn  Distribution `json:"fan_in"`
+	FanOut Distribution `json:"fan_out"`
+
+	MostDependedUpon      []Dependents `json:"most_depended_upon"`
+	Betweenness           []Centrality `json:"betweenness"`
+	SinglePointsOfFailure []Dominator  `json:"single_points_of_failure"`
+}
+
+// Distribution summarizes a degree across all packages. Histogram maps each
+// degree to the number of packages having it.
+type Distribution struct {
+	Mean      float64     `json:"mean"`
+	Median    int         `json:"median"`
+	P90       int         `json:"p90"`
+	Max       int         `json:"max"`
+	Histogram map[int]int `json:"histogram"`
+}
+
+type Dependents struct {
+	Package    string `json:"package"`
+	Direct     int    `json:"direct"`
+	Transitive int    `json:"transitive"`
+}
+
+// Centrality is the share of shortest dependency paths between other
+// packages that run through Package.
+type Centrality struct {
+	Package string  `json:"package"`
+	Score   float64 `json:"score"`
+}
+
+// Dominator is a package every path from the roots to Cut other packages
+// runs through: removing it disconnects them.
+type Dominator struct {
+	Package string  `json:"package"`
+	Cut     int     `json:"cut"`
+	Share   float64 `json:"share"` // Cut over all other packages
+}
+
+// Analyze computes the Report for g, keeping the top entries of each
+// ranking, or all of them if top is zero or less. Synthetic nodes are
+// folded away and dependency cycles broken first, on a copy.
+func Analyze(g *dag.DAG, top int) Report {
+	g = fold(g)
+	r := Report{Nodes: g.NodeCount(), Edges: g.EdgeCount()}
+	transform.BreakCycles(g)
+
+	ids := dag.NodeIDs(g.Nodes())
+	slices.Sort(ids)
+
+	r.Roots, r.Leaves = len(g.Sources()), len(g.Sinks())
+	r.Widths = widths(g, ids)
+	r.Depth = len(r.Widths)
+	r.FanIn = distribution(ids, g.InDegree)
+	r.FanOut = distribution(ids, g.OutDegree)
+
+	transitive := transform.AncestorCounts(g)
+	for _, id := range ids {
+		if n := transitive[id]; n > 0 {
+			r.MostDependedUpon = append(r.MostDependedUpon, Dependents{Package: id, Direct: g.InDegree(id), Transitive: n})
+		}
+	}
+	slices.SortStableFunc(r.MostDependedUpon, func(a, b Dependents) int {
+		return cmp.Or(cmp.Compare(b.Transitive, a.Transitive), cmp.Compare(b.Direct, a.Direct))
+	})
+
+	r.Betweenness = betweenness(g, ids)
+	r.SinglePointsOfFailure = dominators(g, ids)
+
+	r.MostDependedUpon = truncate(r.MostDependedUpon, top)
+	r.Betweenness = truncate(r.Betweenness, top)
+	r.SinglePointsOfFailure = truncate(r.SinglePointsOfFailure, top)
+	return r
+}
+
+// fold copies the regular packages of g and the dependencies between them.
+// A normalized graph routes long dependencies through chains of
+// subdividers and some through separators; each is followed down to the
+// packages it leads to, so a graph analyzes the same before and after
+// normalization. A separator shared by several parents links each to all
+// the children below it, as dependency paths do in why.
+func fold(g *dag.DAG) *dag.DAG {
+	out := g.Subgraph(func(n *dag.Node) bool { return !n.IsSynthetic() })
+	for _, n := range out.Nodes() {
+		seen := make(map[string]bool)
+		var stack []string
+		for _, c := range g.Children(n.ID) {
+			if child, _ := g.Node(c); child.IsSynthetic() {
+				stack = append(stack, c)
+			}
+		}
+		for len(stack) > 0 {
+			id := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			if node, _ := g.Node(id); !node.IsSynthetic() {
+				if !slices.Contains(out.Children(n.ID), id) {
+					out.AddEdge(dag.Edge{From: n.ID, To: id})
+				}
+				continue
+			}
+			stack = append(stack, g.Children(id)...)
+		}
+	}
+	return out
+}
+
+func widths(g *dag.DAG, ids []string) []int {
+	var w []int
+	rows := transform.LongestPath{}.Layers(g)
+	for _, id := range ids {
+		row := rows[id]
+		for len(w) <= row {
+			w = append(w, 0)
+		}
+		w[row]++
+	}
+	return w
+}
+
+func distribution(ids []string, degree func(string) int) Distribution {
+	d := Distribution{Histogram: make(map[int]int)}
+	if len(ids) == 0 {
+		return d
+	}
+	degrees := make([]int, len(ids))
+	sum := 0
+	for i, id := range ids {
+		degrees[i] = degree(id)
+		d.Histogram[degrees[i]]++
+		sum += degrees[i]
+	}
+	slices.Sort(degrees)
+	d.Mean = float64(sum) / float64(len(degrees))
+	d.Median = degrees[(len(degrees)-1)/2]
+	d.P90 = degrees[(len(degrees)-1)*9/10]
+	d.Max = degrees[len(degrees)-1]
+	return d
+}
+
+func truncate[T any](s []T, top int) []T {
+	if top > 0 && len(s) > top {
+		return s[:top]
+	}
+	return s
+}
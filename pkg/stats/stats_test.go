@@ -0,0 +1,121 @@
+package stats
+
+import (
+	"math"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/matzehuels/stacktower/pkg/dag"
+	"github.com/matzehuels/stacktower/pkg/dag/transform"
+)
+
+// app depends on web and cli, which both reach util only through core;
+// log hangs off web alone.
+func sample() *dag.DAG {
+	g := dag.New(nil)
+	for _, id := range []string{"app", "web", "cli", "core", "util", "log"} {
+		_ = g.AddNode(dag.Node{ID: id})
+	}
+	_ = g.AddEdge(dag.Edge{From: "app", To: "web"})
+	_ = g.AddEdge(dag.Edge{From: "app", To: "cli"})
+	_ = g.AddEdge(dag.Edge{From: "web", To: "core"})
+	_ = g.AddEdge(dag.Edge{From: "cli", To: "core"})
+	_ = g.AddEdge(dag.Edge{From: "core", To: "util"})
+	_ = g.AddEdge(dag.Edge{From: "web", To: "log"})
+	return g
+}
+
+func TestAnalyze_Shape(t *testing.T) {
+	r := Analyze(sample(), 0)
+	if r.Nodes != 6 || r.Edges != 6 || r.Roots != 1 || r.Leaves != 2 {
+		t.Errorf("counts = %d nodes, %d edges, %d roots, %d leaves", r.Nodes, r.Edges, r.Roots, r.Leaves)
+	}
+	if r.Depth != 4 || !slices.Equal(r.Widths, []int{1, 2, 2, 1}) {
+		t.Errorf("depth = %d, widths = %v", r.Depth, r.Widths)
+	}
+	if r.FanIn.Max != 2 || r.FanIn.Histogram[1] != 4 || r.FanIn.Histogram[0] != 1 {
+		t.Errorf("fan-in = %+v", r.FanIn)
+	}
+	if r.FanOut.Max != 2 || r.FanOut.Median != 1 || math.Abs(r.FanOut.Mean-1) > 1e-9 {
+		t.Errorf("fan-out = %+v", r.FanOut)
+	}
+}
+
+func TestAnalyze_Rankings(t *testing.T) {
+	r := Analyze(sample(), 2)
+
+	want := []Dependents{{"util", 1, 4}, {"core", 2, 3}}
+	if !slices.Equal(r.MostDependedUpon, want) {
+		t.Errorf("most depended upon = %v, want %v", r.MostDependedUpon, want)
+	}
+
+	// core lies on the shortest paths app→util, web→util and cli→util.
+	if len(r.Betweenness) != 2 || r.Betweenness[0].Package != "core" {
+		t.Fatalf("betweenness = %v", r.Betweenness)
+	}
+	if math.Abs(r.Betweenness[0].Score-3.0/20) > 1e-9 {
+		t.Errorf("core betweenness = %f, want %f", r.Betweenness[0].Score, 3.0/20)
+	}
+
+	// web and cli each have another way down to core, so only core and web
+	// (holding log alone) cut anything off.
+	wantSPOF := []Dominator{{"core", 1, 0.2}, {"web", 1, 0.2}}
+	if !slices.Equal(r.SinglePointsOfFailure, wantSPOF) {
+		t.Errorf("single points of failure = %v, want %v", r.SinglePointsOfFailure, wantSPOF)
+	}
+}
+
+func TestDominators_Chain(t *testing.T) {
+	g := dag.New(nil)
+	for _, id := range []string{"a", "b", "c", "d"} {
+		_ = g.AddNode(dag.Node{ID: id})
+	}
+	_ = g.AddEdge(dag.Edge{From: "a", To: "b"})
+	_ = g.AddEdge(dag.Edge{From: "b", To: "c"})
+	_ = g.AddEdge(dag.Edge{From: "c", To: "d"})
+	_ = g.AddEdge(dag.Edge{From: "a", To: "c"})
+	got := Analyze(g, 0).SinglePointsOfFailure
+	want := []Dominator{{"c", 1, 1.0 / 3}}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestAnalyze_SkipsSyntheticAndBreaksCycles(t *testing.T) {
+	g := dag.New(nil)
+	_ = g.AddNode(dag.Node{ID: "a"})
+	_ = g.AddNode(dag.Node{ID: "b"})
+	_ = g.AddNode(dag.Node{ID: "a_sub_1", Kind: dag.NodeKindSubdivider, MasterID: "a"})
+	_ = g.AddEdge(dag.Edge{From: "a", To: "b"})
+	_ = g.AddEdge(dag.Edge{From: "b", To: "a"})
+
+	r := Analyze(g, 0)
+	if r.Nodes != 2 || r.Edges != 2 || r.Depth != 2 {
+		t.Errorf("report = %+v", r)
+	}
+	if len(g.BackEdges()) != 0 {
+		t.Error("Analyze modified its input")
+	}
+}
+
+func TestAnalyze_NormalizedMatchesRaw(t *testing.T) {
+	// tool reaches util across two rows, so normalizing routes that edge
+	// through a subdivider, and extends the short sinks to the bottom row.
+	build := func() *dag.DAG {
+		g := sample()
+		_ = g.AddNode(dag.Node{ID: "tool"})
+		_ = g.AddEdge(dag.Edge{From: "app", To: "tool"})
+		_ = g.AddEdge(dag.Edge{From: "tool", To: "util"})
+		return g
+	}
+	normalized := transform.Normalize(build())
+	if normalized.NodeCount() == build().NodeCount() {
+		t.Fatal("expected normalization to add subdividers")
+	}
+
+	raw, norm := Analyze(build(), 0), Analyze(normalized, 0)
+	if !reflect.DeepEqual(raw, norm) {
+		t.Errorf("normalized graph analyzes differently:\nraw  %+v\nnorm %+v", raw, norm)
+	}
+}
@@ -9,6 +9,9 @@ readonly DEFAULT_MAX_DEPTH=10
 readonly DEFAULT_MAX_NODES=200
 readonly REFRESH=${REFRESH:-false}
 
+# HTTP record/replay: STACKTOWER_HTTP_MODE=record|replay
+export STACKTOWER_HTTP_FIXTURES="${STACKTOWER_HTTP_FIXTURES:-$EXAMPLES_DIR/fixtures}"
+
 # Render dimensions
 readonly RENDER_WIDTH=800
 readonly RENDER_HEIGHT=600